@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatalf("Get(a) missed a fresh entry")
+	}
+	// a was used last, so b is the one evicted
+	_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Errorf("Get(b) should have been evicted")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Errorf("Get(a) returned an expired entry")
+	}
+
+	_ = c.Delete(ctx, "c")
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", c.Len())
+	}
+}
+
+// fakeRedis is a local stand-in that understands the commands RedisStore uses.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func startFakeRedis(t *testing.T) (string, *fakeRedis) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	srv := &fakeRedis{data: map[string]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	return ln.Addr().String(), srv
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	for {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			line, _ = rd.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			b := make([]byte, size+2)
+			if _, err = io.ReadFull(rd, b); err != nil {
+				return
+			}
+			args[i] = string(b[:size])
+		}
+		f.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if v, ok := f.data[args[1]]; ok {
+				_, _ = conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+			} else {
+				_, _ = conn.Write([]byte("$-1\r\n"))
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			_, _ = conn.Write([]byte("+OK\r\n"))
+		case "DEL":
+			for _, k := range args[1:] {
+				delete(f.data, k)
+			}
+			_, _ = conn.Write([]byte(":" + strconv.Itoa(len(args)-1) + "\r\n"))
+		default:
+			_, _ = conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	addr, srv := startFakeRedis(t)
+	ctx := context.Background()
+	s := NewRedisStore(addr, "", 0, "test:")
+	defer s.Close()
+
+	if _, ok, err := s.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v", ok, err)
+	}
+	if err := s.Set(ctx, "k", []byte("value\r\nwith crlf"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, ok := srv.data["test:k"]; !ok {
+		t.Errorf("Set() did not apply the key prefix")
+	}
+	got, ok, err := s.Get(ctx, "k")
+	if err != nil || !ok || string(got) != "value\r\nwith crlf" {
+		t.Errorf("Get(k) = %q, %v, %v", got, ok, err)
+	}
+	if err := s.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := s.Get(ctx, "k"); ok {
+		t.Errorf("Get(k) after Delete() should miss")
+	}
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisStore is a Store backed by a Redis server. It speaks just enough of
+// the RESP protocol for GET, SET PX and DEL over a single connection that is
+// re-dialled after any I/O error.
+type RedisStore struct {
+	Addr     string
+	Password string
+	DB       int
+	Prefix   string
+	Timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+var errRedisNil = errors.New("redis: nil")
+
+func NewRedisStore(addr, password string, db int, prefix string) *RedisStore {
+	return &RedisStore{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+		Prefix:   prefix,
+		Timeout:  time.Second * 2,
+	}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := s.do(ctx, "GET", s.Prefix+key)
+	switch {
+	case errors.Is(err, errRedisNil):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", v)
+	}
+	return b, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", s.Prefix + key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := s.do(ctx, args...)
+	return err
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, s.Prefix+key)
+	}
+	_, err := s.do(ctx, args...)
+	return err
+}
+
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.rd = nil, nil
+	return err
+}
+
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	v, err := s.roundTrip(ctx, args...)
+	if err != nil && !errors.Is(err, errRedisNil) {
+		var replyErr redisError
+		if !errors.As(err, &replyErr) {
+			// the connection state is unknown after an I/O error
+			_ = s.conn.Close()
+			s.conn, s.rd = nil, nil
+		}
+	}
+	return v, err
+}
+
+func (s *RedisStore) dial(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	s.conn, s.rd = conn, bufio.NewReader(conn)
+	if s.Password != "" {
+		if _, err = s.roundTrip(ctx, "AUTH", s.Password); err != nil {
+			_ = s.conn.Close()
+			s.conn, s.rd = nil, nil
+			return err
+		}
+	}
+	if s.DB != 0 {
+		if _, err = s.roundTrip(ctx, "SELECT", strconv.Itoa(s.DB)); err != nil {
+			_ = s.conn.Close()
+			s.conn, s.rd = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) roundTrip(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(s.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = s.conn.SetDeadline(deadline)
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(s.rd)
+}
+
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+		b := make([]byte, n+2)
+		if _, err = io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+		res := make([]interface{}, n)
+		for i := range res {
+			if res[i], err = readReply(rd); err != nil && !errors.Is(err, errRedisNil) {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+	"net/url"
+)
+
+// ClockIn clocks the employee in at clock.Time, now when clock is nil or
+// its Time is empty.
+func (c *Client) ClockIn(ctx context.Context, id string, clock *model.Clock) (*model.AttendanceEvent, error) {
+	return send[*model.AttendanceEvent](ctx, c, http.MethodPost, path("/api/v1/employees/%s/clock-in", id), clockBody(clock))
+}
+
+// ClockOut clocks the employee out at clock.Time, now when clock is nil or
+// its Time is empty.
+func (c *Client) ClockOut(ctx context.Context, id string, clock *model.Clock) (*model.AttendanceEvent, error) {
+	return send[*model.AttendanceEvent](ctx, c, http.MethodPost, path("/api/v1/employees/%s/clock-out", id), clockBody(clock))
+}
+
+func clockBody(clock *model.Clock) interface{} {
+	if clock == nil {
+		return nil
+	}
+	return clock
+}
+
+// GetAttendance lists clock events from from to to (YYYY-MM-DD, inclusive),
+// the current week when both are empty.
+func (c *Client) GetAttendance(ctx context.Context, id, from, to string) ([]*model.AttendanceEvent, error) {
+	return get[[]*model.AttendanceEvent](ctx, c, path("/api/v1/employees/%s/attendance", id), dateRange(from, to))
+}
+
+// GetTimesheet returns the week containing date (YYYY-MM-DD), the current
+// week when date is empty.
+func (c *Client) GetTimesheet(ctx context.Context, id, date string) (*model.Timesheet, error) {
+	query := url.Values{}
+	if date != "" {
+		query.Set("date", date)
+	}
+	return get[*model.Timesheet](ctx, c, path("/api/v1/employees/%s/timesheet", id), query)
+}
+
+// GetHoursReport returns the hours per employee per period (day, week or
+// month) from from to to.
+func (c *Client) GetHoursReport(ctx context.Context, from, to, period string) ([]*model.HoursReportRow, error) {
+	query := dateRange(from, to)
+	if period != "" {
+		query.Set("period", period)
+	}
+	return get[[]*model.HoursReportRow](ctx, c, "/api/v1/reports/hours", query)
+}
+
+func dateRange(from, to string) url.Values {
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	return query
+}
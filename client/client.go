@@ -0,0 +1,166 @@
+// Package client calls the employee API over HTTP. Methods unwrap the
+// GenericResponse envelope and return failures as *Error.
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"employee-golang/model"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	headerNextCursor     = "X-Next-Cursor"
+)
+
+// Client is safe for concurrent use.
+type Client struct {
+	rest *resty.Client
+}
+
+type Option func(*resty.Client)
+
+// WithAPIKey sends key in the X-API-Key header. Requests with a key the API
+// knows are rate limited per key rather than per IP address.
+func WithAPIKey(key string) Option {
+	return func(rest *resty.Client) {
+		rest.SetHeader("X-API-Key", key)
+	}
+}
+
+// WithTimeout bounds each attempt of a request, 30s by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(rest *resty.Client) {
+		rest.SetTimeout(timeout)
+	}
+}
+
+// WithTransport sends requests through transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(rest *resty.Client) {
+		rest.SetTransport(transport)
+	}
+}
+
+// WithRetries retries a request up to count times, waiting an exponential
+// backoff between wait and maxWait. The default is 3 retries between 200ms
+// and 5s; zero disables retries.
+func WithRetries(count int, wait, maxWait time.Duration) Option {
+	return func(rest *resty.Client) {
+		rest.SetRetryCount(count).SetRetryWaitTime(wait).SetRetryMaxWaitTime(maxWait)
+	}
+}
+
+// New returns a client for the API served at baseURL, such as
+// "https://employees.example.com".
+//
+// Requests failing with 429, a 5xx status or a network error are retried,
+// honouring Retry-After. Every request that is not a GET carries an
+// Idempotency-Key, so retrying it is safe where the API stores them.
+func New(baseURL string, options ...Option) *Client {
+	rest := resty.New().
+		SetBaseURL(strings.TrimRight(baseURL, "/")).
+		SetTimeout(30*time.Second).
+		SetHeader("User-Agent", "employee-golang-client").
+		SetRetryCount(3).
+		SetRetryWaitTime(200 * time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Second).
+		AddRetryCondition(retryable).
+		SetRetryAfter(retryAfter)
+	for _, option := range options {
+		option(rest)
+	}
+	return &Client{rest: rest}
+}
+
+func retryable(resp *resty.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= 500
+}
+
+// retryAfter waits as long as a Retry-After header in seconds asks; without
+// one the client falls back to its backoff.
+func retryAfter(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	seconds, err := strconv.Atoi(resp.Header().Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (c *Client) request(ctx context.Context, method string) *resty.Request {
+	rq := c.rest.R().SetContext(ctx)
+	if method != http.MethodGet {
+		rq.SetHeader(headerIdempotencyKey, newIdempotencyKey())
+	}
+	return rq
+}
+
+// call sends a JSON request and unwraps the data of the response envelope.
+func call[T any](ctx context.Context, c *Client, method, path string, query url.Values, body interface{}) (res T, header http.Header, err error) {
+	var envelope model.GenericResponse[T]
+	rq := c.request(ctx, method).SetResult(&envelope)
+	if query != nil {
+		rq.SetQueryParamsFromValues(query)
+	}
+	if body != nil {
+		rq.SetBody(body)
+	}
+	resp, err := rq.Execute(method, path)
+	if err != nil {
+		return res, nil, err
+	}
+	if resp.IsError() {
+		return res, nil, newError(resp)
+	}
+	return envelope.Data, resp.Header(), nil
+}
+
+func get[T any](ctx context.Context, c *Client, path string, query url.Values) (T, error) {
+	res, _, err := call[T](ctx, c, http.MethodGet, path, query, nil)
+	return res, err
+}
+
+func send[T any](ctx context.Context, c *Client, method, path string, body interface{}) (T, error) {
+	res, _, err := call[T](ctx, c, method, path, nil, body)
+	return res, err
+}
+
+// newError reads the message of an error envelope, falling back to the
+// status text for bodies that are not one.
+func newError(resp *resty.Response) *Error {
+	res := &Error{StatusCode: resp.StatusCode(), Message: http.StatusText(resp.StatusCode())}
+	var envelope model.GenericResponse[any]
+	if json.Unmarshal(resp.Body(), &envelope) == nil {
+		res.Status = envelope.Status
+		if envelope.Data != nil {
+			res.Message = fmt.Sprint(envelope.Data)
+		}
+	}
+	return res
+}
+
+func path(format string, ids ...string) string {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = url.PathEscape(id)
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"employee-golang/controller"
+	"employee-golang/middleware"
+	"employee-golang/model"
+	"employee-golang/service"
+	"employee-golang/util"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeEmployeeService struct {
+	service.IEmployeeService
+	mu        sync.Mutex
+	employees map[string]*model.Employee
+}
+
+func (s *fakeEmployeeService) GetEmployeesByStatus(_ context.Context, filter string) ([]*model.Employee, error) {
+	if filter == "retired" {
+		return nil, model.ErrUnknownStatus
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rs []*model.Employee
+	for _, e := range s.employees {
+		if filter == "" || e.Status == filter {
+			rs = append(rs, e)
+		}
+	}
+	return rs, nil
+}
+
+func (s *fakeEmployeeService) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.employees[id]; ok {
+		return e, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *fakeEmployeeService) InsertEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.employees[employee.IdEmployee]; ok {
+		return "Employee already exists", errors.New("employee already exists")
+	}
+	employee.Status = "onboarding"
+	s.employees[employee.IdEmployee] = employee
+	return "Employee was added", nil
+}
+
+func (s *fakeEmployeeService) ChangeStatus(_ context.Context, id string, change *model.StatusChange) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.employees[id]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	if e.Status == "terminated" {
+		return "", model.ErrStatusTransition
+	}
+	e.Status = change.Status
+	return "Employee status was changed", nil
+}
+
+func (s *fakeEmployeeService) DeleteEmployee(_ context.Context, id string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.employees[id]; !ok {
+		return "", sql.ErrNoRows
+	}
+	delete(s.employees, id)
+	return "Employee was deleted", nil
+}
+
+// newTestServer serves the employee routes, checking all traffic against
+// the OpenAPI document.
+func newTestServer(t *testing.T, middlewares ...echo.MiddlewareFunc) (*httptest.Server, *fakeEmployeeService) {
+	employees := &fakeEmployeeService{employees: map[string]*model.Employee{}}
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		employees.employees[id] = &model.Employee{IdEmployee: id, FirstName: "Employee " + id, Status: "active"}
+	}
+	employees.employees["3"].Status = "terminated"
+
+	e := echo.New()
+	e.Validator = &controller.CustomValidator{Validator: util.NewValidator()}
+	e.Use(middlewares...)
+	e.Use(middleware.NewOpenAPIValidation(controller.NewOpenAPISpec(e)))
+	(&controller.Controller{Service: employees}).Routes(e)
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv, employees
+}
+
+func TestClient_Employees(t *testing.T) {
+	srv, _ := newTestServer(t)
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	employee := &model.Employee{IdEmployee: "6", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "555"}
+	if _, err := c.CreateEmployee(ctx, employee); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetEmployee(ctx, "6")
+	if err != nil || got.FirstName != "Jane" || got.Status != "onboarding" {
+		t.Fatalf("GetEmployee() = %v, %v", got, err)
+	}
+
+	_, err = c.CreateEmployee(ctx, employee)
+	var apiErr *Error
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &apiErr) || apiErr.Status != "CONFLICTED" || apiErr.Message != "employee already exists" {
+		t.Errorf("CreateEmployee() error = %v, want a conflict", err)
+	}
+	if _, err = c.GetEmployee(ctx, "9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetEmployee() error = %v, want not found", err)
+	}
+	if _, err = c.ListEmployees(ctx, ListOptions{Statuses: []string{"retired"}}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ListEmployees() error = %v, want a bad request", err)
+	}
+	if _, err = c.ChangeStatus(ctx, "3", &model.StatusChange{Status: "active"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("ChangeStatus() error = %v, want a conflict", err)
+	}
+}
+
+func TestClient_DeleteEmployee(t *testing.T) {
+	srv, employees := newTestServer(t)
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	rs, err := c.DeleteEmployee(ctx, "2")
+	if err != nil || rs != "Employee was deleted" {
+		t.Fatalf("DeleteEmployee() = %q, %v", rs, err)
+	}
+	if _, ok := employees.employees["2"]; ok {
+		t.Error("employee was not deleted")
+	}
+	if _, err = c.DeleteEmployee(ctx, "2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteEmployee() error = %v, want not found", err)
+	}
+}
+
+func TestClient_EmployeeIterator(t *testing.T) {
+	srv, _ := newTestServer(t)
+	c := New(srv.URL)
+
+	page, err := c.ListEmployees(context.Background(), ListOptions{Limit: 2})
+	if err != nil || len(page.Employees) != 2 || page.NextCursor != "2" {
+		t.Fatalf("ListEmployees() = %+v, %v", page, err)
+	}
+
+	var ids []string
+	it := c.Employees(context.Background(), ListOptions{Statuses: []string{"active"}, Limit: 2})
+	for it.Next() {
+		ids = append(ids, it.Employee().IdEmployee)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 4 || ids[0] != "1" || ids[2] != "4" || ids[3] != "5" {
+		t.Errorf("iterated over %v", ids)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	var mu sync.Mutex
+	var failures int
+	keys := map[string]int{}
+	flaky := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			keys[c.Request().Header.Get(headerIdempotencyKey)]++
+			if failures < 2 {
+				failures++
+				if failures == 1 {
+					c.Response().Header().Set("Retry-After", "0")
+					return c.JSON(http.StatusTooManyRequests, model.GenericResponse[any]{Code: 429, Status: "TOO_MANY_REQUESTS", Data: "Rate limit exceeded"})
+				}
+				return c.JSON(http.StatusServiceUnavailable, nil)
+			}
+			return next(c)
+		}
+	}
+	srv, employees := newTestServer(t, flaky)
+	c := New(srv.URL, WithRetries(3, time.Millisecond, 10*time.Millisecond))
+
+	employee := &model.Employee{IdEmployee: "6", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "555"}
+	if _, err := c.CreateEmployee(context.Background(), employee); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := employees.employees["6"]; !ok {
+		t.Error("employee was not created")
+	}
+	if len(keys) != 1 {
+		t.Errorf("retries sent idempotency keys %v, want the same key each time", keys)
+	}
+	for _, n := range keys {
+		if n != 3 {
+			t.Errorf("sent %d attempts, want 3", n)
+		}
+	}
+
+	failures = 0
+	c = New(srv.URL, WithRetries(1, time.Millisecond, 10*time.Millisecond))
+	if _, err := c.GetEmployee(context.Background(), "1"); !errors.Is(err, ErrServer) {
+		t.Errorf("GetEmployee() error = %v, want a server error once retries run out", err)
+	}
+}
+
+type fakeDocumentService struct {
+	service.IDocumentService
+	content map[int64][]byte
+}
+
+func (s *fakeDocumentService) MaxSize() int64 {
+	return 1 << 20
+}
+
+func (s *fakeDocumentService) UploadDocument(_ context.Context, employeeId, fileName string, content io.Reader) (*model.Document, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	id := int64(len(s.content) + 1)
+	s.content[id] = b
+	return &model.Document{IdDocument: id, IdEmployee: employeeId, FileName: fileName, ContentType: "text/plain; charset=utf-8", Size: int64(len(b))}, nil
+}
+
+func (s *fakeDocumentService) OpenDocument(_ context.Context, employeeId string, id int64) (*model.Document, io.ReadCloser, error) {
+	b, ok := s.content[id]
+	if !ok {
+		return nil, nil, sql.ErrNoRows
+	}
+	return &model.Document{IdDocument: id, IdEmployee: employeeId, ContentType: "text/plain; charset=utf-8", Size: int64(len(b))}, io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func TestClient_Documents(t *testing.T) {
+	documents := &fakeDocumentService{content: map[int64][]byte{}}
+	e := echo.New()
+	e.Use(middleware.NewOpenAPIValidation(controller.NewOpenAPISpec(e)))
+	(&controller.DocumentHandler{Service: documents}).Routes(e)
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	document, err := c.UploadDocument(ctx, "1", "contract.txt", strings.NewReader("signed"))
+	if err != nil || document.FileName != "contract.txt" || document.Size != 6 {
+		t.Fatalf("UploadDocument() = %+v, %v", document, err)
+	}
+	content, contentType, err := c.DownloadDocument(ctx, "1", document.IdDocument)
+	if err != nil || string(content) != "signed" || contentType != "text/plain; charset=utf-8" {
+		t.Errorf("DownloadDocument() = %q, %q, %v", content, contentType, err)
+	}
+	if _, _, err = c.DownloadDocument(ctx, "1", 9); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadDocument() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+)
+
+func (c *Client) GetDepartments(ctx context.Context) ([]*model.Department, error) {
+	return get[[]*model.Department](ctx, c, "/api/v1/departments", nil)
+}
+
+func (c *Client) GetDepartment(ctx context.Context, id string) (*model.Department, error) {
+	return get[*model.Department](ctx, c, path("/api/v1/departments/%s", id), nil)
+}
+
+func (c *Client) GetDepartmentEmployees(ctx context.Context, id string) ([]*model.Employee, error) {
+	return get[[]*model.Employee](ctx, c, path("/api/v1/departments/%s/employees", id), nil)
+}
+
+func (c *Client) CreateDepartment(ctx context.Context, department *model.Department) (string, error) {
+	return send[string](ctx, c, http.MethodPost, "/api/v1/departments", department)
+}
+
+func (c *Client) UpdateDepartment(ctx context.Context, department *model.Department) (string, error) {
+	return send[string](ctx, c, http.MethodPut, "/api/v1/departments", department)
+}
+
+func (c *Client) DeleteDepartment(ctx context.Context, id string) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/departments/%s", id), nil)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"employee-golang/model"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// UploadDocument stores content under fileName for the employee. The
+// multipart body is built up front so that it can be sent again on a
+// retry.
+func (c *Client) UploadDocument(ctx context.Context, id, fileName string, content io.Reader) (*model.Document, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var envelope model.GenericResponse[*model.Document]
+	resp, err := c.request(ctx, http.MethodPost).
+		SetHeader("Content-Type", w.FormDataContentType()).
+		SetBody(body.Bytes()).
+		SetResult(&envelope).
+		Post(path("/api/v1/employees/%s/documents", id))
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, newError(resp)
+	}
+	return envelope.Data, nil
+}
+
+func (c *Client) GetDocuments(ctx context.Context, id string) ([]*model.Document, error) {
+	return get[[]*model.Document](ctx, c, path("/api/v1/employees/%s/documents", id), nil)
+}
+
+// DownloadDocument returns the content of a document with its content type.
+func (c *Client) DownloadDocument(ctx context.Context, id string, documentId int64) (content []byte, contentType string, err error) {
+	resp, err := c.request(ctx, http.MethodGet).Get(path("/api/v1/employees/%s/documents/%s", id, strconv.FormatInt(documentId, 10)))
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.IsError() {
+		return nil, "", newError(resp)
+	}
+	return resp.Body(), resp.Header().Get("Content-Type"), nil
+}
+
+func (c *Client) DeleteDocument(ctx context.Context, id string, documentId int64) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/employees/%s/documents/%s", id, strconv.FormatInt(documentId, 10)), nil)
+}
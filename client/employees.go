@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOptions filters and pages a list of employees.
+type ListOptions struct {
+	// Statuses keeps only employees in one of the statuses.
+	Statuses []string
+	// Limit pages the list in id order; zero returns every employee.
+	Limit int
+	// After continues a paged list from a page's NextCursor.
+	After string
+}
+
+// EmployeePage is one page of employees. NextCursor is empty on the last
+// page.
+type EmployeePage struct {
+	Employees  []*model.Employee
+	NextCursor string
+}
+
+func (c *Client) ListEmployees(ctx context.Context, opts ListOptions) (*EmployeePage, error) {
+	query := url.Values{}
+	if len(opts.Statuses) > 0 {
+		query.Set("status", strings.Join(opts.Statuses, ","))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+		if opts.After != "" {
+			query.Set("after", opts.After)
+		}
+	}
+	rs, header, err := call[[]*model.Employee](ctx, c, http.MethodGet, "/api/v1/employees", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &EmployeePage{Employees: rs, NextCursor: header.Get(headerNextCursor)}, nil
+}
+
+// EmployeeIterator walks a list of employees page by page:
+//
+//	it := c.Employees(ctx, client.ListOptions{Limit: 100})
+//	for it.Next() {
+//		employee := it.Employee()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type EmployeeIterator struct {
+	client *Client
+	ctx    context.Context
+	opts   ListOptions
+	page   []*model.Employee
+	pos    int
+	done   bool
+	err    error
+}
+
+// Employees iterates over the employees matching opts, fetching Limit of
+// them at a time, 100 when unset.
+func (c *Client) Employees(ctx context.Context, opts ListOptions) *EmployeeIterator {
+	if opts.Limit <= 0 {
+		opts.Limit = 100
+	}
+	return &EmployeeIterator{client: c, ctx: ctx, opts: opts}
+}
+
+// Next advances to the next employee, fetching the next page when needed.
+// It returns false at the end of the list or on an error.
+func (it *EmployeeIterator) Next() bool {
+	for it.pos >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		page, err := it.client.ListEmployees(it.ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.pos = page.Employees, 0
+		it.opts.After, it.done = page.NextCursor, page.NextCursor == ""
+	}
+	it.pos++
+	return true
+}
+
+func (it *EmployeeIterator) Employee() *model.Employee {
+	return it.page[it.pos-1]
+}
+
+func (it *EmployeeIterator) Err() error {
+	return it.err
+}
+
+func (c *Client) GetEmployee(ctx context.Context, id string) (*model.Employee, error) {
+	return get[*model.Employee](ctx, c, path("/api/v1/employees/%s", id), nil)
+}
+
+func (c *Client) CreateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	return send[string](ctx, c, http.MethodPost, "/api/v1/employees", employee)
+}
+
+func (c *Client) UpdateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	return send[string](ctx, c, http.MethodPut, "/api/v1/employees", employee)
+}
+
+func (c *Client) DeleteEmployee(ctx context.Context, id string) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/employees/%s", id), nil)
+}
+
+func (c *Client) GetDirectReports(ctx context.Context, id string) ([]*model.Employee, error) {
+	return get[[]*model.Employee](ctx, c, path("/api/v1/employees/%s/reports", id), nil)
+}
+
+func (c *Client) GetReportingChain(ctx context.Context, id string) ([]*model.Employee, error) {
+	return get[[]*model.Employee](ctx, c, path("/api/v1/employees/%s/chain", id), nil)
+}
+
+func (c *Client) GetOrgChart(ctx context.Context, id string) (*model.OrgChartNode, error) {
+	return get[*model.OrgChartNode](ctx, c, path("/api/v1/employees/%s/org-chart", id), nil)
+}
+
+func (c *Client) GetSalaryOverrides(ctx context.Context, id string) ([]*model.SalaryOverride, error) {
+	return get[[]*model.SalaryOverride](ctx, c, path("/api/v1/employees/%s/salary-overrides", id), nil)
+}
+
+func (c *Client) GetCompensationHistory(ctx context.Context, id string) ([]*model.Compensation, error) {
+	return get[[]*model.Compensation](ctx, c, path("/api/v1/employees/%s/compensation", id), nil)
+}
+
+// GetCompensationAsOf returns the salary in effect on date (YYYY-MM-DD),
+// today when date is empty.
+func (c *Client) GetCompensationAsOf(ctx context.Context, id, date string) (*model.Compensation, error) {
+	query := url.Values{}
+	if date != "" {
+		query.Set("date", date)
+	}
+	return get[*model.Compensation](ctx, c, path("/api/v1/employees/%s/compensation/as-of", id), query)
+}
+
+func (c *Client) ScheduleCompensation(ctx context.Context, id string, compensation *model.Compensation) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/employees/%s/compensation", id), compensation)
+}
+
+func (c *Client) ChangeStatus(ctx context.Context, id string, change *model.StatusChange) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/employees/%s/status", id), change)
+}
+
+func (c *Client) Rehire(ctx context.Context, id string, rehire *model.Rehire) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/employees/%s/rehire", id), rehire)
+}
+
+func (c *Client) GetStatusHistory(ctx context.Context, id string) ([]*model.StatusChange, error) {
+	return get[[]*model.StatusChange](ctx, c, path("/api/v1/employees/%s/status-history", id), nil)
+}
+
+// SearchEmployees ranks employees against query; limit zero uses the API's
+// default.
+func (c *Client) SearchEmployees(ctx context.Context, query string, limit int) ([]*model.SearchResult, error) {
+	params := url.Values{"q": {query}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	return get[[]*model.SearchResult](ctx, c, "/api/v1/employees/search", params)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of failure an *Error matches with errors.Is.
+var (
+	ErrBadRequest           = errors.New("bad request")
+	ErrNotFound             = errors.New("not found")
+	ErrConflict             = errors.New("conflict")
+	ErrPayloadTooLarge      = errors.New("payload too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrUnprocessable        = errors.New("unprocessable entity")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrServer               = errors.New("server error")
+)
+
+var statusErrors = map[int]error{
+	400: ErrBadRequest,
+	404: ErrNotFound,
+	409: ErrConflict,
+	413: ErrPayloadTooLarge,
+	415: ErrUnsupportedMediaType,
+	422: ErrUnprocessable,
+	429: ErrRateLimited,
+}
+
+// Error is a response with an error status. Status is the API's name for
+// it, such as NOT_FOUND, and Message what the API said went wrong.
+type Error struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("employee api: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	if e.StatusCode >= 500 {
+		return target == ErrServer
+	}
+	return statusErrors[e.StatusCode] == target
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) GetLeaveTypes(ctx context.Context) ([]*model.LeaveType, error) {
+	return get[[]*model.LeaveType](ctx, c, "/api/v1/leave-types", nil)
+}
+
+func (c *Client) GetLeaveType(ctx context.Context, id string) (*model.LeaveType, error) {
+	return get[*model.LeaveType](ctx, c, path("/api/v1/leave-types/%s", id), nil)
+}
+
+func (c *Client) CreateLeaveType(ctx context.Context, leaveType *model.LeaveType) (string, error) {
+	return send[string](ctx, c, http.MethodPost, "/api/v1/leave-types", leaveType)
+}
+
+func (c *Client) UpdateLeaveType(ctx context.Context, leaveType *model.LeaveType) (string, error) {
+	return send[string](ctx, c, http.MethodPut, "/api/v1/leave-types", leaveType)
+}
+
+func (c *Client) DeleteLeaveType(ctx context.Context, id string) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/leave-types/%s", id), nil)
+}
+
+// RequestLeave submits request, returning the requests stored for it: one
+// per calendar year the leave falls in.
+func (c *Client) RequestLeave(ctx context.Context, id string, request *model.LeaveRequest) ([]*model.LeaveRequest, error) {
+	return send[[]*model.LeaveRequest](ctx, c, http.MethodPost, path("/api/v1/employees/%s/leave-requests", id), request)
+}
+
+func (c *Client) GetLeaveRequests(ctx context.Context, id string) ([]*model.LeaveRequest, error) {
+	return get[[]*model.LeaveRequest](ctx, c, path("/api/v1/employees/%s/leave-requests", id), nil)
+}
+
+// GetLeaveBalances returns the balances of year, the current year when year
+// is zero.
+func (c *Client) GetLeaveBalances(ctx context.Context, id string, year int) ([]*model.LeaveBalance, error) {
+	query := url.Values{}
+	if year > 0 {
+		query.Set("year", strconv.Itoa(year))
+	}
+	return get[[]*model.LeaveBalance](ctx, c, path("/api/v1/employees/%s/leave-balances", id), query)
+}
+
+func (c *Client) ApproveLeave(ctx context.Context, requestId int64, decision *model.LeaveDecision) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/leave-requests/%s/approve", strconv.FormatInt(requestId, 10)), decision)
+}
+
+func (c *Client) RejectLeave(ctx context.Context, requestId int64, decision *model.LeaveDecision) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/leave-requests/%s/reject", strconv.FormatInt(requestId, 10)), decision)
+}
+
+func (c *Client) CancelLeave(ctx context.Context, requestId int64) (string, error) {
+	return send[string](ctx, c, http.MethodPost, path("/api/v1/leave-requests/%s/cancel", strconv.FormatInt(requestId, 10)), nil)
+}
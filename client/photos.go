@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// UploadPhoto replaces the employee's photo. The content is read up front
+// so that it can be sent again on a retry.
+func (c *Client) UploadPhoto(ctx context.Context, id string, content io.Reader) (*model.Photo, error) {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	return send[*model.Photo](ctx, c, http.MethodPut, path("/api/v1/employees/%s/photo", id), body)
+}
+
+func (c *Client) GetPhotoMetadata(ctx context.Context, id string) (*model.Photo, error) {
+	return get[*model.Photo](ctx, c, path("/api/v1/employees/%s/photo/metadata", id), nil)
+}
+
+// GetPhoto downloads the original photo, or the thumbnail of the given size
+// when size is above zero, with its content type.
+func (c *Client) GetPhoto(ctx context.Context, id string, size int) (content []byte, contentType string, err error) {
+	rq := c.request(ctx, http.MethodGet)
+	if size > 0 {
+		rq.SetQueryParam("size", strconv.Itoa(size))
+	}
+	resp, err := rq.Get(path("/api/v1/employees/%s/photo", id))
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.IsError() {
+		return nil, "", newError(resp)
+	}
+	return resp.Body(), resp.Header().Get("Content-Type"), nil
+}
+
+func (c *Client) DeletePhoto(ctx context.Context, id string) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/employees/%s/photo", id), nil)
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+)
+
+func (c *Client) GetPositions(ctx context.Context) ([]*model.Position, error) {
+	return get[[]*model.Position](ctx, c, "/api/v1/positions", nil)
+}
+
+func (c *Client) GetPosition(ctx context.Context, id string) (*model.Position, error) {
+	return get[*model.Position](ctx, c, path("/api/v1/positions/%s", id), nil)
+}
+
+func (c *Client) CreatePosition(ctx context.Context, position *model.Position) (string, error) {
+	return send[string](ctx, c, http.MethodPost, "/api/v1/positions", position)
+}
+
+func (c *Client) UpdatePosition(ctx context.Context, position *model.Position) (string, error) {
+	return send[string](ctx, c, http.MethodPut, "/api/v1/positions", position)
+}
+
+func (c *Client) DeletePosition(ctx context.Context, id string) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, path("/api/v1/positions/%s", id), nil)
+}
@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/url"
+)
+
+// GetSalaryReport returns salaries converted to currency, the base currency
+// when currency is empty.
+func (c *Client) GetSalaryReport(ctx context.Context, currency string) (*model.SalaryReport, error) {
+	query := url.Values{}
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+	return get[*model.SalaryReport](ctx, c, "/api/v1/reports/salaries", query)
+}
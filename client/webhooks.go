@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"employee-golang/model"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) GetWebhooks(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	return get[[]*model.WebhookSubscription](ctx, c, "/api/v1/webhooks", nil)
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*model.WebhookSubscription, error) {
+	return get[*model.WebhookSubscription](ctx, c, webhookPath("", id), nil)
+}
+
+// CreateWebhook subscribes to events. The returned subscription carries the
+// signing secret, which is not returned again.
+func (c *Client) CreateWebhook(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	return send[*model.WebhookSubscription](ctx, c, http.MethodPost, "/api/v1/webhooks", subscription)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	return send[*model.WebhookSubscription](ctx, c, http.MethodPut, webhookPath("", subscription.IdSubscription), subscription)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) (string, error) {
+	return send[string](ctx, c, http.MethodDelete, webhookPath("", id), nil)
+}
+
+// RotateWebhookSecret replaces the signing secret and returns the
+// subscription with the new one.
+func (c *Client) RotateWebhookSecret(ctx context.Context, id int64) (*model.WebhookSubscription, error) {
+	return send[*model.WebhookSubscription](ctx, c, http.MethodPost, webhookPath("/secret", id), nil)
+}
+
+// GetWebhookDeliveries lists deliveries newest first, only those in status
+// when it is set and at most limit when it is above zero.
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id int64, status string, limit int) ([]*model.WebhookDelivery, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return get[[]*model.WebhookDelivery](ctx, c, webhookPath("/deliveries", id), query)
+}
+
+func (c *Client) GetWebhookDelivery(ctx context.Context, id, deliveryId int64) (*model.WebhookDelivery, error) {
+	return get[*model.WebhookDelivery](ctx, c, webhookPath("/deliveries/"+strconv.FormatInt(deliveryId, 10), id), nil)
+}
+
+func (c *Client) RedeliverWebhook(ctx context.Context, id, deliveryId int64) (*model.WebhookDelivery, error) {
+	return send[*model.WebhookDelivery](ctx, c, http.MethodPost, webhookPath("/deliveries/"+strconv.FormatInt(deliveryId, 10)+"/redeliver", id), nil)
+}
+
+func webhookPath(suffix string, id int64) string {
+	return "/api/v1/webhooks/" + strconv.FormatInt(id, 10) + suffix
+}
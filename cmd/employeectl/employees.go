@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/client"
+	"employee-golang/model"
+	"employee-golang/service"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errExists = errors.New("employee already exists")
+
+// backend runs the employee commands. Both implementations report an
+// existing employee as errExists so import can fall back to an update.
+type backend interface {
+	ListEmployees(ctx context.Context, statuses []string) ([]*model.Employee, error)
+	GetEmployee(ctx context.Context, id string) (*model.Employee, error)
+	CreateEmployee(ctx context.Context, employee *model.Employee) (string, error)
+	UpdateEmployee(ctx context.Context, employee *model.Employee) (string, error)
+	DeleteEmployee(ctx context.Context, id string) (string, error)
+}
+
+type apiBackend struct {
+	client *client.Client
+}
+
+func (b apiBackend) ListEmployees(ctx context.Context, statuses []string) ([]*model.Employee, error) {
+	var res []*model.Employee
+	it := b.client.Employees(ctx, client.ListOptions{Statuses: statuses, Limit: 500})
+	for it.Next() {
+		res = append(res, it.Employee())
+	}
+	return res, it.Err()
+}
+
+func (b apiBackend) GetEmployee(ctx context.Context, id string) (*model.Employee, error) {
+	return b.client.GetEmployee(ctx, id)
+}
+
+func (b apiBackend) CreateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	rs, err := b.client.CreateEmployee(ctx, employee)
+	if errors.Is(err, client.ErrConflict) {
+		return "", errExists
+	}
+	return rs, err
+}
+
+func (b apiBackend) UpdateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	return b.client.UpdateEmployee(ctx, employee)
+}
+
+func (b apiBackend) DeleteEmployee(ctx context.Context, id string) (string, error) {
+	return b.client.DeleteEmployee(ctx, id)
+}
+
+// databaseBackend goes through the service layer rather than the
+// repositories alone, so the CLI keeps the rules the API enforces and
+// records the same events.
+type databaseBackend struct {
+	service service.IEmployeeService
+}
+
+func (b databaseBackend) ListEmployees(ctx context.Context, statuses []string) ([]*model.Employee, error) {
+	return b.service.GetEmployeesByStatus(ctx, strings.Join(statuses, ","))
+}
+
+func (b databaseBackend) GetEmployee(ctx context.Context, id string) (*model.Employee, error) {
+	rs, err := b.service.GetEmployeeById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("employee %s not found", id)
+	}
+	return rs, err
+}
+
+func (b databaseBackend) CreateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	rs, err := b.service.InsertEmployee(ctx, employee)
+	if err != nil && err.Error() == "employee already exists" {
+		return "", errExists
+	}
+	return rs, err
+}
+
+func (b databaseBackend) UpdateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	return b.service.UpdateEmployee(ctx, employee)
+}
+
+func (b databaseBackend) DeleteEmployee(ctx context.Context, id string) (string, error) {
+	rs, err := b.service.DeleteEmployee(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("employee %s not found", id)
+	}
+	return rs, err
+}
+
+func (c *cli) runEmployees(ctx context.Context, command string, args []string) error {
+	flags := flag.NewFlagSet("employees "+command, flag.ContinueOnError)
+	status := flags.String("status", "", "comma-separated statuses to keep (list, export)")
+	file := flags.String("file", "", `file to read or write, "-" for standard input or output`)
+	update := flags.Bool("update", false, "update employees that already exist (import)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var statuses []string
+	if *status != "" {
+		statuses = strings.Split(*status, ",")
+	}
+
+	b, err := c.backend()
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "list":
+		rs, err := b.ListEmployees(ctx, statuses)
+		if err != nil {
+			return err
+		}
+		return c.printEmployees(rs)
+	case "get":
+		if flags.NArg() != 1 {
+			return errors.New("usage: employees get <id>")
+		}
+		rs, err := b.GetEmployee(ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return c.printEmployees([]*model.Employee{rs})
+	case "create", "update":
+		employee := new(model.Employee)
+		if err := c.readFile(*file, func(r io.Reader) error { return json.NewDecoder(r).Decode(employee) }); err != nil {
+			return err
+		}
+		send := b.CreateEmployee
+		if command == "update" {
+			send = b.UpdateEmployee
+		}
+		rs, err := send(ctx, employee)
+		if err != nil {
+			return err
+		}
+		return c.printMessage(rs)
+	case "delete":
+		if flags.NArg() != 1 {
+			return errors.New("usage: employees delete <id>")
+		}
+		rs, err := b.DeleteEmployee(ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return c.printMessage(rs)
+	case "import":
+		var employees []*model.Employee
+		if err := c.readFile(*file, func(r io.Reader) (err error) {
+			employees, err = readEmployees(r, fileFormat(*file))
+			return err
+		}); err != nil {
+			return err
+		}
+		return c.importEmployees(ctx, b, employees, *update)
+	case "export":
+		rs, err := b.ListEmployees(ctx, statuses)
+		if err != nil {
+			return err
+		}
+		return c.writeFile(*file, func(w io.Writer) error { return writeEmployees(w, fileFormat(*file), rs) })
+	}
+	return fmt.Errorf("unknown employees subcommand %q", command)
+}
+
+// importEmployees creates the employees one by one, going on past failures,
+// and reports the outcome of each.
+func (c *cli) importEmployees(ctx context.Context, b backend, employees []*model.Employee, update bool) error {
+	rows := make([][]string, 0, len(employees))
+	results := make([]map[string]string, 0, len(employees))
+	failed := 0
+	for _, employee := range employees {
+		result := "created"
+		_, err := b.CreateEmployee(ctx, employee)
+		if errors.Is(err, errExists) && update {
+			result = "updated"
+			_, err = b.UpdateEmployee(ctx, employee)
+		}
+		if err != nil {
+			result = "failed: " + err.Error()
+			failed++
+		}
+		rows = append(rows, []string{employee.IdEmployee, result})
+		results = append(results, map[string]string{"idEmployee": employee.IdEmployee, "result": result})
+	}
+	if err := c.print(results, []string{"idEmployee", "result"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d employees failed to import", failed, len(employees))
+	}
+	return nil
+}
+
+// fileFormat is json for .json files and csv otherwise.
+func fileFormat(file string) string {
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return "json"
+	}
+	return "csv"
+}
+
+func (c *cli) readFile(file string, read func(io.Reader) error) error {
+	switch file {
+	case "":
+		return errors.New("-file is required")
+	case "-":
+		return read(c.in)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func (c *cli) writeFile(file string, write func(io.Writer) error) error {
+	switch file {
+	case "":
+		return errors.New("-file is required")
+	case "-":
+		return write(c.out)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
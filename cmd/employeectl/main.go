@@ -0,0 +1,122 @@
+// Command employeectl administers the employee service: it manages and
+// imports or exports employees, runs the schema migrations and shows the
+// configuration.
+//
+//	employeectl [-api URL] [-api-key KEY] [-output table|json|csv] <command> <subcommand> [flags]
+//
+// Employee commands go through the HTTP API when -api is set, and straight
+// to the database otherwise. The database, migrations and config commands
+// are configured like the server, from the environment and the cloud
+// config.
+package main
+
+import (
+	"context"
+	"employee-golang/client"
+	"employee-golang/config"
+	"employee-golang/repositories"
+	"employee-golang/service"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `usage: employeectl [-api URL] [-api-key KEY] [-output table|json|csv] <command> <subcommand> [flags]
+
+commands:
+  employees list [-status s1,s2]
+  employees get <id>
+  employees create -file employee.json
+  employees update -file employee.json
+  employees delete <id>
+  employees import -file employees.csv|employees.json [-update]
+  employees export -file employees.csv|employees.json [-status s1,s2]
+  migrate status
+  migrate up [-to version]
+  migrate baseline -to version
+  config show [key...]
+
+global flags:
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stdin); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "employeectl:", err)
+		os.Exit(1)
+	}
+}
+
+type cli struct {
+	api    string
+	apiKey string
+	output string
+	out    io.Writer
+	in     io.Reader
+	// employees is set by tests; otherwise it is built on first use.
+	employees backend
+}
+
+func run(ctx context.Context, args []string, out io.Writer, in io.Reader) error {
+	c := &cli{out: out, in: in}
+	flags := flag.NewFlagSet("employeectl", flag.ContinueOnError)
+	flags.StringVar(&c.api, "api", os.Getenv("EMPLOYEECTL_API"), "base URL of the employee API; the database is used when empty")
+	flags.StringVar(&c.apiKey, "api-key", os.Getenv("EMPLOYEECTL_API_KEY"), "API key sent to the employee API")
+	flags.StringVar(&c.output, "output", "table", "output format: table, json or csv")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if c.output != "table" && c.output != "json" && c.output != "csv" {
+		return fmt.Errorf("unknown output format %q", c.output)
+	}
+	return c.dispatch(ctx, flags.Args())
+}
+
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	if len(args) < 2 {
+		return errors.New("a command and subcommand are required; see employeectl -h")
+	}
+	switch args[0] {
+	case "employees":
+		return c.runEmployees(ctx, args[1], args[2:])
+	case "migrate":
+		return c.runMigrate(ctx, args[1], args[2:])
+	case "config":
+		return c.runConfig(args[1], args[2:])
+	}
+	return fmt.Errorf("unknown command %q; see employeectl -h", args[0])
+}
+
+// backend returns where employee commands go, connecting on first use.
+func (c *cli) backend() (backend, error) {
+	if c.employees != nil {
+		return c.employees, nil
+	}
+	if c.api != "" {
+		var options []client.Option
+		if c.apiKey != "" {
+			options = append(options, client.WithAPIKey(c.apiKey))
+		}
+		c.employees = apiBackend{client: client.New(c.api, options...)}
+		return c.employees, nil
+	}
+	if err := connect(); err != nil {
+		return nil, err
+	}
+	c.employees = databaseBackend{service: service.NewEmployeeService()}
+	return c.employees, nil
+}
+
+// connect loads the server configuration and waits for the database.
+func connect() error {
+	config.InitConfig(true)
+	return repositories.WaitForConnection()
+}
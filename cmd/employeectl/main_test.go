@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"employee-golang/controller"
+	"employee-golang/middleware"
+	"employee-golang/model"
+	"employee-golang/service"
+	"employee-golang/util"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeEmployeeService struct {
+	service.IEmployeeService
+	mu        sync.Mutex
+	employees map[string]*model.Employee
+}
+
+func (s *fakeEmployeeService) GetEmployeesByStatus(_ context.Context, filter string) ([]*model.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rs []*model.Employee
+	for _, e := range s.employees {
+		if filter == "" || e.Status == filter {
+			rs = append(rs, e)
+		}
+	}
+	return rs, nil
+}
+
+func (s *fakeEmployeeService) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.employees[id]; ok {
+		return e, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *fakeEmployeeService) InsertEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.employees[employee.IdEmployee]; ok {
+		return "Employee already exists", errors.New("employee already exists")
+	}
+	s.employees[employee.IdEmployee] = employee
+	return "Employee was added", nil
+}
+
+func (s *fakeEmployeeService) UpdateEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.employees[employee.IdEmployee] = employee
+	return "Employee was updated", nil
+}
+
+func (s *fakeEmployeeService) DeleteEmployee(_ context.Context, id string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.employees[id]; !ok {
+		return "", sql.ErrNoRows
+	}
+	delete(s.employees, id)
+	return "Employee was deleted", nil
+}
+
+func newTestAPI(t *testing.T) (string, *fakeEmployeeService) {
+	employees := &fakeEmployeeService{employees: map[string]*model.Employee{
+		"1": {IdEmployee: "1", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "555", Status: "active"},
+		"2": {IdEmployee: "2", FirstName: "John", LastName: "Roe", Email: "john@example.com", Phone: "556", Status: "on_leave", ManagerId: "1"},
+	}}
+	e := echo.New()
+	e.Validator = &controller.CustomValidator{Validator: util.NewValidator()}
+	e.Use(middleware.NewOpenAPIValidation(controller.NewOpenAPISpec(e)))
+	(&controller.Controller{Service: employees}).Routes(e)
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv.URL, employees
+}
+
+func Test_run_ListEmployees(t *testing.T) {
+	api, _ := newTestAPI(t)
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"-api", api, "-output", "csv", "employees", "list", "-status", "on_leave"}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join(employeeColumns, ",") + "\n2,John,Roe,john@example.com,556,,0,,,1,,on_leave,,\n"
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := run(context.Background(), []string{"-api", api, "employees", "get", "1"}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "IDEMPLOYEE  FIRSTNAME") || !strings.Contains(lines[1], "jane@example.com") {
+		t.Errorf("unexpected table\n%s", out.String())
+	}
+
+	if err := run(context.Background(), []string{"-api", api, "employees", "get", "9"}, &out, nil); err == nil {
+		t.Error("expected an error for an unknown employee")
+	}
+}
+
+func Test_run_DeleteEmployee(t *testing.T) {
+	api, employees := newTestAPI(t)
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"-api", api, "employees", "delete", "2"}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := employees.employees["2"]; ok || !strings.Contains(out.String(), "Employee was deleted") {
+		t.Errorf("employee 2 was not deleted, printed %q", out.String())
+	}
+	if err := run(context.Background(), []string{"-api", api, "employees", "delete", "2"}, &out, nil); err == nil {
+		t.Error("expected an error for an unknown employee")
+	}
+}
+
+func Test_run_ImportExport(t *testing.T) {
+	api, employees := newTestAPI(t)
+	file := filepath.Join(t.TempDir(), "employees.csv")
+	os.WriteFile(file, []byte("idEmployee,firstName,lastName,email,phone,salary\n"+
+		"1,Janet,Doe,jane@example.com,555,1200.50\n"+
+		"3,Mary,Major,mary@example.com,557,900\n"), 0o600)
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"-api", api, "employees", "import", "-file", file}, &out, nil); err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("import without -update error = %v", err)
+	}
+	if employees.employees["3"] == nil || employees.employees["1"].FirstName != "Jane" {
+		t.Fatal("expected only the new employee to be imported")
+	}
+
+	out.Reset()
+	if err := run(context.Background(), []string{"-api", api, "-output", "json", "employees", "import", "-file", file, "-update"}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if employees.employees["1"].FirstName != "Janet" || employees.employees["1"].Salary.String() != "1200.5" {
+		t.Errorf("employee 1 was not updated: %+v", employees.employees["1"])
+	}
+	if !strings.Contains(out.String(), `"result": "updated"`) {
+		t.Errorf("unexpected import results %s", out.String())
+	}
+
+	exported := filepath.Join(t.TempDir(), "employees.json")
+	if err := run(context.Background(), []string{"-api", api, "employees", "export", "-file", exported}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.Open(exported)
+	defer f.Close()
+	rs, err := readEmployees(f, "json")
+	if err != nil || len(rs) != 3 {
+		t.Errorf("exported %d employees, %v", len(rs), err)
+	}
+}
+
+func Test_run_ConfigShow(t *testing.T) {
+	viper.Set("datasource.employee.password", "hunter2")
+	viper.Set("datasource.employee.host", "db.internal")
+	t.Cleanup(viper.Reset)
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"-output", "csv", "config", "show"}, &out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "datasource.employee.host,db.internal\n") || !strings.Contains(out.String(), "datasource.employee.password,********\n") {
+		t.Errorf("unexpected config\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "hunter2") {
+		t.Error("the password was shown")
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+func (c *cli) runMigrate(ctx context.Context, command string, args []string) error {
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	to := flags.Int("to", 0, "last version to apply or baseline; the latest when zero (up)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if command != "status" && command != "up" && command != "baseline" {
+		return fmt.Errorf("unknown migrate subcommand %q", command)
+	}
+	if command == "baseline" && *to == 0 {
+		return errors.New("migrate baseline needs -to, the version the schema is already at")
+	}
+
+	if err := connect(); err != nil {
+		return err
+	}
+	r, err := repositories.NewMigrationRepositories()
+	if err != nil {
+		return err
+	}
+	var rs []*model.MigrationStatus
+	switch command {
+	case "status":
+		rs, err = r.GetMigrationStatus(ctx)
+	case "up":
+		rs, err = r.Migrate(ctx, *to)
+	case "baseline":
+		rs, err = r.Baseline(ctx, *to)
+	}
+	if errPrint := c.printMigrations(rs); errPrint != nil && err == nil {
+		err = errPrint
+	}
+	return err
+}
+
+func (c *cli) printMigrations(migrations []*model.MigrationStatus) error {
+	rows := make([][]string, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt := m.AppliedAt
+		if appliedAt == "" && c.output == "table" {
+			appliedAt = "pending"
+		}
+		rows = append(rows, []string{strconv.Itoa(m.Version), m.Name, appliedAt})
+	}
+	if migrations == nil {
+		migrations = []*model.MigrationStatus{}
+	}
+	return c.print(migrations, []string{"version", "name", "appliedAt"}, rows)
+}
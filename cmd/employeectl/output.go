@@ -0,0 +1,191 @@
+package main
+
+import (
+	"employee-golang/model"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// employeeColumns are the fields of exported and imported files, named
+// after the JSON fields of the API.
+var employeeColumns = []string{
+	"idEmployee", "firstName", "lastName", "email", "phone", "hireDate", "salary", "currency",
+	"departmentId", "managerId", "positionId", "status", "terminationDate", "terminationReason",
+}
+
+// tableColumns are the employee fields wide enough to read in a terminal.
+var tableColumns = []string{"idEmployee", "firstName", "lastName", "email", "status", "departmentId", "managerId"}
+
+func employeeField(e *model.Employee, column string) string {
+	switch column {
+	case "idEmployee":
+		return e.IdEmployee
+	case "firstName":
+		return e.FirstName
+	case "lastName":
+		return e.LastName
+	case "email":
+		return e.Email
+	case "phone":
+		return e.Phone
+	case "hireDate":
+		return e.HireDate
+	case "salary":
+		return e.Salary.String()
+	case "currency":
+		return e.Currency
+	case "departmentId":
+		return e.DepartmentId
+	case "managerId":
+		return e.ManagerId
+	case "positionId":
+		return e.PositionId
+	case "status":
+		return e.Status
+	case "terminationDate":
+		return e.TerminationDate
+	case "terminationReason":
+		return e.TerminationReason
+	}
+	return ""
+}
+
+func setEmployeeField(e *model.Employee, column, value string) error {
+	switch column {
+	case "idEmployee":
+		e.IdEmployee = value
+	case "firstName":
+		e.FirstName = value
+	case "lastName":
+		e.LastName = value
+	case "email":
+		e.Email = value
+	case "phone":
+		e.Phone = value
+	case "hireDate":
+		e.HireDate = value
+	case "salary":
+		if value == "" {
+			return nil
+		}
+		salary, err := decimal.NewFromString(value)
+		if err != nil {
+			return fmt.Errorf("salary %q is not a decimal number", value)
+		}
+		e.Salary = model.NewMoney(salary)
+	case "currency":
+		e.Currency = value
+	case "departmentId":
+		e.DepartmentId = value
+	case "managerId":
+		e.ManagerId = value
+	case "positionId":
+		e.PositionId = value
+	case "status":
+		e.Status = value
+	case "terminationDate":
+		e.TerminationDate = value
+	case "terminationReason":
+		e.TerminationReason = value
+	default:
+		return fmt.Errorf("unknown column %q", column)
+	}
+	return nil
+}
+
+func employeeRows(employees []*model.Employee, columns []string) [][]string {
+	rows := make([][]string, 0, len(employees))
+	for _, e := range employees {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = employeeField(e, column)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// readEmployees reads a JSON array of employees, or a CSV file whose header
+// names a column of employeeColumns for each field.
+func readEmployees(r io.Reader, format string) ([]*model.Employee, error) {
+	if format == "json" {
+		var res []*model.Employee
+		return res, json.NewDecoder(r).Decode(&res)
+	}
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	res := make([]*model.Employee, 0, len(records)-1)
+	for line, record := range records[1:] {
+		e := new(model.Employee)
+		for i, column := range header {
+			if err := setEmployeeField(e, strings.TrimSpace(column), record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func writeEmployees(w io.Writer, format string, employees []*model.Employee) error {
+	if format == "json" {
+		return writeJSON(w, employees)
+	}
+	return writeCSV(w, employeeColumns, employeeRows(employees, employeeColumns))
+}
+
+func (c *cli) printEmployees(employees []*model.Employee) error {
+	columns := employeeColumns
+	if c.output == "table" {
+		columns = tableColumns
+	}
+	return c.print(employees, columns, employeeRows(employees, columns))
+}
+
+// print writes v as JSON, or header and rows as CSV or an aligned table.
+func (c *cli) print(v interface{}, header []string, rows [][]string) error {
+	switch c.output {
+	case "json":
+		return writeJSON(c.out, v)
+	case "csv":
+		return writeCSV(c.out, header, rows)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (c *cli) printMessage(message string) error {
+	if c.output == "json" {
+		return writeJSON(c.out, map[string]string{"message": message})
+	}
+	_, err := fmt.Fprintln(c.out, message)
+	return err
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.WriteAll(rows)
+	return cw.Error()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"employee-golang/config"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"sort"
+	"strings"
+)
+
+// secretWords mark configuration keys whose values are masked.
+var secretWords = []string{"password", "secret", "token", "credential", "connection", "api_key", "apikey"}
+
+func (c *cli) runConfig(command string, keys []string) error {
+	if command != "show" {
+		return fmt.Errorf("unknown config subcommand %q", command)
+	}
+	config.InitConfig(true)
+
+	settings := map[string]string{}
+	if len(keys) > 0 {
+		// keys set only in the environment are found by name alone
+		for _, key := range keys {
+			if !viper.IsSet(key) {
+				return errors.New("config key " + key + " is not set")
+			}
+			settings[key] = fmt.Sprint(viper.Get(key))
+		}
+	} else {
+		flatten("", viper.AllSettings(), settings)
+	}
+
+	names := make([]string, 0, len(settings))
+	for key := range settings {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	rows := make([][]string, 0, len(names))
+	for _, key := range names {
+		if isSecret(key) {
+			settings[key] = "********"
+		}
+		rows = append(rows, []string{key, settings[key]})
+	}
+	return c.print(settings, []string{"key", "value"}, rows)
+}
+
+func flatten(prefix string, settings map[string]interface{}, res map[string]string) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(key, nested, res)
+			continue
+		}
+		res[key] = fmt.Sprint(value)
+	}
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range secretWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return strings.HasSuffix(key, ".key") || strings.HasSuffix(key, "_key")
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"net"
+	"strings"
+	"time"
+)
+
+// GetV1DeprecatedAt is when v1 of the API was deprecated, from the
+// api.v1.deprecated_at date (YYYY-MM-DD); zero when unset.
+func GetV1DeprecatedAt() time.Time {
+	return apiDate("api.v1.deprecated_at")
+}
+
+// GetV1Sunset is when v1 of the API stops being served, from the
+// api.v1.sunset date (YYYY-MM-DD); zero when unset.
+func GetV1Sunset() time.Time {
+	return apiDate("api.v1.sunset")
+}
+
+func apiDate(key string) time.Time {
+	t, err := time.Parse("2006-01-02", viper.GetString(key))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// GetAPIKeys lists the API keys clients may identify with in X-API-Key,
+// from api.keys.
+func GetAPIKeys() []string {
+	return viper.GetStringSlice("api.keys")
+}
+
+// GetJWTSecret is the HMAC secret bearer tokens are signed with, from
+// api.jwt_secret. Without it bearer tokens do not identify callers.
+func GetJWTSecret() string {
+	return viper.GetString("api.jwt_secret")
+}
+
+// GetTrustedProxies parses api.trusted_proxies, the addresses or CIDR
+// ranges of the proxies whose X-Forwarded-For header is believed.
+func GetTrustedProxies() []*net.IPNet {
+	var res []*net.IPNet
+	for _, v := range viper.GetStringSlice("api.trusted_proxies") {
+		v = strings.TrimSpace(v)
+		if !strings.Contains(v, "/") {
+			if ip := net.ParseIP(v); ip != nil && ip.To4() != nil {
+				v += "/32"
+			} else {
+				v += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(v)
+		if err != nil {
+			logrus.Warnf("ignoring invalid trusted proxy: %s", v)
+			continue
+		}
+		res = append(res, ipNet)
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+// OvertimeRules decide which worked hours are overtime: hours beyond Daily
+// in a day, then regular hours beyond Weekly in a week. A zero threshold
+// disables that rule.
+type OvertimeRules struct {
+	Daily  time.Duration
+	Weekly time.Duration
+}
+
+func GetOvertimeRules() OvertimeRules {
+	rules := OvertimeRules{Daily: 8 * time.Hour, Weekly: 40 * time.Hour}
+	if viper.IsSet("attendance.overtime.daily") {
+		rules.Daily = viper.GetDuration("attendance.overtime.daily")
+	}
+	if viper.IsSet("attendance.overtime.weekly") {
+		rules.Weekly = viper.GetDuration("attendance.overtime.weekly")
+	}
+	return rules
+}
+
+// GetWeekStart is the first day of a timesheet week, monday by default.
+func GetWeekStart() time.Weekday {
+	v := strings.ToLower(viper.GetString("attendance.week_start"))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == v {
+			return day
+		}
+	}
+	return time.Monday
+}
+
+// GetAttendanceLocation is the time zone days and weeks are cut in.
+func GetAttendanceLocation() *time.Location {
+	v := viper.GetString("attendance.timezone")
+	if v == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(v)
+	if err != nil {
+		logrus.Warnf("unknown attendance.timezone %s, using UTC: %s", v, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// GetClockSkew is how far past now a clock time may be, allowing for
+// clients whose clocks run slightly ahead.
+func GetClockSkew() time.Duration {
+	v := viper.GetDuration("attendance.clock_skew")
+	if v <= 0 {
+		return 5 * time.Minute
+	}
+	return v
+}
+
+// GetMaxShift is the longest a clock-in may stay open; a later clock-out is
+// capped to it. Sessions are looked up this far before a period so shifts
+// crossing into it are counted.
+func GetMaxShift() time.Duration {
+	v := viper.GetDuration("attendance.max_shift")
+	if v <= 0 {
+		return 24 * time.Hour
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+func IsEmployeeCacheEnabled() bool {
+	return viper.GetBool("cache.employee.enabled")
+}
+
+// GetCacheBackend is either "memory" (default) or "redis".
+func GetCacheBackend() string {
+	v := viper.GetString("cache.employee.backend")
+	if v == "" {
+		return "memory"
+	}
+	return v
+}
+
+func GetCacheTTL() time.Duration {
+	v := viper.GetDuration("cache.employee.ttl")
+	if v <= 0 {
+		return time.Minute
+	}
+	return v
+}
+
+func GetCacheSize() int {
+	v := viper.GetInt("cache.employee.size")
+	if v <= 0 {
+		return 1000
+	}
+	return v
+}
+
+func GetRedisAddr() string {
+	v := viper.GetString("cache.redis.addr")
+	if v == "" {
+		return "127.0.0.1:6379"
+	}
+	return v
+}
+
+func GetRedisPassword() string {
+	return viper.GetString("cache.redis.password")
+}
+
+func GetRedisDB() int {
+	return viper.GetInt("cache.redis.db")
+}
+
+func GetRedisPrefix() string {
+	v := viper.GetString("cache.redis.prefix")
+	if v == "" {
+		return "employee-golang:"
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"employee-golang/money"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// GetDefaultCurrency is the currency assumed for salaries sent without one.
+func GetDefaultCurrency() string {
+	v := viper.GetString("currency.default")
+	if v == "" {
+		return "USD"
+	}
+	return strings.ToUpper(v)
+}
+
+// GetExchangeRates parses currency.rates, a ";"-separated list of
+// "CODE=rate" entries giving how many units of CODE one unit of
+// currency.base buys, for example "EUR=0.92;IDR=15550;JPY=149.5".
+func GetExchangeRates() money.Rates {
+	base := viper.GetString("currency.base")
+	if base == "" {
+		base = GetDefaultCurrency()
+	}
+	rates := money.Rates{Base: strings.ToUpper(base), Rates: map[string]decimal.Decimal{}}
+	for _, entry := range strings.Split(viper.GetString("currency.rates"), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, value, _ := strings.Cut(entry, "=")
+		rate, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil || !rate.IsPositive() {
+			logrus.Warnf("ignoring exchange rate entry with invalid rate: %s", entry)
+			continue
+		}
+		rates.Rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates
+}
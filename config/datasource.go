@@ -0,0 +1,225 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/spf13/viper"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+func GetMaxOpenConns() int {
+	v := viper.GetInt("datasource.employee.pool.max_open_conns")
+	if v <= 0 {
+		return 10
+	}
+	return v
+}
+
+func GetMaxIdleConns() int {
+	v := viper.GetInt("datasource.employee.pool.max_idle_conns")
+	if v <= 0 {
+		return 10
+	}
+	return v
+}
+
+func GetConnMaxLifetime() time.Duration {
+	v := viper.GetDuration("datasource.employee.pool.conn_max_lifetime")
+	if v <= 0 {
+		return time.Minute * 3
+	}
+	return v
+}
+
+func GetConnMaxIdleTime() time.Duration {
+	return viper.GetDuration("datasource.employee.pool.conn_max_idle_time")
+}
+
+func GetConnectRetryMax() int {
+	v := viper.GetInt("datasource.employee.connect.retry_max")
+	if v <= 0 {
+		return 5
+	}
+	return v
+}
+
+func GetConnectRetryWait() time.Duration {
+	v := viper.GetDuration("datasource.employee.connect.retry_wait")
+	if v <= 0 {
+		return time.Second * 2
+	}
+	return v
+}
+
+// BuildConnection assembles a MySQL DSN from the discrete datasource keys
+// (host, port, user, password, name, params) and the configured TLS mode.
+func BuildConnection(prefix string) (string, error) {
+	return buildConnection(prefix, "")
+}
+
+// buildConnection is BuildConnection with an optional "host[:port]" that
+// replaces the configured address, used for replicas sharing the primary's
+// credentials and params.
+func buildConnection(prefix, addr string) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = viper.GetString(prefix + ".user")
+	cfg.Passwd = viper.GetString(prefix + ".password")
+	cfg.DBName = viper.GetString(prefix + ".name")
+	cfg.Net = "tcp"
+
+	host := viper.GetString(prefix + ".host")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := viper.GetString(prefix + ".port")
+	if port == "" {
+		port = "3306"
+	}
+	if addr != "" {
+		if h, p, err := net.SplitHostPort(addr); err == nil {
+			host, port = h, p
+		} else {
+			host = addr
+		}
+	}
+	cfg.Addr = net.JoinHostPort(host, port)
+
+	params := viper.GetString(prefix + ".params")
+	if params != "" {
+		// parse params through the driver so known options (parseTime, loc, ...)
+		// land on their typed fields instead of the raw Params map
+		parsed, err := mysql.ParseDSN("/?" + params)
+		if err != nil {
+			return "", fmt.Errorf("invalid datasource params %q: %w", params, err)
+		}
+		parsed.User, parsed.Passwd, parsed.DBName = cfg.User, cfg.Passwd, cfg.DBName
+		parsed.Net, parsed.Addr = cfg.Net, cfg.Addr
+		cfg = parsed
+	}
+
+	tlsName, err := registerTLS(prefix, host)
+	if err != nil {
+		return "", err
+	}
+	if tlsName != "" {
+		cfg.TLSConfig = tlsName
+	}
+	return cfg.FormatDSN(), nil
+}
+
+// registerTLS resolves the tls mode of a datasource. Built-in driver modes
+// (true, false, skip-verify, preferred) are passed through; "custom" loads the
+// CA and optional client certificate and registers them with the driver.
+func registerTLS(prefix, host string) (string, error) {
+	mode := strings.ToLower(viper.GetString(prefix + ".tls.mode"))
+	switch mode {
+	case "":
+		return "", nil
+	case "true", "false", "skip-verify", "preferred":
+		return mode, nil
+	case "custom":
+	default:
+		return "", fmt.Errorf("unsupported datasource tls mode %q", mode)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: viper.GetString(prefix + ".tls.server_name"),
+		MinVersion: tls.VersionTLS12,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	if ca := viper.GetString(prefix + ".tls.ca"); ca != "" {
+		pem, err := os.ReadFile(ca)
+		if err != nil {
+			return "", fmt.Errorf("failed to read datasource CA %s: %w", ca, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("no certificates found in datasource CA %s", ca)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	cert, key := viper.GetString(prefix+".tls.cert"), viper.GetString(prefix+".tls.key")
+	if cert != "" && key != "" {
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return "", fmt.Errorf("failed to load datasource client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	name := strings.ReplaceAll(prefix, ".", "-")
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func resolveConnection(prefix string) (string, error) {
+	v := viper.GetString(prefix + ".connection")
+	if v != "" {
+		return v, nil
+	}
+	dsn, err := BuildConnection(prefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to build connection for %s: %w", prefix, err)
+	}
+	return dsn, nil
+}
+
+// GetReplicaConnections returns the DSNs of the read replicas. Full DSNs in
+// datasource.employee.replica.connections win; otherwise every entry of
+// datasource.employee.replica.hosts is built with the primary's credentials.
+func GetReplicaConnections() ([]string, error) {
+	connections := viper.GetStringSlice("datasource.employee.replica.connections")
+	if len(connections) > 0 {
+		return connections, nil
+	}
+	hosts := viper.GetStringSlice("datasource.employee.replica.hosts")
+	res := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		dsn, err := buildConnection("datasource.employee", strings.TrimSpace(host))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build replica connection for %s: %w", host, err)
+		}
+		res = append(res, dsn)
+	}
+	return res, nil
+}
+
+func GetReplicaHealthInterval() time.Duration {
+	v := viper.GetDuration("datasource.employee.replica.health_interval")
+	if v <= 0 {
+		return time.Second * 10
+	}
+	return v
+}
+
+func GetReplicaEjectAfter() int {
+	v := viper.GetInt("datasource.employee.replica.eject_after")
+	if v <= 0 {
+		return 3
+	}
+	return v
+}
+
+// GetReplicaMaxPins caps the clients pinned to the primary after a write.
+func GetReplicaMaxPins() int {
+	v := viper.GetInt("datasource.employee.replica.max_pins")
+	if v <= 0 {
+		return 100000
+	}
+	return v
+}
+
+// GetReadYourWritesWindow is how long a client is pinned to the primary after
+// a write; zero disables pinning.
+func GetReadYourWritesWindow() time.Duration {
+	return viper.GetDuration("datasource.employee.replica.read_your_writes_window")
+}
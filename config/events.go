@@ -0,0 +1,107 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+// IsEventRelayEnabled starts the relay publishing outbox events. Events
+// are written to the outbox either way.
+func IsEventRelayEnabled() bool {
+	return viper.GetBool("events.relay.enabled")
+}
+
+// GetEventSinks lists the sinks events are published to, from the
+// comma-separated events.sinks: stdout (default), file, webhook, nats,
+// kafka, and webhooks, which enqueues deliveries to webhook subscriptions.
+func GetEventSinks() []string {
+	var sinks []string
+	for _, s := range strings.Split(viper.GetString("events.sinks"), ",") {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			sinks = append(sinks, s)
+		}
+	}
+	if len(sinks) == 0 {
+		return []string{"stdout"}
+	}
+	return sinks
+}
+
+func GetEventRelayInterval() time.Duration {
+	v := viper.GetDuration("events.relay.interval")
+	if v <= 0 {
+		return time.Second
+	}
+	return v
+}
+
+func GetEventRelayBatchSize() int {
+	v := viper.GetInt("events.relay.batch_size")
+	if v <= 0 {
+		return 100
+	}
+	return v
+}
+
+// GetEventRetention is how long published events stay in the outbox.
+func GetEventRetention() time.Duration {
+	v := viper.GetDuration("events.relay.retention")
+	if v <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return v
+}
+
+func GetEventFilePath() string {
+	v := viper.GetString("events.file.path")
+	if v == "" {
+		return "./data/events.jsonl"
+	}
+	return v
+}
+
+func GetEventWebhookURL() string {
+	return viper.GetString("events.webhook.url")
+}
+
+func GetEventSinkTimeout() time.Duration {
+	v := viper.GetDuration("events.timeout")
+	if v <= 0 {
+		return 5 * time.Second
+	}
+	return v
+}
+
+func GetNATSURL() string {
+	v := viper.GetString("events.nats.url")
+	if v == "" {
+		return "nats://127.0.0.1:4222"
+	}
+	return v
+}
+
+func GetNATSSubject() string {
+	v := viper.GetString("events.nats.subject")
+	if v == "" {
+		return "employees"
+	}
+	return v
+}
+
+// GetKafkaRestURL is the Kafka REST proxy events are produced through.
+func GetKafkaRestURL() string {
+	v := viper.GetString("events.kafka.rest_url")
+	if v == "" {
+		return "http://127.0.0.1:8082"
+	}
+	return v
+}
+
+func GetKafkaTopic() string {
+	v := viper.GetString("events.kafka.topic")
+	if v == "" {
+		return "employee-events"
+	}
+	return v
+}
@@ -0,0 +1,23 @@
+package config
+
+import "github.com/spf13/viper"
+
+// GetGraphQLMaxComplexity is the highest cost a GraphQL operation may have,
+// 1000 by default.
+func GetGraphQLMaxComplexity() int {
+	v := viper.GetInt("graphql.max_complexity")
+	if v <= 0 {
+		return 1000
+	}
+	return v
+}
+
+// GetGraphQLMaxDepth is how deeply a GraphQL operation may nest fields, 10
+// by default.
+func GetGraphQLMaxDepth() int {
+	v := viper.GetInt("graphql.max_depth")
+	if v <= 0 {
+		return 10
+	}
+	return v
+}
@@ -0,0 +1,18 @@
+package config
+
+import "github.com/spf13/viper"
+
+// IsGRPCEnabled starts the gRPC employee API next to the REST server.
+func IsGRPCEnabled() bool {
+	return viper.GetBool("grpc.enabled")
+}
+
+// GetGRPCAddress is the address the gRPC server listens on, :9090 by
+// default.
+func GetGRPCAddress() string {
+	v := viper.GetString("grpc.address")
+	if v == "" {
+		return ":9090"
+	}
+	return v
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+func IsIdempotencyEnabled() bool {
+	if !viper.IsSet("idempotency.enabled") {
+		return true
+	}
+	return viper.GetBool("idempotency.enabled")
+}
+
+// GetIdempotencyBackend is either "memory" (default) or "database".
+func GetIdempotencyBackend() string {
+	v := viper.GetString("idempotency.backend")
+	if v == "" {
+		return "memory"
+	}
+	return v
+}
+
+// GetIdempotencyLease is how long a key stays claimed by a request that has
+// not finished, so a key left behind by a crash frees up quickly.
+func GetIdempotencyLease() time.Duration {
+	v := viper.GetDuration("idempotency.lease")
+	if v <= 0 {
+		return time.Minute
+	}
+	return v
+}
+
+// GetIdempotencyMaxBody caps the request bodies buffered to be hashed. The
+// default leaves room for a document upload.
+func GetIdempotencyMaxBody() int64 {
+	v := viper.GetInt64("idempotency.max_body")
+	if v <= 0 {
+		return GetDocumentMaxSize() + 1<<20
+	}
+	return v
+}
+
+func GetIdempotencyTTL() time.Duration {
+	v := viper.GetDuration("idempotency.ttl")
+	if v <= 0 {
+		return time.Hour * 24
+	}
+	return v
+}
+
+// GetIdempotencyMethods lists the HTTP methods honouring Idempotency-Key.
+func GetIdempotencyMethods() []string {
+	v := viper.GetStringSlice("idempotency.methods")
+	if len(v) == 0 {
+		return []string{"POST"}
+	}
+	for i := range v {
+		v[i] = strings.ToUpper(strings.TrimSpace(v[i]))
+	}
+	return v
+}
@@ -1,16 +1,16 @@
 package config
 
 import (
-	_ "employee-golang/docs"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	swagger "github.com/swaggo/echo-swagger"
 	"net/http"
 )
 
+// InitSwagger serves the Swagger UI over the generated /openapi.json.
 func InitSwagger(e *echo.Echo) {
 
-	e.GET("/swagger/*", swagger.WrapHandler)
+	e.GET("/swagger/*", swagger.EchoWrapHandler(swagger.URL("/openapi.json")))
 	e.GET("/", func(c echo.Context) error {
 		return c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
 	})
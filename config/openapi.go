@@ -0,0 +1,9 @@
+package config
+
+import "github.com/spf13/viper"
+
+// IsOpenAPIValidationEnabled turns on checking requests and responses
+// against the OpenAPI document, off unless openapi.validation is set.
+func IsOpenAPIValidationEnabled() bool {
+	return viper.GetBool("openapi.validation")
+}
@@ -2,18 +2,41 @@ package config
 
 import "github.com/spf13/viper"
 
-func GetConnection() string {
-	v := viper.GetString("datasource.employee.connection")
-	if v == "" {
-		return ""
+// employeeColumns is the select list every employee query returns, in the
+// order repositories scan it.
+var employeeColumns = employeeColumnsOf("")
+
+// employeeColumnsOf is employeeColumns qualified with a table alias.
+func employeeColumnsOf(alias string) string {
+	p, table := "", "employee"
+	if alias != "" {
+		p, table = alias+".", alias
 	}
-	return v
+	// the current salary and currency come from the latest compensation entry
+	// already in effect, falling back to the employee columns without history
+	latest := func(column string) string {
+		return "(select c." + column + " from compensation c where c.employee_id = " + table + ".employee_id" +
+			" and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1)"
+	}
+	return p + "employee_id, " + p + "first_name, " + p + "last_name, " + p + "email, " + p + "phone, " +
+		"coalesce(" + p + "hire_date, ''), " +
+		"coalesce(" + latest("salary") + ", " + p + "salary, 0.0), " +
+		"coalesce(" + latest("currency") + ", " + p + "currency, ''), " +
+		"coalesce(" + p + "department_id, ''), coalesce(" + p + "manager_id, ''), coalesce(" + p + "position_id, ''), " +
+		p + "status, coalesce(" + p + "termination_date, ''), coalesce(" + p + "termination_reason, ''), " +
+		"coalesce((select ph.checksum from employee_photo ph where ph.employee_id = " + table + ".employee_id), '')"
+}
+
+// GetConnection is the DSN of the employee database, or why it cannot be
+// built from the datasource settings.
+func GetConnection() (string, error) {
+	return resolveConnection("datasource.employee")
 }
 
 func GetEmployees() string {
 	v := viper.GetString("app.query.GET_EMPLOYEES")
 	if v == "" {
-		return "select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee"
+		return "select " + employeeColumns + " from employee"
 	}
 	return v
 }
@@ -21,7 +44,35 @@ func GetEmployees() string {
 func GetEmployeeById() string {
 	v := viper.GetString("app.query.GET_EMPLOYEES_BY_ID")
 	if v == "" {
-		return "select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee where employee_id = ?"
+		return "select " + employeeColumns + " from employee where employee_id = ?"
+	}
+	return v
+}
+
+// GetEmployeeByIdForUpdate locks the row, read as the before image of a
+// change recorded in the outbox.
+func GetEmployeeByIdForUpdate() string {
+	v := viper.GetString("app.query.GET_EMPLOYEE_BY_ID_FOR_UPDATE")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where employee_id = ? for update"
+	}
+	return v
+}
+
+// GetEmployeesByIds looks up a batch of employees; the repository expands
+// "in (?)" to one placeholder per id.
+func GetEmployeesByIds() string {
+	v := viper.GetString("app.query.GET_EMPLOYEES_BY_IDS")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where employee_id in (?)"
+	}
+	return v
+}
+
+func GetEmployeesByManagers() string {
+	v := viper.GetString("app.query.GET_EMPLOYEES_BY_MANAGERS")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where manager_id in (?)"
 	}
 	return v
 }
@@ -29,7 +80,7 @@ func GetEmployeeById() string {
 func InsertEmployee() string {
 	v := viper.GetString("app.query.INSERT_EMPLOYEE")
 	if v == "" {
-		return "insert into employee (employee_id, first_name, last_name, email, phone, hire_date, salary) value (?, ?, ?, ?, ?, nullif(?,''), nullif(?, ''))"
+		return "insert into employee (employee_id, first_name, last_name, email, phone, hire_date, salary, currency, department_id, manager_id, position_id, status) value (?, ?, ?, ?, ?, nullif(?,''), ?, ?, nullif(?, ''), nullif(?, ''), nullif(?, ''), ?)"
 	}
 	return v
 }
@@ -45,7 +96,7 @@ func CountEmployee() string {
 func EditEmployee() string {
 	v := viper.GetString("app.query.EDIT_EMPLOYEE")
 	if v == "" {
-		return "update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = nullif(?, 0) where employee_id = ?"
+		return "update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = ?, currency = ?, department_id = nullif(?, ''), manager_id = nullif(?, ''), position_id = nullif(?, '') where employee_id = ?"
 	}
 	return v
 }
@@ -57,3 +108,771 @@ func DeleteEmployee() string {
 	}
 	return v
 }
+
+func GetEmployeesByDepartment() string {
+	v := viper.GetString("app.query.GET_EMPLOYEES_BY_DEPARTMENT")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where department_id = ?"
+	}
+	return v
+}
+
+// GetEmployeesByStatus filters on a comma-separated list of statuses.
+func GetEmployeesByStatus() string {
+	v := viper.GetString("app.query.GET_EMPLOYEES_BY_STATUS")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where find_in_set(status, ?)"
+	}
+	return v
+}
+
+func EditEmployeeStatus() string {
+	v := viper.GetString("app.query.EDIT_EMPLOYEE_STATUS")
+	if v == "" {
+		return "update employee set status = ?, hire_date = nullif(?, ''), termination_date = nullif(?, ''), termination_reason = nullif(?, '') where employee_id = ?"
+	}
+	return v
+}
+
+func InsertStatusChange() string {
+	v := viper.GetString("app.query.INSERT_STATUS_CHANGE")
+	if v == "" {
+		return "insert into employment_status_history (employee_id, from_status, to_status, effective_date, reason) value (?, nullif(?, ''), ?, ?, nullif(?, ''))"
+	}
+	return v
+}
+
+func GetStatusHistory() string {
+	v := viper.GetString("app.query.GET_STATUS_HISTORY")
+	if v == "" {
+		return "select employee_id, coalesce(from_status, ''), to_status, cast(effective_date as char), coalesce(reason, ''), cast(created_at as char) from employment_status_history where employee_id = ? order by id"
+	}
+	return v
+}
+
+func GetDirectReports() string {
+	v := viper.GetString("app.query.GET_DIRECT_REPORTS")
+	if v == "" {
+		return "select " + employeeColumns + " from employee where manager_id = ?"
+	}
+	return v
+}
+
+// GetReportingChain walks manager_id upwards from an employee and returns
+// every manager above them, nearest first. The depth bound stops a runaway
+// recursion should a cycle ever reach the table.
+func GetReportingChain() string {
+	v := viper.GetString("app.query.GET_REPORTING_CHAIN")
+	if v == "" {
+		return "with recursive chain (employee_id, manager_id, depth) as (" +
+			"select employee_id, manager_id, 0 from employee where employee_id = ? " +
+			"union all " +
+			"select e.employee_id, e.manager_id, c.depth + 1 from employee e join chain c on e.employee_id = c.manager_id where c.depth < 100" +
+			") select " + employeeColumnsOf("e") + " from chain c join employee e on e.employee_id = c.employee_id where c.depth > 0 order by c.depth"
+	}
+	return v
+}
+
+// GetSubordinates returns every employee below a manager, with their depth
+// relative to the manager, breadth first.
+func GetSubordinates() string {
+	v := viper.GetString("app.query.GET_SUBORDINATES")
+	if v == "" {
+		return "with recursive tree (employee_id, depth) as (" +
+			"select employee_id, 0 from employee where manager_id = ? " +
+			"union all " +
+			"select e.employee_id, t.depth + 1 from employee e join tree t on e.manager_id = t.employee_id where t.depth < 100" +
+			") select " + employeeColumnsOf("e") + " from tree t join employee e on e.employee_id = t.employee_id order by t.depth, e.employee_id"
+	}
+	return v
+}
+
+// IsRecursiveCTESupported reports whether the database understands
+// "with recursive" (MySQL 8+, MariaDB 10.2+). When false, hierarchy queries
+// fall back to walking the tree one level per query.
+func IsRecursiveCTESupported() bool {
+	if !viper.IsSet("datasource.employee.recursive_cte") {
+		return true
+	}
+	return viper.GetBool("datasource.employee.recursive_cte")
+}
+
+func GetDepartments() string {
+	v := viper.GetString("app.query.GET_DEPARTMENTS")
+	if v == "" {
+		return "select department_id, name, coalesce(description, '') from department"
+	}
+	return v
+}
+
+func GetDepartmentById() string {
+	v := viper.GetString("app.query.GET_DEPARTMENT_BY_ID")
+	if v == "" {
+		return "select department_id, name, coalesce(description, '') from department where department_id = ?"
+	}
+	return v
+}
+
+func GetDepartmentsByIds() string {
+	v := viper.GetString("app.query.GET_DEPARTMENTS_BY_IDS")
+	if v == "" {
+		return "select department_id, name, coalesce(description, '') from department where department_id in (?)"
+	}
+	return v
+}
+
+func InsertDepartment() string {
+	v := viper.GetString("app.query.INSERT_DEPARTMENT")
+	if v == "" {
+		return "insert into department (department_id, name, description) value (?, ?, nullif(?, ''))"
+	}
+	return v
+}
+
+func CountDepartment() string {
+	v := viper.GetString("app.query.COUNT_DEPARTMENT")
+	if v == "" {
+		return "select count(*) from department where department_id = ?"
+	}
+	return v
+}
+
+func EditDepartment() string {
+	v := viper.GetString("app.query.EDIT_DEPARTMENT")
+	if v == "" {
+		return "update department set name = ?, description = nullif(?, '') where department_id = ?"
+	}
+	return v
+}
+
+func DeleteDepartment() string {
+	v := viper.GetString("app.query.DELETE_DEPARTMENT")
+	if v == "" {
+		return "delete from department where department_id = ?"
+	}
+	return v
+}
+
+func CountEmployeeByDepartment() string {
+	v := viper.GetString("app.query.COUNT_EMPLOYEE_BY_DEPARTMENT")
+	if v == "" {
+		return "select count(*) from employee where department_id = ?"
+	}
+	return v
+}
+
+func GetPositions() string {
+	v := viper.GetString("app.query.GET_POSITIONS")
+	if v == "" {
+		return "select position_id, title, grade, min_salary, max_salary, currency from position"
+	}
+	return v
+}
+
+func GetPositionById() string {
+	v := viper.GetString("app.query.GET_POSITION_BY_ID")
+	if v == "" {
+		return "select position_id, title, grade, min_salary, max_salary, currency from position where position_id = ?"
+	}
+	return v
+}
+
+func GetPositionsByIds() string {
+	v := viper.GetString("app.query.GET_POSITIONS_BY_IDS")
+	if v == "" {
+		return "select position_id, title, grade, min_salary, max_salary, currency from position where position_id in (?)"
+	}
+	return v
+}
+
+func InsertPosition() string {
+	v := viper.GetString("app.query.INSERT_POSITION")
+	if v == "" {
+		return "insert into position (position_id, title, grade, min_salary, max_salary, currency) value (?, ?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func CountPosition() string {
+	v := viper.GetString("app.query.COUNT_POSITION")
+	if v == "" {
+		return "select count(*) from position where position_id = ?"
+	}
+	return v
+}
+
+func EditPosition() string {
+	v := viper.GetString("app.query.EDIT_POSITION")
+	if v == "" {
+		return "update position set title = ?, grade = ?, min_salary = ?, max_salary = ?, currency = ? where position_id = ?"
+	}
+	return v
+}
+
+func DeletePosition() string {
+	v := viper.GetString("app.query.DELETE_POSITION")
+	if v == "" {
+		return "delete from position where position_id = ?"
+	}
+	return v
+}
+
+func CountEmployeeByPosition() string {
+	v := viper.GetString("app.query.COUNT_EMPLOYEE_BY_POSITION")
+	if v == "" {
+		return "select count(*) from employee where position_id = ?"
+	}
+	return v
+}
+
+func InsertSalaryOverride() string {
+	v := viper.GetString("app.query.INSERT_SALARY_OVERRIDE")
+	if v == "" {
+		return "insert into salary_override (employee_id, position_id, salary, currency, min_salary, max_salary, band_currency, reason) value (?, ?, ?, ?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func GetSalaryOverrides() string {
+	v := viper.GetString("app.query.GET_SALARY_OVERRIDES")
+	if v == "" {
+		return "select employee_id, position_id, salary, currency, min_salary, max_salary, band_currency, reason, cast(created_at as char) from salary_override where employee_id = ? order by id"
+	}
+	return v
+}
+
+func InsertCompensation() string {
+	v := viper.GetString("app.query.INSERT_COMPENSATION")
+	if v == "" {
+		return "insert into compensation (employee_id, salary, currency, effective_date, reason) value (?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func GetCompensationHistory() string {
+	v := viper.GetString("app.query.GET_COMPENSATION_HISTORY")
+	if v == "" {
+		return "select id, employee_id, salary, currency, cast(effective_date as char), coalesce(reason, ''), cast(created_at as char) from compensation where employee_id = ? order by effective_date, id"
+	}
+	return v
+}
+
+func GetCompensationAsOf() string {
+	v := viper.GetString("app.query.GET_COMPENSATION_AS_OF")
+	if v == "" {
+		return "select id, employee_id, salary, currency, cast(effective_date as char), coalesce(reason, ''), cast(created_at as char) from compensation where employee_id = ? and effective_date <= ? order by effective_date desc, id desc limit 1"
+	}
+	return v
+}
+
+// leaveRequestColumns is the select list leave request queries return, in
+// the order repositories scan it.
+const leaveRequestColumns = "id, employee_id, leave_type_id, cast(start_date as char), cast(end_date as char), days, " +
+	"coalesce(reason, ''), status, coalesce(approver_id, ''), coalesce(decision_note, ''), " +
+	"coalesce(cast(decided_at as char), ''), cast(created_at as char)"
+
+func GetLeaveTypes() string {
+	v := viper.GetString("app.query.GET_LEAVE_TYPES")
+	if v == "" {
+		return "select leave_type_id, name, annual_days, accrual, carry_over_days from leave_type"
+	}
+	return v
+}
+
+func GetLeaveTypeById() string {
+	v := viper.GetString("app.query.GET_LEAVE_TYPE_BY_ID")
+	if v == "" {
+		return "select leave_type_id, name, annual_days, accrual, carry_over_days from leave_type where leave_type_id = ?"
+	}
+	return v
+}
+
+func InsertLeaveType() string {
+	v := viper.GetString("app.query.INSERT_LEAVE_TYPE")
+	if v == "" {
+		return "insert into leave_type (leave_type_id, name, annual_days, accrual, carry_over_days) value (?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func CountLeaveType() string {
+	v := viper.GetString("app.query.COUNT_LEAVE_TYPE")
+	if v == "" {
+		return "select count(*) from leave_type where leave_type_id = ?"
+	}
+	return v
+}
+
+func EditLeaveType() string {
+	v := viper.GetString("app.query.EDIT_LEAVE_TYPE")
+	if v == "" {
+		return "update leave_type set name = ?, annual_days = ?, accrual = ?, carry_over_days = ? where leave_type_id = ?"
+	}
+	return v
+}
+
+func DeleteLeaveType() string {
+	v := viper.GetString("app.query.DELETE_LEAVE_TYPE")
+	if v == "" {
+		return "delete from leave_type where leave_type_id = ?"
+	}
+	return v
+}
+
+func CountLeaveRequestByType() string {
+	v := viper.GetString("app.query.COUNT_LEAVE_REQUEST_BY_TYPE")
+	if v == "" {
+		return "select count(*) from leave_request where leave_type_id = ?"
+	}
+	return v
+}
+
+func InsertLeaveRequest() string {
+	v := viper.GetString("app.query.INSERT_LEAVE_REQUEST")
+	if v == "" {
+		return "insert into leave_request (employee_id, leave_type_id, start_date, end_date, days, reason, status) value (?, ?, ?, ?, ?, nullif(?, ''), ?)"
+	}
+	return v
+}
+
+func GetLeaveRequestById() string {
+	v := viper.GetString("app.query.GET_LEAVE_REQUEST_BY_ID")
+	if v == "" {
+		return "select " + leaveRequestColumns + " from leave_request where id = ?"
+	}
+	return v
+}
+
+func GetLeaveRequestsByEmployee() string {
+	v := viper.GetString("app.query.GET_LEAVE_REQUESTS_BY_EMPLOYEE")
+	if v == "" {
+		return "select " + leaveRequestColumns + " from leave_request where employee_id = ? order by start_date desc, id desc"
+	}
+	return v
+}
+
+func CountOverlappingLeaveRequest() string {
+	v := viper.GetString("app.query.COUNT_OVERLAPPING_LEAVE_REQUEST")
+	if v == "" {
+		return "select count(*) from leave_request where employee_id = ? and status in ('pending', 'approved') and start_date <= ? and end_date >= ?"
+	}
+	return v
+}
+
+func EditLeaveRequestStatus() string {
+	v := viper.GetString("app.query.EDIT_LEAVE_REQUEST_STATUS")
+	if v == "" {
+		return "update leave_request set status = ?, approver_id = nullif(?, ''), decision_note = nullif(?, ''), decided_at = current_timestamp where id = ? and status = ?"
+	}
+	return v
+}
+
+func GetLeaveUsage() string {
+	v := viper.GetString("app.query.GET_LEAVE_USAGE")
+	if v == "" {
+		return "select year(start_date), status, sum(days) from leave_request where employee_id = ? and leave_type_id = ? and status in ('pending', 'approved') group by year(start_date), status"
+	}
+	return v
+}
+
+func InsertAttendanceEvent() string {
+	v := viper.GetString("app.query.INSERT_ATTENDANCE_EVENT")
+	if v == "" {
+		return "insert into attendance_event (employee_id, kind, occurred_at, note, capped) value (?, ?, ?, nullif(?, ''), ?)"
+	}
+	return v
+}
+
+func GetLastAttendanceEvent() string {
+	v := viper.GetString("app.query.GET_LAST_ATTENDANCE_EVENT")
+	if v == "" {
+		return "select id, employee_id, kind, cast(occurred_at as char), coalesce(note, ''), capped from attendance_event where employee_id = ? order by occurred_at desc, id desc limit 1"
+	}
+	return v
+}
+
+func GetAttendanceEvents() string {
+	v := viper.GetString("app.query.GET_ATTENDANCE_EVENTS")
+	if v == "" {
+		return "select id, employee_id, kind, cast(occurred_at as char), coalesce(note, ''), capped from attendance_event where employee_id = ? and occurred_at >= ? and occurred_at < ? order by occurred_at, id"
+	}
+	return v
+}
+
+func GetAllAttendanceEvents() string {
+	v := viper.GetString("app.query.GET_ALL_ATTENDANCE_EVENTS")
+	if v == "" {
+		return "select id, employee_id, kind, cast(occurred_at as char), coalesce(note, ''), capped from attendance_event where occurred_at >= ? and occurred_at < ? order by employee_id, occurred_at, id"
+	}
+	return v
+}
+
+func InsertDocument() string {
+	v := viper.GetString("app.query.INSERT_DOCUMENT")
+	if v == "" {
+		return "insert into employee_document (employee_id, file_name, content_type, size, checksum, storage_key) value (?, ?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func GetDocuments() string {
+	v := viper.GetString("app.query.GET_DOCUMENTS")
+	if v == "" {
+		return "select id, employee_id, file_name, content_type, size, checksum, storage_key, cast(created_at as char) from employee_document where employee_id = ? order by id"
+	}
+	return v
+}
+
+func GetDocumentById() string {
+	v := viper.GetString("app.query.GET_DOCUMENT_BY_ID")
+	if v == "" {
+		return "select id, employee_id, file_name, content_type, size, checksum, storage_key, cast(created_at as char) from employee_document where employee_id = ? and id = ?"
+	}
+	return v
+}
+
+func DeleteDocument() string {
+	v := viper.GetString("app.query.DELETE_DOCUMENT")
+	if v == "" {
+		return "delete from employee_document where employee_id = ? and id = ?"
+	}
+	return v
+}
+
+// SearchEmployees ranks by natural language relevance, binding the query
+// twice.
+func SearchEmployees() string {
+	v := viper.GetString("app.query.SEARCH_EMPLOYEES")
+	if v == "" {
+		return "select " + employeeColumnsOf("") + ", match (first_name, last_name, email, phone) against (?) as score from employee" +
+			" where match (first_name, last_name, email, phone) against (?) order by score desc, employee_id limit ?"
+	}
+	return v
+}
+
+func CountEmployeeFullText() string {
+	v := viper.GetString("app.query.COUNT_EMPLOYEE_FULLTEXT")
+	if v == "" {
+		return "select count(*) from information_schema.statistics where table_schema = database() and table_name = 'employee' and index_type = 'FULLTEXT'"
+	}
+	return v
+}
+
+func GetPhoto() string {
+	v := viper.GetString("app.query.GET_PHOTO")
+	if v == "" {
+		return "select employee_id, content_type, width, height, size, checksum, cast(updated_at as char) from employee_photo where employee_id = ?"
+	}
+	return v
+}
+
+func SavePhoto() string {
+	v := viper.GetString("app.query.SAVE_PHOTO")
+	if v == "" {
+		return "insert into employee_photo (employee_id, content_type, width, height, size, checksum) value (?, ?, ?, ?, ?, ?) " +
+			"on duplicate key update content_type = values(content_type), width = values(width), height = values(height), size = values(size), checksum = values(checksum)"
+	}
+	return v
+}
+
+func DeletePhoto() string {
+	v := viper.GetString("app.query.DELETE_PHOTO")
+	if v == "" {
+		return "delete from employee_photo where employee_id = ?"
+	}
+	return v
+}
+
+func InsertOutboxEvent() string {
+	v := viper.GetString("app.query.INSERT_OUTBOX_EVENT")
+	if v == "" {
+		return "insert into outbox_event (event_id, event_type, aggregate_id, payload, occurred_at) value (?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+// GetPendingOutboxEvents locks the oldest unpublished events, so relays in
+// other instances wait rather than publish them out of order.
+func GetPendingOutboxEvents() string {
+	v := viper.GetString("app.query.GET_PENDING_OUTBOX_EVENTS")
+	if v == "" {
+		return "select id, event_id, event_type, aggregate_id, payload, cast(occurred_at as char), attempts from outbox_event" +
+			" where published_at is null order by id limit ? for update"
+	}
+	return v
+}
+
+func MarkOutboxEventPublished() string {
+	v := viper.GetString("app.query.MARK_OUTBOX_EVENT_PUBLISHED")
+	if v == "" {
+		return "update outbox_event set published_at = ?, attempts = attempts + 1, last_error = null where id = ?"
+	}
+	return v
+}
+
+func RecordOutboxEventFailure() string {
+	v := viper.GetString("app.query.RECORD_OUTBOX_EVENT_FAILURE")
+	if v == "" {
+		return "update outbox_event set attempts = attempts + 1, last_error = left(?, 1000) where id = ?"
+	}
+	return v
+}
+
+func DeletePublishedOutboxEvents() string {
+	v := viper.GetString("app.query.DELETE_PUBLISHED_OUTBOX_EVENTS")
+	if v == "" {
+		return "delete from outbox_event where published_at < ?"
+	}
+	return v
+}
+
+func GetWebhookSubscriptions() string {
+	v := viper.GetString("app.query.GET_WEBHOOK_SUBSCRIPTIONS")
+	if v == "" {
+		return "select id, url, secret, event_types, description, active, cast(created_at as char), cast(updated_at as char) from webhook_subscription order by id"
+	}
+	return v
+}
+
+func GetWebhookSubscriptionById() string {
+	v := viper.GetString("app.query.GET_WEBHOOK_SUBSCRIPTION_BY_ID")
+	if v == "" {
+		return "select id, url, secret, event_types, description, active, cast(created_at as char), cast(updated_at as char) from webhook_subscription where id = ?"
+	}
+	return v
+}
+
+func InsertWebhookSubscription() string {
+	v := viper.GetString("app.query.INSERT_WEBHOOK_SUBSCRIPTION")
+	if v == "" {
+		return "insert into webhook_subscription (url, secret, event_types, description, active) value (?, ?, ?, ?, ?)"
+	}
+	return v
+}
+
+func UpdateWebhookSubscription() string {
+	v := viper.GetString("app.query.UPDATE_WEBHOOK_SUBSCRIPTION")
+	if v == "" {
+		return "update webhook_subscription set url = ?, event_types = ?, description = ?, active = ? where id = ?"
+	}
+	return v
+}
+
+func UpdateWebhookSecret() string {
+	v := viper.GetString("app.query.UPDATE_WEBHOOK_SECRET")
+	if v == "" {
+		return "update webhook_subscription set secret = ? where id = ?"
+	}
+	return v
+}
+
+func DeleteWebhookSubscription() string {
+	v := viper.GetString("app.query.DELETE_WEBHOOK_SUBSCRIPTION")
+	if v == "" {
+		return "delete from webhook_subscription where id = ?"
+	}
+	return v
+}
+
+// EnqueueWebhookDeliveries adds a delivery of one event for every active
+// subscription selecting its type, ignoring ones already enqueued.
+func EnqueueWebhookDeliveries() string {
+	v := viper.GetString("app.query.ENQUEUE_WEBHOOK_DELIVERIES")
+	if v == "" {
+		return "insert ignore into webhook_delivery (subscription_id, event_id, event_type, payload, next_attempt_at)" +
+			" select id, ?, ?, ?, ? from webhook_subscription where active and (event_types = '' or find_in_set(?, event_types))"
+	}
+	return v
+}
+
+// GetDueWebhookDeliveries locks the pending deliveries that are due,
+// skipping those another dispatcher has locked.
+func GetDueWebhookDeliveries() string {
+	v := viper.GetString("app.query.GET_DUE_WEBHOOK_DELIVERIES")
+	if v == "" {
+		return "select d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret" +
+			" from webhook_delivery d join webhook_subscription s on s.id = d.subscription_id" +
+			" where d.status = 'pending' and d.next_attempt_at <= ? and s.active" +
+			" order by d.next_attempt_at, d.id limit ? for update of d skip locked"
+	}
+	return v
+}
+
+func LeaseWebhookDelivery() string {
+	v := viper.GetString("app.query.LEASE_WEBHOOK_DELIVERY")
+	if v == "" {
+		return "update webhook_delivery set next_attempt_at = ? where id = ?"
+	}
+	return v
+}
+
+func RecordWebhookDelivery() string {
+	v := viper.GetString("app.query.RECORD_WEBHOOK_DELIVERY")
+	if v == "" {
+		return "update webhook_delivery set status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?," +
+			" response_status = nullif(?, 0), last_error = nullif(left(?, 1000), '') where id = ?"
+	}
+	return v
+}
+
+func InsertWebhookAttempt() string {
+	v := viper.GetString("app.query.INSERT_WEBHOOK_ATTEMPT")
+	if v == "" {
+		return "insert into webhook_delivery_attempt (delivery_id, attempted_at, response_status, error, duration_ms)" +
+			" value (?, ?, nullif(?, 0), nullif(left(?, 1000), ''), ?)"
+	}
+	return v
+}
+
+func GetWebhookDeliveries() string {
+	v := viper.GetString("app.query.GET_WEBHOOK_DELIVERIES")
+	if v == "" {
+		return "select id, subscription_id, event_id, event_type, status, attempts, cast(next_attempt_at as char), coalesce(cast(last_attempt_at as char), '')," +
+			" coalesce(response_status, 0), coalesce(last_error, ''), cast(created_at as char)" +
+			" from webhook_delivery where subscription_id = ? and (? = '' or status = ?) order by id desc limit ?"
+	}
+	return v
+}
+
+func GetWebhookDeliveryById() string {
+	v := viper.GetString("app.query.GET_WEBHOOK_DELIVERY_BY_ID")
+	if v == "" {
+		return "select id, subscription_id, event_id, event_type, status, attempts, cast(next_attempt_at as char), coalesce(cast(last_attempt_at as char), '')," +
+			" coalesce(response_status, 0), coalesce(last_error, ''), cast(created_at as char), payload" +
+			" from webhook_delivery where subscription_id = ? and id = ?"
+	}
+	return v
+}
+
+func GetWebhookAttempts() string {
+	v := viper.GetString("app.query.GET_WEBHOOK_ATTEMPTS")
+	if v == "" {
+		return "select cast(attempted_at as char), coalesce(response_status, 0), coalesce(error, ''), duration_ms" +
+			" from webhook_delivery_attempt where delivery_id = ? order by id"
+	}
+	return v
+}
+
+func RedeliverWebhookDelivery() string {
+	v := viper.GetString("app.query.REDELIVER_WEBHOOK_DELIVERY")
+	if v == "" {
+		return "update webhook_delivery set status = 'pending', attempts = 0, next_attempt_at = ? where subscription_id = ? and id = ?"
+	}
+	return v
+}
+
+func DeleteFinishedWebhookDeliveries() string {
+	v := viper.GetString("app.query.DELETE_FINISHED_WEBHOOK_DELIVERIES")
+	if v == "" {
+		return "delete from webhook_delivery where status <> 'pending' and created_at < ?"
+	}
+	return v
+}
+
+func InsertIdempotencyKey() string {
+	v := viper.GetString("app.query.INSERT_IDEMPOTENCY_KEY")
+	if v == "" {
+		return "insert ignore into idempotency_key (idempotency_key, request_hash, completed, expires_at) value (?, ?, false, ?)"
+	}
+	return v
+}
+
+func GetIdempotencyKey() string {
+	v := viper.GetString("app.query.GET_IDEMPOTENCY_KEY")
+	if v == "" {
+		return "select idempotency_key, request_hash, coalesce(status_code, 0), coalesce(content_type, ''), response_body, completed, expires_at from idempotency_key where idempotency_key = ?"
+	}
+	return v
+}
+
+func CompleteIdempotencyKey() string {
+	v := viper.GetString("app.query.COMPLETE_IDEMPOTENCY_KEY")
+	if v == "" {
+		return "update idempotency_key set status_code = ?, content_type = ?, response_body = ?, completed = true, expires_at = ? where idempotency_key = ?"
+	}
+	return v
+}
+
+func DeleteIdempotencyKey() string {
+	v := viper.GetString("app.query.DELETE_IDEMPOTENCY_KEY")
+	if v == "" {
+		return "delete from idempotency_key where idempotency_key = ?"
+	}
+	return v
+}
+
+func DeleteExpiredIdempotencyKey() string {
+	v := viper.GetString("app.query.DELETE_EXPIRED_IDEMPOTENCY_KEY")
+	if v == "" {
+		return "delete from idempotency_key where idempotency_key = ? and expires_at < ?"
+	}
+	return v
+}
+
+func CreateSchemaMigrationTable() string {
+	v := viper.GetString("app.query.CREATE_SCHEMA_MIGRATION_TABLE")
+	if v == "" {
+		return "create table if not exists schema_migration (version int not null primary key, name varchar(255) not null, applied_at datetime(6) not null, checksum char(64) not null default '')"
+	}
+	return v
+}
+
+func GetSchemaMigrations() string {
+	v := viper.GetString("app.query.GET_SCHEMA_MIGRATIONS")
+	if v == "" {
+		return "select version, name, applied_at, checksum from schema_migration order by version"
+	}
+	return v
+}
+
+// HasSchemaMigrationChecksum selects 1 when schema_migration has the
+// checksum column, which tables created before it was added lack.
+func HasSchemaMigrationChecksum() string {
+	v := viper.GetString("app.query.HAS_SCHEMA_MIGRATION_CHECKSUM")
+	if v == "" {
+		return "select count(*) from information_schema.columns where table_schema = database() and table_name = 'schema_migration' and column_name = 'checksum'"
+	}
+	return v
+}
+
+func AddSchemaMigrationChecksum() string {
+	v := viper.GetString("app.query.ADD_SCHEMA_MIGRATION_CHECKSUM")
+	if v == "" {
+		return "alter table schema_migration add column checksum char(64) not null default ''"
+	}
+	return v
+}
+
+func UpdateSchemaMigrationChecksum() string {
+	v := viper.GetString("app.query.UPDATE_SCHEMA_MIGRATION_CHECKSUM")
+	if v == "" {
+		return "update schema_migration set checksum = ? where version = ?"
+	}
+	return v
+}
+
+func InsertSchemaMigration() string {
+	v := viper.GetString("app.query.INSERT_SCHEMA_MIGRATION")
+	if v == "" {
+		return "insert into schema_migration (version, name, applied_at, checksum) value (?, ?, ?, ?)"
+	}
+	return v
+}
+
+// LockSchemaMigrations waits up to the given seconds for the lock that
+// keeps migration runs from overlapping; it selects 1 once the lock is held.
+func LockSchemaMigrations() string {
+	v := viper.GetString("app.query.LOCK_SCHEMA_MIGRATIONS")
+	if v == "" {
+		return "select get_lock('schema_migration', ?)"
+	}
+	return v
+}
+
+func UnlockSchemaMigrations() string {
+	v := viper.GetString("app.query.UNLOCK_SCHEMA_MIGRATIONS")
+	if v == "" {
+		return "select release_lock('schema_migration')"
+	}
+	return v
+}
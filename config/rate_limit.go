@@ -0,0 +1,79 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a token-bucket quota: Limit requests per Period, refilled
+// continuously.
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+func IsRateLimitEnabled() bool {
+	if !viper.IsSet("ratelimit.enabled") {
+		return true
+	}
+	return viper.GetBool("ratelimit.enabled")
+}
+
+func GetDefaultRateLimit() RateLimit {
+	limit := viper.GetInt("ratelimit.default.limit")
+	if limit <= 0 {
+		limit = 120
+	}
+	period := viper.GetDuration("ratelimit.default.period")
+	if period <= 0 {
+		period = time.Minute
+	}
+	return RateLimit{Limit: limit, Period: period}
+}
+
+// GetRateLimitMaxBuckets caps the buckets kept in memory, one per client
+// and route.
+func GetRateLimitMaxBuckets() int {
+	v := viper.GetInt("ratelimit.max_buckets")
+	if v <= 0 {
+		return 100000
+	}
+	return v
+}
+
+// GetRouteRateLimits parses ratelimit.routes, a ";"-separated list of
+// "METHOD /route/path=limit/period" entries, for example
+// "GET /api/v1/employees/:id=300/1m;POST /api/v1/employees=20/1m".
+// The route path is the echo route template, not the concrete URL.
+func GetRouteRateLimits() map[string]RateLimit {
+	res := map[string]RateLimit{}
+	for _, entry := range strings.Split(viper.GetString("ratelimit.routes"), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		eq := strings.LastIndex(entry, "=")
+		if eq < 0 {
+			logrus.Warnf("ignoring rate limit entry without quota: %s", entry)
+			continue
+		}
+		route, quota := strings.TrimSpace(entry[:eq]), entry[eq+1:]
+		limitStr, periodStr, _ := strings.Cut(quota, "/")
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit <= 0 {
+			logrus.Warnf("ignoring rate limit entry with invalid limit: %s", entry)
+			continue
+		}
+		period, err := time.ParseDuration(strings.TrimSpace(periodStr))
+		if err != nil || period <= 0 {
+			logrus.Warnf("ignoring rate limit entry with invalid period: %s", entry)
+			continue
+		}
+		method, path, _ := strings.Cut(route, " ")
+		res[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = RateLimit{Limit: limit, Period: period}
+	}
+	return res
+}
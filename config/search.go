@@ -0,0 +1,35 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+// GetSearchBackend is "fulltext", "memory" or "auto" (default), which uses
+// the employee FULLTEXT index when the database has one and the in-process
+// index otherwise.
+func GetSearchBackend() string {
+	v := viper.GetString("search.backend")
+	if v == "" {
+		return "auto"
+	}
+	return v
+}
+
+// GetSearchRefreshInterval is how long the in-process index trusts its own
+// updates before reloading, which picks up writes made by other instances.
+func GetSearchRefreshInterval() time.Duration {
+	v := viper.GetDuration("search.refresh_interval")
+	if v <= 0 {
+		return 5 * time.Minute
+	}
+	return v
+}
+
+func GetSearchMaxResults() int {
+	v := viper.GetInt("search.max_results")
+	if v <= 0 {
+		return 100
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"strconv"
+	"strings"
+)
+
+// GetStorageBackend is either "filesystem" (default) or "s3".
+func GetStorageBackend() string {
+	v := viper.GetString("storage.backend")
+	if v == "" {
+		return "filesystem"
+	}
+	return v
+}
+
+func GetStorageRoot() string {
+	v := viper.GetString("storage.filesystem.root")
+	if v == "" {
+		return "./data/documents"
+	}
+	return v
+}
+
+func GetS3Endpoint() string {
+	v := viper.GetString("storage.s3.endpoint")
+	if v == "" {
+		return "http://127.0.0.1:9000"
+	}
+	return v
+}
+
+func GetS3Region() string {
+	v := viper.GetString("storage.s3.region")
+	if v == "" {
+		return "us-east-1"
+	}
+	return v
+}
+
+func GetS3Bucket() string {
+	v := viper.GetString("storage.s3.bucket")
+	if v == "" {
+		return "employee-documents"
+	}
+	return v
+}
+
+func GetS3AccessKey() string {
+	return viper.GetString("storage.s3.access_key")
+}
+
+func GetS3SecretKey() string {
+	return viper.GetString("storage.s3.secret_key")
+}
+
+// IsS3PathStyle addresses objects as endpoint/bucket/key, which local
+// stand-ins such as MinIO expect. It is on by default.
+func IsS3PathStyle() bool {
+	if !viper.IsSet("storage.s3.path_style") {
+		return true
+	}
+	return viper.GetBool("storage.s3.path_style")
+}
+
+// GetDocumentMaxSize is the largest accepted upload in bytes, 10 MiB by
+// default.
+func GetDocumentMaxSize() int64 {
+	v := viper.GetInt64("documents.max_size")
+	if v <= 0 {
+		return 10 << 20
+	}
+	return v
+}
+
+// GetDocumentContentTypes lists the media types uploads may sniff as, from
+// the comma-separated documents.content_types.
+func GetDocumentContentTypes() []string {
+	v := viper.GetString("documents.content_types")
+	if v == "" {
+		return []string{"application/pdf", "image/jpeg", "image/png", "image/webp", "text/plain"}
+	}
+	var types []string
+	for _, t := range strings.Split(v, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// GetPhotoMaxSize is the largest accepted photo upload in bytes, 5 MiB by
+// default.
+func GetPhotoMaxSize() int64 {
+	v := viper.GetInt64("photos.max_size")
+	if v <= 0 {
+		return 5 << 20
+	}
+	return v
+}
+
+// GetPhotoDimensions bounds the width and height of uploaded photos, 64 to
+// 4096 pixels by default. The maximum also keeps decoding memory bounded.
+func GetPhotoDimensions() (min, max int) {
+	min, max = viper.GetInt("photos.min_dimension"), viper.GetInt("photos.max_dimension")
+	if min <= 0 {
+		min = 64
+	}
+	if max <= 0 {
+		max = 4096
+	}
+	return min, max
+}
+
+// GetPhotoThumbnailSizes lists the square bounding boxes thumbnails are
+// generated for, from the comma-separated photos.thumbnail_sizes.
+func GetPhotoThumbnailSizes() []int {
+	var sizes []int
+	for _, s := range strings.Split(viper.GetString("photos.thumbnail_sizes"), ",") {
+		if size, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && size > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		return []int{64, 256}
+	}
+	return sizes
+}
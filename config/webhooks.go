@@ -0,0 +1,94 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"time"
+)
+
+// IsWebhookDispatcherEnabled starts the dispatcher sending webhook
+// deliveries. Deliveries are enqueued by the event relay when events.sinks
+// includes webhooks.
+func IsWebhookDispatcherEnabled() bool {
+	return viper.GetBool("webhooks.dispatcher.enabled")
+}
+
+func GetWebhookInterval() time.Duration {
+	v := viper.GetDuration("webhooks.dispatcher.interval")
+	if v <= 0 {
+		return 5 * time.Second
+	}
+	return v
+}
+
+func GetWebhookBatchSize() int {
+	v := viper.GetInt("webhooks.dispatcher.batch_size")
+	if v <= 0 {
+		return 50
+	}
+	return v
+}
+
+// GetWebhookConcurrency is how many deliveries are sent at once.
+func GetWebhookConcurrency() int {
+	v := viper.GetInt("webhooks.dispatcher.concurrency")
+	if v <= 0 {
+		return 4
+	}
+	return v
+}
+
+func GetWebhookTimeout() time.Duration {
+	v := viper.GetDuration("webhooks.timeout")
+	if v <= 0 {
+		return 10 * time.Second
+	}
+	return v
+}
+
+// GetWebhookMaxAttempts is how many failed attempts dead-letter a delivery.
+func GetWebhookMaxAttempts() int {
+	v := viper.GetInt("webhooks.retry.max_attempts")
+	if v <= 0 {
+		return 10
+	}
+	return v
+}
+
+// GetWebhookBackoff returns the wait after the first failed attempt, which
+// doubles with every further failure, and the longest wait.
+func GetWebhookBackoff() (initial, longest time.Duration) {
+	initial = viper.GetDuration("webhooks.retry.initial_interval")
+	if initial <= 0 {
+		initial = 30 * time.Second
+	}
+	longest = viper.GetDuration("webhooks.retry.max_interval")
+	if longest <= 0 {
+		longest = 6 * time.Hour
+	}
+	if longest < initial {
+		longest = initial
+	}
+	return initial, longest
+}
+
+// GetWebhookRetention is how long finished deliveries stay in the log.
+func GetWebhookRetention() time.Duration {
+	v := viper.GetDuration("webhooks.retention")
+	if v <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return v
+}
+
+// IsWebhookHTTPAllowed accepts plain http subscription URLs, which are
+// rejected by default.
+func IsWebhookHTTPAllowed() bool {
+	return viper.GetBool("webhooks.allow_http")
+}
+
+// IsWebhookPrivateNetworkAllowed lets deliveries reach loopback, private
+// and link-local addresses, which are refused by default so subscriptions
+// cannot probe the internal network.
+func IsWebhookPrivateNetworkAllowed() bool {
+	return viper.GetBool("webhooks.allow_private_networks")
+}
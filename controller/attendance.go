@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"employee-golang/util"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"strings"
+)
+
+type AttendanceHandler struct {
+	Service service.IAttendanceService
+}
+
+// AttendanceController registers the attendance routes.
+func AttendanceController(e *echo.Echo) {
+	handler := &AttendanceHandler{
+		Service: service.NewAttendanceService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the attendance routes on e.
+func (handler *AttendanceHandler) Routes(e *echo.Echo) {
+	employees := apiGroup(e, "/employees")
+	employees.Add("POST", "/:id/clock-in", handler.ClockIn, openapi.Operation{
+		Summary: "Clock in, now when the body is empty", Body: &model.Clock{}, OptionalBody: true, Response: &model.AttendanceEvent{},
+	})
+	employees.Add("POST", "/:id/clock-out", handler.ClockOut, openapi.Operation{
+		Summary: "Clock out, now when the body is empty", Body: &model.Clock{}, OptionalBody: true, Response: &model.AttendanceEvent{},
+	})
+	employees.Add("GET", "/:id/attendance", handler.GetAttendance, openapi.Operation{
+		Summary:  "List clock events, the current week by default",
+		Query:    []openapi.Param{{Name: "from", Description: "YYYY-MM-DD"}, {Name: "to", Description: "YYYY-MM-DD, inclusive"}},
+		Response: []*model.AttendanceEvent{},
+	})
+	employees.Add("GET", "/:id/timesheet", handler.GetTimesheet, openapi.Operation{
+		Summary:  "Get the timesheet of a week",
+		Query:    []openapi.Param{{Name: "date", Description: "YYYY-MM-DD within the week"}},
+		Response: &model.Timesheet{},
+	})
+
+	reports := apiGroup(e, "/reports")
+	reports.Add("GET", "/hours", handler.GetHoursReport, openapi.Operation{
+		Summary: "Report hours per employee and period",
+		Query: []openapi.Param{
+			{Name: "from", Description: "YYYY-MM-DD"},
+			{Name: "to", Description: "YYYY-MM-DD, inclusive"},
+			{Name: "period", Description: "day, week or month"},
+			{Name: "format", Description: "csv to answer in CSV"},
+		},
+		Response: []*model.HoursReportRow{},
+	})
+}
+
+func (handler *AttendanceHandler) ClockIn(c echo.Context) error {
+	return handler.clock(c, model.ClockIn)
+}
+
+func (handler *AttendanceHandler) ClockOut(c echo.Context) error {
+	return handler.clock(c, model.ClockOut)
+}
+
+// clock accepts an empty body, which clocks at the current time.
+func (handler *AttendanceHandler) clock(c echo.Context, kind string) error {
+	rq := new(model.Clock)
+	if c.Request().ContentLength != 0 {
+		if ok, err := bindAndValidate(c, rq); !ok {
+			return err
+		}
+	}
+	body, err := handler.Service.Clock(requestContext(c), c.Param(`id`), kind, rq)
+	return attendanceResponse(c, body, err, "Error recording attendance")
+}
+
+// GetAttendance lists clock events between the "from" and "to" query
+// parameters (YYYY-MM-DD, inclusive), the current week when omitted.
+func (handler *AttendanceHandler) GetAttendance(c echo.Context) error {
+	response, err := handler.Service.GetAttendance(requestContext(c), c.Param(`id`), c.QueryParam("from"), c.QueryParam("to"))
+	return attendanceResponse(c, response, err, "Error getting attendance")
+}
+
+// GetTimesheet returns the week containing the "date" query parameter.
+func (handler *AttendanceHandler) GetTimesheet(c echo.Context) error {
+	response, err := handler.Service.GetTimesheet(requestContext(c), c.Param(`id`), c.QueryParam("date"))
+	return attendanceResponse(c, response, err, "Error getting timesheet")
+}
+
+// GetHoursReport returns hours per employee per "period" (day, week or
+// month) between "from" and "to", in the format the Accept header prefers
+// like other lists. "format=csv" asks for CSV whatever the header, for
+// links opened in a browser; the CSV is offered as a download.
+func (handler *AttendanceHandler) GetHoursReport(c echo.Context) error {
+	if strings.EqualFold(c.QueryParam("format"), "csv") {
+		c.Request().Header.Set(echo.HeaderAccept, util.MIMETextCSV)
+	}
+	response, err := handler.Service.GetHoursReport(requestContext(c), c.QueryParam("from"), c.QueryParam("to"), c.QueryParam("period"))
+	if err == nil && util.ResponseType(c.Request().Header.Get(echo.HeaderAccept)) == util.MIMETextCSV {
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="hours.csv"`)
+	}
+	return attendanceResponse(c, response, err, "Error getting hours report")
+}
+
+func attendanceResponse(c echo.Context, response interface{}, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrAlreadyClockedIn), errors.Is(err, model.ErrNotClockedIn), errors.Is(err, model.ErrClockOrder):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrClockInactive), errors.Is(err, model.ErrClockFuture), errors.Is(err, model.ErrReportPeriod):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
@@ -1,45 +1,74 @@
 package controller
 
 import (
+	"context"
 	"database/sql"
 	model "employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/openapi"
 	"employee-golang/service"
 	"employee-golang/util"
 	"errors"
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
 )
 
+// HeaderNextCursor carries the cursor of the next page of a paged list.
+const HeaderNextCursor = "X-Next-Cursor"
+
 type Controller struct {
-	Service service.IEmployeeService
+	Service      service.IEmployeeService
+	Compensation service.ICompensationService
 }
 
 func EmployeeController(e *echo.Echo) {
 	handler := &Controller{
-		Service: service.NewEmployeeService(),
+		Service:      service.NewEmployeeService(),
+		Compensation: service.NewCompensationService(),
 	}
+	handler.Routes(e)
+}
 
-	apis := e.Group("/api/v1/employees")
-	apis.Add("GET", "", handler.GetEmployee)
-	apis.Add("GET", "/:id", handler.GetEmployeeById)
-	apis.Add("POST", "", handler.InsertEmployee)
-	apis.Add("PUT", "", handler.UpdateEmployee)
-	apis.Add("DELETE", "/:id", handler.DeleteEmployee)
-
-	e.Logger.Fatal(e.Start(":8080"))
+// Routes registers the employee routes on e.
+func (controller *Controller) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/employees")
+	apis.Add("GET", "", controller.GetEmployee, openapi.Operation{
+		Summary: "List employees",
+		Query: []openapi.Param{
+			{Name: "status", Description: "comma-separated statuses to keep"},
+			{Name: "limit", Type: "integer", Description: "page size; pages in id order"},
+			{Name: "after", Description: "id to continue after, from X-Next-Cursor"},
+		},
+		Response: []*model.Employee{},
+	})
+	apis.Add("GET", "/:id", controller.GetEmployeeById, openapi.Operation{Summary: "Get an employee", Response: &model.Employee{}})
+	apis.Add("POST", "", controller.InsertEmployee, openapi.Operation{Summary: "Add an employee", Body: &model.Employee{}, Response: ""})
+	apis.Add("PUT", "", controller.UpdateEmployee, openapi.Operation{Summary: "Update an employee", Body: &model.Employee{}, Response: ""})
+	apis.Add("DELETE", "/:id", controller.DeleteEmployee, openapi.Operation{Summary: "Delete an employee", Response: ""})
+	apis.Add("GET", "/:id/reports", controller.GetDirectReports, openapi.Operation{Summary: "List direct reports", Response: []*model.Employee{}})
+	apis.Add("GET", "/:id/chain", controller.GetReportingChain, openapi.Operation{Summary: "List managers up to the top", Response: []*model.Employee{}})
+	apis.Add("GET", "/:id/org-chart", controller.GetOrgChart, openapi.Operation{Summary: "Get the org chart below an employee", Response: &model.OrgChartNode{}})
+	apis.Add("GET", "/:id/salary-overrides", controller.GetSalaryOverrides, openapi.Operation{Summary: "List salaries outside the position band", Response: []*model.SalaryOverride{}})
+	apis.Add("GET", "/:id/compensation", controller.GetCompensationHistory, openapi.Operation{Summary: "List compensation changes", Response: []*model.Compensation{}})
+	apis.Add("GET", "/:id/compensation/as-of", controller.GetCompensationAsOf, openapi.Operation{
+		Summary:  "Get the compensation in effect on a date",
+		Query:    []openapi.Param{{Name: "date", Description: "YYYY-MM-DD"}},
+		Response: &model.Compensation{},
+	})
+	apis.Add("POST", "/:id/compensation", controller.ScheduleCompensation, openapi.Operation{Summary: "Schedule a compensation change", Body: &model.Compensation{}, Response: ""})
+	apis.Add("POST", "/:id/status", controller.ChangeStatus, openapi.Operation{Summary: "Change the employee status", Body: &model.StatusChange{}, Response: ""})
+	apis.Add("GET", "/:id/status-history", controller.GetStatusHistory, openapi.Operation{Summary: "List status changes", Response: []*model.StatusChange{}})
+	apis.Add("POST", "/:id/rehire", controller.Rehire, openapi.Operation{Summary: "Rehire a terminated employee", Body: &model.Rehire{}, Response: ""})
 }
 
 func (controller *Controller) InsertEmployee(c echo.Context) error {
 	rq := new(model.Employee)
 
-	c.Echo().Validator = &CustomValidator{
-		Validator: validator.New(),
-	}
 	errBind := c.Bind(&rq)
 	if errBind != nil {
-		logrus.Printf("Body request is required %v", errBind)
-		return createErrorResponse(c, 400, "BAD_REQUEST", "Body required", "Body request is required", errBind)
+		return bindError(c, errBind)
 	}
 	errValidate := c.Validate(rq)
 	if errValidate != nil {
@@ -47,11 +76,13 @@ func (controller *Controller) InsertEmployee(c echo.Context) error {
 		return createErrorResponse(c, 400, "BAD_REQUEST", errValidate.Error(), "Error: "+errValidate.Error(), errValidate)
 	}
 
-	body, err := controller.Service.InsertEmployee(rq)
+	body, err := controller.Service.InsertEmployee(requestContext(c), rq)
 	switch {
 	case err != nil && err.Error() == "employee already exists":
 		logrus.Printf("Error: %v", err)
 		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case isInvalidEmployeeError(err):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
 	case err != nil:
 		logrus.Printf("Error getting employees %s", err)
 		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting employees", err)
@@ -65,13 +96,9 @@ func (controller *Controller) InsertEmployee(c echo.Context) error {
 func (controller *Controller) UpdateEmployee(c echo.Context) error {
 	rq := new(model.Employee)
 
-	c.Echo().Validator = &CustomValidator{
-		validator.New(),
-	}
 	errBind := c.Bind(rq)
 	if errBind != nil {
-		logrus.Printf("Body request is required %v", errBind)
-		return createErrorResponse(c, 400, "BAD_REQUEST", "Body required", "Body request is required", errBind)
+		return bindError(c, errBind)
 	}
 	errValidate := c.Validate(rq)
 	if errValidate != nil {
@@ -79,31 +106,59 @@ func (controller *Controller) UpdateEmployee(c echo.Context) error {
 		return createErrorResponse(c, 400, "BAD_REQUEST", errValidate.Error(), "Error: "+errValidate.Error(), errValidate)
 	}
 
-	body, err := controller.Service.UpdateEmployee(rq)
+	body, err := controller.Service.UpdateEmployee(requestContext(c), rq)
+	if isInvalidEmployeeError(err) {
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	}
 	if err != nil {
 		logrus.Printf("Error updating employees %s", err)
 		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting employees", err)
 	}
 	return createSuccessResponse(c, 200, body)
 }
+
+// GetEmployee lists employees, optionally only those whose status is in the
+// comma-separated "status" query parameter. With ?limit= the list is paged
+// in id order: ?after= continues after the given id, and the X-Next-Cursor
+// header carries the id to continue after while more employees follow.
 func (controller *Controller) GetEmployee(c echo.Context) error {
-	response, err := controller.Service.GetEmployees()
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		var errLimit error
+		if limit, errLimit = strconv.Atoi(v); errLimit != nil || limit <= 0 {
+			return createErrorResponse(c, 400, "BAD_REQUEST", "limit must be a positive number", "Error: invalid limit", errLimit)
+		}
+	}
+	response, err := controller.Service.GetEmployeesByStatus(requestContext(c), c.QueryParam("status"))
+	if errors.Is(err, model.ErrUnknownStatus) {
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	}
 	if err != nil {
 		logrus.Printf("Error getting employees %v", err)
 		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting employees", err)
 	}
+	if limit > 0 {
+		page, more := service.PageEmployees(response, c.QueryParam("after"), limit)
+		pagination := &model.Pagination{Limit: limit, HasMore: more}
+		if more {
+			pagination.NextCursor = page[len(page)-1].IdEmployee
+			c.Response().Header().Set(HeaderNextCursor, pagination.NextCursor)
+		}
+		util.SetPagination(c, pagination)
+		response = page
+	}
 
 	return createSuccessResponse(c, 200, response)
 }
 
 func (controller *Controller) GetEmployeeById(c echo.Context) error {
 	id := c.Param(`id`)
-	response, err := controller.Service.GetEmployeeById(id)
+	response, err := controller.Service.GetEmployeeById(requestContext(c), id)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
-	case false:
+	case err != nil:
 		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting employees", err)
 	}
 
@@ -113,15 +168,121 @@ func (controller *Controller) GetEmployeeById(c echo.Context) error {
 func (controller *Controller) DeleteEmployee(c echo.Context) error {
 	id := c.Param(`id`)
 
-	response, err := controller.Service.DeleteEmployee(id)
+	response, err := controller.Service.DeleteEmployee(requestContext(c), id)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
-	case !errors.Is(err, sql.ErrNoRows):
-		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting employees", err)
+	case errors.Is(err, model.ErrEmployeeHasReports):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error deleting employee", err)
+	}
+
+	return createSuccessResponse(c, 200, response)
+}
+
+func (controller *Controller) GetDirectReports(c echo.Context) error {
+	response, err := controller.Service.GetDirectReports(requestContext(c), c.Param(`id`))
+	return hierarchyResponse(c, response, err)
+}
+
+func (controller *Controller) GetReportingChain(c echo.Context) error {
+	response, err := controller.Service.GetReportingChain(requestContext(c), c.Param(`id`))
+	return hierarchyResponse(c, response, err)
+}
+
+func (controller *Controller) GetOrgChart(c echo.Context) error {
+	response, err := controller.Service.GetOrgChart(requestContext(c), c.Param(`id`))
+	return hierarchyResponse(c, response, err)
+}
+
+func (controller *Controller) GetSalaryOverrides(c echo.Context) error {
+	response, err := controller.Service.GetSalaryOverrides(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting salary overrides", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (controller *Controller) GetCompensationHistory(c echo.Context) error {
+	response, err := controller.Compensation.GetCompensationHistory(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting compensation history", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+// GetCompensationAsOf returns the salary in effect on the "date" query
+// parameter (YYYY-MM-DD), today when omitted.
+func (controller *Controller) GetCompensationAsOf(c echo.Context) error {
+	date := c.QueryParam("date")
+	if _, errDate := time.Parse("2006-01-02", date); date != "" && errDate != nil {
+		return createErrorResponse(c, 400, "BAD_REQUEST", "date must be formatted as YYYY-MM-DD", "Error: invalid date", errDate)
+	}
+	response, err := controller.Compensation.GetCompensationAsOf(requestContext(c), c.Param(`id`), date)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting compensation", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (controller *Controller) ScheduleCompensation(c echo.Context) error {
+	rq := new(model.Compensation)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	rq.IdEmployee = c.Param(`id`)
+
+	body, err := controller.Compensation.ScheduleCompensation(requestContext(c), rq)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrEffectiveDatePast) || isInvalidEmployeeError(err):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error scheduling compensation", err)
 	}
+	return createSuccessResponse(c, 200, body)
+}
 
+// isInvalidEmployeeError reports whether an insert or update was rejected
+// because the request references or implies something invalid.
+func isInvalidEmployeeError(err error) bool {
+	for _, target := range []error{
+		model.ErrDepartmentNotFound,
+		model.ErrManagerNotFound,
+		model.ErrManagerCycle,
+		model.ErrHierarchyTooDeep,
+		model.ErrPositionNotFound,
+		model.ErrSalaryOutOfBand,
+		money.ErrUnknownCurrency,
+		model.ErrStatusTransition,
+		model.ErrStatusNotEditable,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func hierarchyResponse(c echo.Context, response interface{}, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting reporting line", err)
+	}
 	return createSuccessResponse(c, 200, response)
 }
 
@@ -143,3 +304,7 @@ func createSuccessResponse(c echo.Context, code int, data interface{}) error {
 	}
 	return util.RespJSONData(c, code, response, nil)
 }
+
+func requestContext(c echo.Context) context.Context {
+	return util.WithClient(c.Request().Context(), util.ClientId(c))
+}
@@ -1,6 +1,8 @@
 package controller
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
+)
 
 type CustomValidator struct {
 	Validator *validator.Validate
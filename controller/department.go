@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type DepartmentHandler struct {
+	Service service.IDepartmentService
+}
+
+// DepartmentController registers the department routes.
+func DepartmentController(e *echo.Echo) {
+	handler := &DepartmentHandler{
+		Service: service.NewDepartmentService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the department routes on e.
+func (handler *DepartmentHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/departments")
+	apis.Add("GET", "", handler.GetDepartment, openapi.Operation{Summary: "List departments", Response: []*model.Department{}})
+	apis.Add("GET", "/:id", handler.GetDepartmentById, openapi.Operation{Summary: "Get a department", Response: &model.Department{}})
+	apis.Add("GET", "/:id/employees", handler.GetDepartmentEmployees, openapi.Operation{Summary: "List the employees of a department", Response: []*model.Employee{}})
+	apis.Add("POST", "", handler.InsertDepartment, openapi.Operation{Summary: "Add a department", Body: &model.Department{}, Response: ""})
+	apis.Add("PUT", "", handler.UpdateDepartment, openapi.Operation{Summary: "Update a department", Body: &model.Department{}, Response: ""})
+	apis.Add("DELETE", "/:id", handler.DeleteDepartment, openapi.Operation{Summary: "Delete a department", Response: ""})
+}
+
+func (handler *DepartmentHandler) GetDepartment(c echo.Context) error {
+	response, err := handler.Service.GetDepartments(requestContext(c))
+	if err != nil {
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting departments", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *DepartmentHandler) GetDepartmentById(c echo.Context) error {
+	response, err := handler.Service.GetDepartmentById(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting department", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *DepartmentHandler) GetDepartmentEmployees(c echo.Context) error {
+	response, err := handler.Service.GetDepartmentEmployees(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting department employees", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *DepartmentHandler) InsertDepartment(c echo.Context) error {
+	rq := new(model.Department)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.InsertDepartment(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrDepartmentExists):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error inserting department", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *DepartmentHandler) UpdateDepartment(c echo.Context) error {
+	rq := new(model.Department)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.UpdateDepartment(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrDepartmentNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error updating department", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *DepartmentHandler) DeleteDepartment(c echo.Context) error {
+	response, err := handler.Service.DeleteDepartment(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, model.ErrDepartmentNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrDepartmentInUse):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error deleting department", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+// bindError answers a body that could not be bound: 415 for a content type
+// no binder reads, 400 otherwise.
+func bindError(c echo.Context, err error) error {
+	logrus.Printf("Body request is required %v", err)
+	if errors.Is(err, echo.ErrUnsupportedMediaType) {
+		return createErrorResponse(c, 415, "UNSUPPORTED_MEDIA_TYPE", "Supported body types are application/json, application/xml and application/msgpack", "Unsupported body type", err)
+	}
+	return createErrorResponse(c, 400, "BAD_REQUEST", "Body required", "Body request is required", err)
+}
+
+// bindAndValidate binds the request body into rq and validates it. When
+// either step fails it writes the 400 response and returns ok false.
+func bindAndValidate(c echo.Context, rq interface{}) (ok bool, err error) {
+	errBind := c.Bind(rq)
+	if errBind != nil {
+		return false, bindError(c, errBind)
+	}
+	errValidate := c.Validate(rq)
+	if errValidate != nil {
+		logrus.Printf("Error: %s", errValidate)
+		return false, createErrorResponse(c, 400, "BAD_REQUEST", errValidate.Error(), "Error: "+errValidate.Error(), errValidate)
+	}
+	return true, nil
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"employee-golang/storage"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+type DocumentHandler struct {
+	Service service.IDocumentService
+}
+
+// DocumentController registers the employee document routes.
+func DocumentController(e *echo.Echo) {
+	handler := &DocumentHandler{
+		Service: service.NewDocumentService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the employee document routes on e.
+func (handler *DocumentHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/employees")
+	apis.Add("POST", "/:id/documents", handler.UploadDocument, openapi.Operation{
+		Summary: "Upload a document", Upload: true, Status: 201, Response: &model.Document{},
+	})
+	apis.Add("GET", "/:id/documents", handler.GetDocuments, openapi.Operation{Summary: "List documents", Response: []*model.Document{}})
+	apis.Add("GET", "/:id/documents/:documentId", handler.DownloadDocument, openapi.Operation{Summary: "Download a document", Produces: []string{"*/*"}})
+	apis.Add("DELETE", "/:id/documents/:documentId", handler.DeleteDocument, openapi.Operation{Summary: "Delete a document", Response: ""})
+}
+
+// multipartOverhead leaves room for the multipart framing around the file
+// when the request body is capped.
+const multipartOverhead = 64 << 10
+
+// UploadDocument takes a multipart/form-data body with the file in the
+// "file" field.
+func (handler *DocumentHandler) UploadDocument(c echo.Context) error {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, handler.Service.MaxSize()+multipartOverhead)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return createErrorResponse(c, 413, "PAYLOAD_TOO_LARGE", model.ErrDocumentTooLarge.Error(), "Error: "+err.Error(), err)
+		}
+		return createErrorResponse(c, 400, "BAD_REQUEST", "multipart field \"file\" is required", "Error: "+err.Error(), err)
+	}
+	content, err := file.Open()
+	if err != nil {
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	}
+	defer content.Close()
+
+	body, err := handler.Service.UploadDocument(requestContext(c), c.Param(`id`), file.Filename, content)
+	return documentResponse(c, 201, body, err, "Error uploading document")
+}
+
+func (handler *DocumentHandler) GetDocuments(c echo.Context) error {
+	response, err := handler.Service.GetDocuments(requestContext(c), c.Param(`id`))
+	return documentResponse(c, 200, response, err, "Error getting documents")
+}
+
+// DownloadDocument streams the content with its sniffed type and the hex
+// SHA-256 checksum in X-Checksum-Sha256.
+func (handler *DocumentHandler) DownloadDocument(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`documentId`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	document, content, err := handler.Service.OpenDocument(requestContext(c), c.Param(`id`), id)
+	if err != nil {
+		return documentResponse(c, 200, nil, err, "Error downloading document")
+	}
+	defer content.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(document.Size, 10))
+	header.Set("X-Checksum-Sha256", document.Checksum)
+	header.Set("ETag", `"`+document.Checksum+`"`)
+	return c.Stream(200, document.ContentType, io.Reader(content))
+}
+
+func (handler *DocumentHandler) DeleteDocument(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`documentId`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.DeleteDocument(requestContext(c), c.Param(`id`), id)
+	return documentResponse(c, 200, response, err, "Error deleting document")
+}
+
+func documentResponse(c echo.Context, code int, response interface{}, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, storage.ErrNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrDocumentTooLarge):
+		return createErrorResponse(c, 413, "PAYLOAD_TOO_LARGE", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrDocumentType):
+		return createErrorResponse(c, 415, "UNSUPPORTED_MEDIA_TYPE", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrDocumentEmpty):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, code, response)
+}
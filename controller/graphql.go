@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"employee-golang/graphqlapi"
+	"employee-golang/service"
+	"encoding/json"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type GraphQLHandler struct {
+	Server *graphqlapi.Server
+}
+
+// GraphQLController registers the GraphQL endpoint.
+func GraphQLController(e *echo.Echo) {
+	server, err := graphqlapi.NewServer(service.NewEmployeeService(), service.NewDepartmentService(), service.NewPositionService())
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	handler := &GraphQLHandler{
+		Server: server,
+	}
+
+	e.Add("POST", "/graphql", handler.Query)
+	e.Add("GET", "/graphql", handler.Query)
+}
+
+// Query answers with the GraphQL response itself rather than the usual
+// envelope, so that GraphQL clients can read it. Over GET the request comes
+// in ?query=, ?operationName= and a JSON encoded ?variables=, and only
+// queries are allowed.
+func (handler *GraphQLHandler) Query(c echo.Context) error {
+	rq := graphqlapi.Request{}
+	if c.Request().Method == "GET" {
+		rq.Query = c.QueryParam("query")
+		rq.OperationName = c.QueryParam("operationName")
+		rq.QueryOnly = true
+		if v := c.QueryParam("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &rq.Variables); err != nil {
+				return graphQLError(c, "variables must be a JSON object")
+			}
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(&rq); err != nil {
+		return graphQLError(c, "Body must be a JSON GraphQL request")
+	}
+	if rq.Query == "" {
+		return graphQLError(c, "query is required")
+	}
+
+	res, rejected := handler.Server.Execute(requestContext(c), rq)
+	if rejected {
+		return c.JSON(400, res)
+	}
+	return c.JSON(200, res)
+}
+
+func graphQLError(c echo.Context, message string) error {
+	return c.JSON(400, &graphql.Result{Errors: gqlerrors.FormatErrors(gqlerrors.NewFormattedError(message))})
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"strconv"
+)
+
+type LeaveHandler struct {
+	Service service.ILeaveService
+}
+
+// LeaveController registers the leave routes.
+func LeaveController(e *echo.Echo) {
+	handler := &LeaveHandler{
+		Service: service.NewLeaveService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the leave routes on e.
+func (handler *LeaveHandler) Routes(e *echo.Echo) {
+	types := apiGroup(e, "/leave-types")
+	types.Add("GET", "", handler.GetLeaveTypes, openapi.Operation{Summary: "List leave types", Response: []*model.LeaveType{}})
+	types.Add("GET", "/:id", handler.GetLeaveTypeById, openapi.Operation{Summary: "Get a leave type", Response: &model.LeaveType{}})
+	types.Add("POST", "", handler.InsertLeaveType, openapi.Operation{Summary: "Add a leave type", Body: &model.LeaveType{}, Response: ""})
+	types.Add("PUT", "", handler.UpdateLeaveType, openapi.Operation{Summary: "Update a leave type", Body: &model.LeaveType{}, Response: ""})
+	types.Add("DELETE", "/:id", handler.DeleteLeaveType, openapi.Operation{Summary: "Delete a leave type", Response: ""})
+
+	requests := apiGroup(e, "/leave-requests")
+	requests.Add("POST", "/:id/approve", handler.ApproveLeave, openapi.Operation{Summary: "Approve a leave request", Body: &model.LeaveDecision{}, Response: ""})
+	requests.Add("POST", "/:id/reject", handler.RejectLeave, openapi.Operation{Summary: "Reject a leave request", Body: &model.LeaveDecision{}, Response: ""})
+	requests.Add("POST", "/:id/cancel", handler.CancelLeave, openapi.Operation{Summary: "Cancel a leave request", Response: ""})
+
+	employees := apiGroup(e, "/employees")
+	employees.Add("GET", "/:id/leave-requests", handler.GetLeaveRequests, openapi.Operation{Summary: "List leave requests", Response: []*model.LeaveRequest{}})
+	employees.Add("POST", "/:id/leave-requests", handler.RequestLeave, openapi.Operation{
+		Summary: "Request leave, stored as one request per calendar year", Body: &model.LeaveRequest{}, Response: []*model.LeaveRequest{},
+	})
+	employees.Add("GET", "/:id/leave-balances", handler.GetLeaveBalances, openapi.Operation{
+		Summary:  "List leave balances",
+		Query:    []openapi.Param{{Name: "year", Type: "integer", Description: "the current year by default"}},
+		Response: []*model.LeaveBalance{},
+	})
+}
+
+func (handler *LeaveHandler) GetLeaveTypes(c echo.Context) error {
+	response, err := handler.Service.GetLeaveTypes(requestContext(c))
+	if err != nil {
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting leave types", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *LeaveHandler) GetLeaveTypeById(c echo.Context) error {
+	response, err := handler.Service.GetLeaveTypeById(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting leave type", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *LeaveHandler) InsertLeaveType(c echo.Context) error {
+	rq := new(model.LeaveType)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.InsertLeaveType(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrLeaveTypeExists):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error inserting leave type", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *LeaveHandler) UpdateLeaveType(c echo.Context) error {
+	rq := new(model.LeaveType)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.UpdateLeaveType(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrLeaveTypeNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error updating leave type", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *LeaveHandler) DeleteLeaveType(c echo.Context) error {
+	response, err := handler.Service.DeleteLeaveType(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, model.ErrLeaveTypeNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrLeaveTypeInUse):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error deleting leave type", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *LeaveHandler) RequestLeave(c echo.Context) error {
+	rq := new(model.LeaveRequest)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	rq.IdEmployee = c.Param(`id`)
+
+	body, err := handler.Service.RequestLeave(requestContext(c), rq)
+	return leaveResponse(c, body, err, "Error requesting leave")
+}
+
+func (handler *LeaveHandler) GetLeaveRequests(c echo.Context) error {
+	response, err := handler.Service.GetLeaveRequests(requestContext(c), c.Param(`id`))
+	return leaveResponse(c, response, err, "Error getting leave requests")
+}
+
+// GetLeaveBalances returns balances for the "year" query parameter, the
+// current year when omitted.
+func (handler *LeaveHandler) GetLeaveBalances(c echo.Context) error {
+	year := 0
+	if v := c.QueryParam("year"); v != "" {
+		var errYear error
+		if year, errYear = strconv.Atoi(v); errYear != nil || year < 1 {
+			return createErrorResponse(c, 400, "BAD_REQUEST", "year must be a number", "Error: invalid year", errYear)
+		}
+	}
+	response, err := handler.Service.GetLeaveBalances(requestContext(c), c.Param(`id`), year)
+	return leaveResponse(c, response, err, "Error getting leave balances")
+}
+
+func (handler *LeaveHandler) ApproveLeave(c echo.Context) error {
+	return handler.decideLeave(c, model.LeaveApproved)
+}
+
+func (handler *LeaveHandler) RejectLeave(c echo.Context) error {
+	return handler.decideLeave(c, model.LeaveRejected)
+}
+
+func (handler *LeaveHandler) decideLeave(c echo.Context, status string) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	rq := new(model.LeaveDecision)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.DecideLeave(requestContext(c), id, rq, status)
+	return leaveResponse(c, body, err, "Error deciding leave request")
+}
+
+func (handler *LeaveHandler) CancelLeave(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	body, err := handler.Service.CancelLeave(requestContext(c), id)
+	return leaveResponse(c, body, err, "Error cancelling leave request")
+}
+
+func leaveResponse(c echo.Context, response interface{}, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrLeaveNotApprover):
+		return createErrorResponse(c, 403, "FORBIDDEN", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrLeaveOverlap), errors.Is(err, model.ErrLeaveNotPending):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrLeaveTypeNotFound), errors.Is(err, model.ErrLeaveDates),
+		errors.Is(err, model.ErrLeaveBalance), errors.Is(err, model.ErrLeaveInactive):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
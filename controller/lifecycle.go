@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"errors"
+	"github.com/labstack/echo/v4"
+)
+
+func (controller *Controller) ChangeStatus(c echo.Context) error {
+	rq := new(model.StatusChange)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	body, err := controller.Service.ChangeStatus(requestContext(c), c.Param(`id`), rq)
+	return lifecycleResponse(c, body, err, "Error changing employee status")
+}
+
+func (controller *Controller) Rehire(c echo.Context) error {
+	rq := new(model.Rehire)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	body, err := controller.Service.Rehire(requestContext(c), c.Param(`id`), rq)
+	return lifecycleResponse(c, body, err, "Error rehiring employee")
+}
+
+func (controller *Controller) GetStatusHistory(c echo.Context) error {
+	response, err := controller.Service.GetStatusHistory(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting status history", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+// lifecycleResponse answers a status change: a transition the current status
+// does not allow conflicts with it, a missing termination reason or a future
+// effective date is a bad request.
+func lifecycleResponse(c echo.Context, body string, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrStatusTransition), errors.Is(err, model.ErrNotTerminated):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrTerminationReason), errors.Is(err, model.ErrStatusFutureDate):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"employee-golang/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+// NewOpenAPISpec returns the OpenAPI document of the API routes registered
+// on e, built when first used.
+func NewOpenAPISpec(e *echo.Echo) *openapi.Spec {
+	return openapi.NewSpec(e, operations)
+}
+
+// OpenAPIController serves the document at /openapi.json.
+func OpenAPIController(e *echo.Echo, spec *openapi.Spec) {
+	e.GET("/openapi.json", func(c echo.Context) error {
+		doc, err := spec.Document()
+		if err != nil {
+			return createErrorResponse(c, 500, "INTERNAL_ERROR", "OpenAPI document unavailable", "Error building the OpenAPI document", err)
+		}
+		return c.JSON(200, doc)
+	})
+}
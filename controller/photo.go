@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"employee-golang/storage"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PhotoHandler struct {
+	Service service.IPhotoService
+}
+
+// PhotoController registers the employee photo routes.
+func PhotoController(e *echo.Echo) {
+	handler := &PhotoHandler{
+		Service: service.NewPhotoService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the employee photo routes on e.
+func (handler *PhotoHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/employees")
+	apis.Add("PUT", "/:id/photo", handler.UploadPhoto, openapi.Operation{
+		Summary:  "Upload the photo, as a multipart file or the image itself",
+		Upload:   true,
+		RawBody:  []string{"image/jpeg", "image/png", "application/octet-stream"},
+		Response: &model.Photo{},
+	})
+	apis.Add("GET", "/:id/photo", handler.GetPhoto, openapi.Operation{
+		Summary: "Get the photo or a thumbnail",
+		Query: []openapi.Param{
+			{Name: "size", Type: "integer", Description: "thumbnail size in pixels"},
+			{Name: "v", Description: "photo version; such URLs may be cached for a year"},
+		},
+		Produces: []string{"image/jpeg", "image/png"},
+		Empty:    []int{304},
+	})
+	apis.Add("GET", "/:id/photo/metadata", handler.GetPhotoMetadata, openapi.Operation{Summary: "Get the photo metadata", Response: &model.Photo{}})
+	apis.Add("DELETE", "/:id/photo", handler.DeletePhoto, openapi.Operation{Summary: "Delete the photo", Response: ""})
+}
+
+// UploadPhoto takes the image either as the raw request body or in the
+// "file" field of a multipart/form-data body.
+func (handler *PhotoHandler) UploadPhoto(c echo.Context) error {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, handler.Service.MaxSize()+multipartOverhead)
+
+	var content io.Reader = req.Body
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return createErrorResponse(c, 413, "PAYLOAD_TOO_LARGE", model.ErrPhotoTooLarge.Error(), "Error: "+err.Error(), err)
+			}
+			return createErrorResponse(c, 400, "BAD_REQUEST", "multipart field \"file\" is required", "Error: "+err.Error(), err)
+		}
+		f, err := file.Open()
+		if err != nil {
+			return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+		}
+		defer f.Close()
+		content = f
+	}
+
+	body, err := handler.Service.UploadPhoto(requestContext(c), c.Param(`id`), content)
+	return photoResponse(c, body, err, "Error uploading photo")
+}
+
+func (handler *PhotoHandler) GetPhotoMetadata(c echo.Context) error {
+	response, err := handler.Service.GetPhoto(requestContext(c), c.Param(`id`))
+	return photoResponse(c, response, err, "Error getting photo")
+}
+
+// GetPhoto serves the original, or the thumbnail given by ?size=. URLs
+// carrying the current version in ?v= never change content and may be
+// cached for a year; other requests must revalidate against the ETag.
+func (handler *PhotoHandler) GetPhoto(c echo.Context) error {
+	size := 0
+	if v := c.QueryParam("size"); v != "" {
+		var errSize error
+		if size, errSize = strconv.Atoi(v); errSize != nil || size <= 0 {
+			return createErrorResponse(c, 400, "BAD_REQUEST", model.ErrPhotoSize.Error(), "Error: invalid photo size", model.ErrPhotoSize)
+		}
+	}
+	photo, err := handler.Service.GetPhoto(requestContext(c), c.Param(`id`))
+	if err != nil {
+		return photoResponse(c, nil, err, "Error getting photo")
+	}
+
+	variant := "original"
+	if size > 0 {
+		variant = strconv.Itoa(size)
+	}
+	etag := `"` + photo.Version() + "-" + variant + `"`
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	if c.QueryParam("v") == photo.Version() {
+		header.Set("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "private, no-cache")
+	}
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	content, err := handler.Service.OpenPhoto(requestContext(c), photo, size)
+	if err != nil {
+		header.Del("ETag")
+		header.Del("Cache-Control")
+		return photoResponse(c, nil, err, "Error getting photo")
+	}
+	defer content.Close()
+	return c.Stream(200, photo.ContentType, content)
+}
+
+func (handler *PhotoHandler) DeletePhoto(c echo.Context) error {
+	response, err := handler.Service.DeletePhoto(requestContext(c), c.Param(`id`))
+	return photoResponse(c, response, err, "Error deleting photo")
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, ignoring
+// weak validator prefixes.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, v := range strings.Split(ifNoneMatch, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func photoResponse(c echo.Context, response interface{}, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, storage.ErrNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrPhotoTooLarge):
+		return createErrorResponse(c, 413, "PAYLOAD_TOO_LARGE", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrPhotoType):
+		return createErrorResponse(c, 415, "UNSUPPORTED_MEDIA_TYPE", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrPhotoDimensions):
+		return createErrorResponse(c, 422, "UNPROCESSABLE_ENTITY", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrPhotoSize):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+)
+
+type PositionHandler struct {
+	Service service.IPositionService
+}
+
+// PositionController registers the position routes.
+func PositionController(e *echo.Echo) {
+	handler := &PositionHandler{
+		Service: service.NewPositionService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the position routes on e.
+func (handler *PositionHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/positions")
+	apis.Add("GET", "", handler.GetPosition, openapi.Operation{Summary: "List positions", Response: []*model.Position{}})
+	apis.Add("GET", "/:id", handler.GetPositionById, openapi.Operation{Summary: "Get a position", Response: &model.Position{}})
+	apis.Add("POST", "", handler.InsertPosition, openapi.Operation{Summary: "Add a position", Body: &model.Position{}, Response: ""})
+	apis.Add("PUT", "", handler.UpdatePosition, openapi.Operation{Summary: "Update a position", Body: &model.Position{}, Response: ""})
+	apis.Add("DELETE", "/:id", handler.DeletePosition, openapi.Operation{Summary: "Delete a position", Response: ""})
+}
+
+func (handler *PositionHandler) GetPosition(c echo.Context) error {
+	response, err := handler.Service.GetPositions(requestContext(c))
+	if err != nil {
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting positions", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *PositionHandler) GetPositionById(c echo.Context) error {
+	response, err := handler.Service.GetPositionById(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting position", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
+
+func (handler *PositionHandler) InsertPosition(c echo.Context) error {
+	rq := new(model.Position)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.InsertPosition(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrPositionExists):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error inserting position", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *PositionHandler) UpdatePosition(c echo.Context) error {
+	rq := new(model.Position)
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+
+	body, err := handler.Service.UpdatePosition(requestContext(c), rq)
+	switch {
+	case errors.Is(err, model.ErrPositionNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error updating position", err)
+	}
+	return createSuccessResponse(c, 200, body)
+}
+
+func (handler *PositionHandler) DeletePosition(c echo.Context) error {
+	response, err := handler.Service.DeletePosition(requestContext(c), c.Param(`id`))
+	switch {
+	case errors.Is(err, model.ErrPositionNotFound):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrPositionInUse):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error deleting position", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportHandler struct {
+	Service service.IReportService
+}
+
+// ReportController registers the report routes.
+func ReportController(e *echo.Echo) {
+	handler := &ReportHandler{
+		Service: service.NewReportService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the report routes on e.
+func (handler *ReportHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/reports")
+	apis.Add("GET", "/salaries", handler.GetSalaryReport, openapi.Operation{
+		Summary:  "Report salaries converted to one currency",
+		Query:    []openapi.Param{{Name: "currency", Description: "ISO 4217 code, the base currency by default"}},
+		Response: &model.SalaryReport{},
+	})
+}
+
+func (handler *ReportHandler) GetSalaryReport(c echo.Context) error {
+	response, err := handler.Service.GetSalaryReport(requestContext(c), c.QueryParam("currency"))
+	switch {
+	case errors.Is(err, money.ErrUnknownCurrency):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error getting salary report", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
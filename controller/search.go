@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"strconv"
+)
+
+type SearchHandler struct {
+	Service service.ISearchService
+}
+
+// SearchController registers the employee search route.
+func SearchController(e *echo.Echo) {
+	handler := &SearchHandler{
+		Service: service.NewSearchService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the employee search routes on e.
+func (handler *SearchHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/employees")
+	apis.Add("GET", "/search", handler.SearchEmployees, openapi.Operation{
+		Summary:  "Search employees",
+		Query:    []openapi.Param{{Name: "q", Description: "search terms"}, {Name: "limit", Type: "integer", Description: "maximum results"}},
+		Response: []*model.SearchResult{},
+	})
+}
+
+// SearchEmployees takes the query in ?q= and an optional result limit in
+// ?limit=.
+func (handler *SearchHandler) SearchEmployees(c echo.Context) error {
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		var errLimit error
+		if limit, errLimit = strconv.Atoi(v); errLimit != nil || limit <= 0 {
+			return createErrorResponse(c, 400, "BAD_REQUEST", "limit must be a positive number", "Error: invalid limit", errLimit)
+		}
+	}
+	response, err := handler.Service.SearchEmployees(requestContext(c), c.QueryParam("q"), limit)
+	switch {
+	case errors.Is(err, model.ErrSearchQuery):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), "Error searching employees", err)
+	}
+	return createSuccessResponse(c, 200, response)
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"employee-golang/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+// apiVersions are the path prefixes every API route is served under. The
+// version only changes the response envelope, so both share the handlers.
+var apiVersions = []string{"/api/v1", "/api/v2"}
+
+// operations describes every API route for the OpenAPI document.
+var operations = openapi.NewOperations()
+
+// routeGroup registers each route under every API version.
+type routeGroup []*echo.Group
+
+func apiGroup(e *echo.Echo, prefix string) routeGroup {
+	res := make(routeGroup, 0, len(apiVersions))
+	for _, version := range apiVersions {
+		res = append(res, e.Group(version+prefix))
+	}
+	return res
+}
+
+// Add registers the route with the operation documenting it.
+func (g routeGroup) Add(method, path string, handler echo.HandlerFunc, op openapi.Operation) {
+	for _, group := range g {
+		route := group.Add(method, path, handler)
+		operations.Add(method, route.Path, op)
+	}
+}
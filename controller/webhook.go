@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/service"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"strconv"
+)
+
+type WebhookHandler struct {
+	Service service.IWebhookService
+}
+
+// WebhookController registers the webhook subscription routes.
+func WebhookController(e *echo.Echo) {
+	handler := &WebhookHandler{
+		Service: service.NewWebhookService(),
+	}
+	handler.Routes(e)
+}
+
+// Routes registers the webhook subscription routes on e.
+func (handler *WebhookHandler) Routes(e *echo.Echo) {
+	apis := apiGroup(e, "/webhooks")
+	apis.Add("GET", "", handler.GetWebhooks, openapi.Operation{Summary: "List webhook subscriptions", Response: []*model.WebhookSubscription{}})
+	apis.Add("GET", "/:id", handler.GetWebhookById, openapi.Operation{Summary: "Get a webhook subscription", Response: &model.WebhookSubscription{}})
+	apis.Add("POST", "", handler.InsertWebhook, openapi.Operation{
+		Summary: "Subscribe to events", Body: &model.WebhookSubscription{}, Response: &model.WebhookSubscription{},
+	})
+	apis.Add("PUT", "/:id", handler.UpdateWebhook, openapi.Operation{
+		Summary: "Update a webhook subscription", Body: &model.WebhookSubscription{}, Response: &model.WebhookSubscription{},
+	})
+	apis.Add("DELETE", "/:id", handler.DeleteWebhook, openapi.Operation{Summary: "Delete a webhook subscription", Response: ""})
+	apis.Add("POST", "/:id/secret", handler.RotateSecret, openapi.Operation{Summary: "Rotate the signing secret", Response: &model.WebhookSubscription{}})
+	apis.Add("GET", "/:id/deliveries", handler.GetDeliveries, openapi.Operation{
+		Summary:  "List deliveries, newest first",
+		Query:    []openapi.Param{{Name: "status", Description: "delivery status to keep"}, {Name: "limit", Type: "integer", Description: "maximum deliveries"}},
+		Response: []*model.WebhookDelivery{},
+	})
+	apis.Add("GET", "/:id/deliveries/:deliveryId", handler.GetDeliveryById, openapi.Operation{Summary: "Get a delivery", Response: &model.WebhookDelivery{}})
+	apis.Add("POST", "/:id/deliveries/:deliveryId/redeliver", handler.Redeliver, openapi.Operation{Summary: "Deliver again", Response: &model.WebhookDelivery{}})
+}
+
+func (handler *WebhookHandler) GetWebhooks(c echo.Context) error {
+	response, err := handler.Service.GetWebhooks(requestContext(c))
+	return webhookResponse(c, 200, response, err, "Error getting webhooks")
+}
+
+func (handler *WebhookHandler) GetWebhookById(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.GetWebhookById(requestContext(c), id)
+	return webhookResponse(c, 200, response, err, "Error getting webhook")
+}
+
+// InsertWebhook registers a subscription. The response carries the signing
+// secret, which is not shown again.
+func (handler *WebhookHandler) InsertWebhook(c echo.Context) error {
+	rq := &model.WebhookSubscription{Active: true}
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	response, err := handler.Service.InsertWebhook(requestContext(c), rq)
+	return webhookResponse(c, 200, response, err, "Error inserting webhook")
+}
+
+func (handler *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	rq := &model.WebhookSubscription{Active: true}
+	if ok, err := bindAndValidate(c, rq); !ok {
+		return err
+	}
+	rq.IdSubscription = id
+	response, err := handler.Service.UpdateWebhook(requestContext(c), rq)
+	return webhookResponse(c, 200, response, err, "Error updating webhook")
+}
+
+func (handler *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.DeleteWebhook(requestContext(c), id)
+	return webhookResponse(c, 200, response, err, "Error deleting webhook")
+}
+
+// RotateSecret replaces the signing secret with a generated one, returned
+// in the response.
+func (handler *WebhookHandler) RotateSecret(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.RotateSecret(requestContext(c), id)
+	return webhookResponse(c, 200, response, err, "Error rotating webhook secret")
+}
+
+// GetDeliveries is the delivery log of a subscription, newest first,
+// filtered by the status query parameter and capped by limit.
+func (handler *WebhookHandler) GetDeliveries(c echo.Context) error {
+	id, errId := strconv.ParseInt(c.Param(`id`), 10, 64)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		var errLimit error
+		if limit, errLimit = strconv.Atoi(v); errLimit != nil {
+			return createErrorResponse(c, 400, "BAD_REQUEST", "limit must be a number", "Error: invalid limit", errLimit)
+		}
+	}
+	response, err := handler.Service.GetDeliveries(requestContext(c), id, c.QueryParam("status"), limit)
+	return webhookResponse(c, 200, response, err, "Error getting webhook deliveries")
+}
+
+// GetDeliveryById returns a delivery with its payload and every attempt.
+func (handler *WebhookHandler) GetDeliveryById(c echo.Context) error {
+	id, deliveryId, errId := deliveryIds(c)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.GetDeliveryById(requestContext(c), id, deliveryId)
+	return webhookResponse(c, 200, response, err, "Error getting webhook delivery")
+}
+
+// Redeliver schedules a delivery to be sent again right away, including
+// one that was dead-lettered.
+func (handler *WebhookHandler) Redeliver(c echo.Context) error {
+	id, deliveryId, errId := deliveryIds(c)
+	if errId != nil {
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", errId)
+	}
+	response, err := handler.Service.Redeliver(requestContext(c), id, deliveryId)
+	return webhookResponse(c, 200, response, err, "Error redelivering webhook delivery")
+}
+
+func deliveryIds(c echo.Context) (id, deliveryId int64, err error) {
+	if id, err = strconv.ParseInt(c.Param(`id`), 10, 64); err != nil {
+		return 0, 0, err
+	}
+	deliveryId, err = strconv.ParseInt(c.Param(`deliveryId`), 10, 64)
+	return id, deliveryId, err
+}
+
+func webhookResponse(c echo.Context, code int, response interface{}, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return createErrorResponse(c, 404, "NOT_FOUND", "Data Not Found", "Data not found", err)
+	case errors.Is(err, model.ErrWebhookUrl), errors.Is(err, model.ErrWebhookEventType):
+		return createErrorResponse(c, 422, "UNPROCESSABLE_ENTITY", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrDeliveryStatus):
+		return createErrorResponse(c, 400, "BAD_REQUEST", err.Error(), "Error: "+err.Error(), err)
+	case errors.Is(err, model.ErrWebhookInactive):
+		return createErrorResponse(c, 409, "CONFLICTED", err.Error(), "Error: "+err.Error(), err)
+	case err != nil:
+		return createErrorResponse(c, 500, "INTERNAL_ERROR", err.Error(), message, err)
+	}
+	return createSuccessResponse(c, code, response)
+}
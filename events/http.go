@@ -0,0 +1,124 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"employee-golang/model"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookSink POSTs each event as JSON to a URL. Any 2xx response accepts
+// the event.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{URL: url, Client: &http.Client{Timeout: timeout}}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event *model.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.IdEvent)
+	req.Header.Set("X-Event-Type", event.Type)
+	return send(s.Client, req, nil)
+}
+
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// KafkaSink produces events to a Kafka topic through the Confluent REST
+// proxy v2 API, keyed by aggregate id so the changes of one employee stay
+// in one partition and in order.
+type KafkaSink struct {
+	URL    string
+	Topic  string
+	Client *http.Client
+}
+
+func NewKafkaSink(restURL, topic string, timeout time.Duration) *KafkaSink {
+	return &KafkaSink{URL: restURL, Topic: topic, Client: &http.Client{Timeout: timeout}}
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string       `json:"key"`
+	Value *model.Event `json:"value"`
+}
+
+// kafkaOffsets is the produce response; a record the broker rejected has
+// an error instead of an offset.
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, event *model.Event) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: event.AggregateId, Value: event}}})
+	if err != nil {
+		return err
+	}
+	endpoint := s.URL + "/topics/" + url.PathEscape(s.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	var res kafkaOffsets
+	if err = send(s.Client, req, &res); err != nil {
+		return err
+	}
+	for _, o := range res.Offsets {
+		if o.Error != nil {
+			return fmt.Errorf("kafka: producing to %s: %s", s.Topic, *o.Error)
+		}
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	return nil
+}
+
+// send performs req, failing on a non-2xx status, and decodes the response
+// body into out when it is not nil.
+func send(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Redacted(), resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"employee-golang/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSSink publishes each event to <Subject>.<event type> on a NATS
+// server. It speaks just enough of the client protocol to connect and PUB
+// over a single connection that is re-dialled after any I/O error. Core
+// NATS does not acknowledge messages, so every PUB is followed by a PING
+// and the event counts as accepted once the server answers PONG.
+type NATSSink struct {
+	URL     string
+	Subject string
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+func NewNATSSink(url, subject string) *NATSSink {
+	return &NATSSink{
+		URL:     url,
+		Subject: subject,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func (s *NATSSink) Publish(ctx context.Context, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	subject := s.Subject + "." + event.Type
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = s.publish(ctx, subject, payload); err != nil {
+		s.closeConn()
+	}
+	return err
+}
+
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeConn()
+}
+
+func (s *NATSSink) publish(ctx context.Context, subject string, payload []byte) error {
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return err
+		}
+	}
+	s.setDeadline(ctx)
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+// dial connects and sends CONNECT, taking credentials from the URL.
+func (s *NATSSink) dial(ctx context.Context) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return err
+	}
+	d := net.Dialer{Timeout: s.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return err
+	}
+	s.conn, s.rd = conn, bufio.NewReader(conn)
+	s.setDeadline(ctx)
+
+	line, err := s.rd.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("nats: unexpected greeting %q", strings.TrimSpace(line))
+	}
+	options := map[string]interface{}{"verbose": false, "pedantic": false, "name": "employee-golang", "lang": "go"}
+	if u.User != nil {
+		options["user"] = u.User.Username()
+		options["pass"], _ = u.User.Password()
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(s.conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+// awaitPong reads until the server's PONG, answering its PINGs and
+// skipping INFO updates.
+func (s *NATSSink) awaitPong() error {
+	for {
+		line, err := s.rd.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err = s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("nats: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+		default:
+			return fmt.Errorf("nats: unexpected reply %q", line)
+		}
+	}
+}
+
+func (s *NATSSink) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(s.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = s.conn.SetDeadline(deadline)
+}
+
+func (s *NATSSink) closeConn() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.rd = nil, nil
+	return err
+}
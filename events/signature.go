@@ -0,0 +1,49 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	HeaderWebhookId        = "X-Webhook-Id"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+var ErrSignature = errors.New("webhook signature does not match")
+
+// SignPayload returns the X-Webhook-Signature value for body sent at
+// timestamp (unix seconds): "sha256=" and the hex HMAC-SHA256, keyed with
+// the subscription secret, of the timestamp, a dot and the body. Signing
+// the timestamp lets receivers reject replayed deliveries.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the timestamp and signature headers of a
+// delivery against its body, refusing timestamps further than tolerance
+// from now.
+func VerifySignature(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrSignature
+	}
+	if !hmac.Equal([]byte(SignPayload(secret, ts, body)), []byte(strings.TrimSpace(signature))) {
+		return ErrSignature
+	}
+	return nil
+}
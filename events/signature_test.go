@@ -0,0 +1,47 @@
+package events
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"EmployeeCreated"}`)
+	signature := SignPayload("whsec_test", now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		now       time.Time
+		wantErr   bool
+	}{
+		{name: "valid", secret: "whsec_test", timestamp: timestamp, body: body, now: now.Add(time.Minute)},
+		{name: "wrong secret", secret: "whsec_other", timestamp: timestamp, body: body, now: now, wantErr: true},
+		{name: "tampered body", secret: "whsec_test", timestamp: timestamp, body: []byte(`{"type":"EmployeeDeleted"}`), now: now, wantErr: true},
+		{name: "replayed later", secret: "whsec_test", timestamp: timestamp, body: body, now: now.Add(10 * time.Minute), wantErr: true},
+		{name: "other timestamp", secret: "whsec_test", timestamp: strconv.FormatInt(now.Unix()+1, 10), body: body, now: now, wantErr: true},
+		{name: "bad timestamp", secret: "whsec_test", timestamp: "yesterday", body: body, now: now, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.timestamp, signature, tt.body, 5*time.Minute, tt.now)
+			if tt.wantErr != errors.Is(err, ErrSignature) || !tt.wantErr && err != nil {
+				t.Errorf("VerifySignature() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignPayload(t *testing.T) {
+	// HMAC-SHA256 keyed "key" of "1.body"
+	want := "sha256=91b5374b153842ad05b2c4eab9349b8321b14703165bd3fb8b034dfb8be98ae5"
+	if got := SignPayload("key", 1, []byte("body")); got != want {
+		t.Errorf("SignPayload() = %q, want %q", got, want)
+	}
+}
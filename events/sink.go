@@ -0,0 +1,105 @@
+// Package events publishes domain events to the systems that follow
+// employee changes.
+package events
+
+import (
+	"context"
+	"employee-golang/model"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink receives events in outbox order. Publish returns only once the sink
+// has accepted the event; an error makes the relay retry it, so sinks see
+// at-least-once delivery and may get an event again.
+type Sink interface {
+	Publish(ctx context.Context, event *model.Event) error
+	Close() error
+}
+
+// WriterSink writes each event as a line of JSON, to stdout by default.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutSink() *WriterSink {
+	return &WriterSink{w: os.Stdout}
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Publish(_ context.Context, event *model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// FileSink appends events as JSON lines to a file, syncing each one to
+// disk before reporting it published.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Publish(_ context.Context, event *model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err = s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// FanOut publishes every event to all of its sinks. A failure in one sink
+// retries the event on all of them, so the others may see it twice.
+type FanOut []Sink
+
+func (f FanOut) Publish(ctx context.Context, event *model.Event) error {
+	for _, s := range f {
+		if err := s.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes every sink and returns the first error.
+func (f FanOut) Close() error {
+	var first error
+	for _, s := range f {
+		if err := s.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
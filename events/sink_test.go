@@ -0,0 +1,173 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"employee-golang/model"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() *model.Event {
+	return &model.Event{Sequence: 3, IdEvent: "e-3", Type: model.EventEmployeeUpdated, AggregateId: "7",
+		OccurredAt: "2024-05-06T07:00:00Z", Payload: json.RawMessage(`{"before":null,"after":{"idEmployee":"7"}}`)}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sink.Publish(context.Background(), testEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sink.Close()
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var got model.Event
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &got) != nil || got.IdEvent != "e-3" {
+		t.Errorf("file content = %q", data)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	status := http.StatusNoContent
+	var got model.Event
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, time.Second)
+	if err := sink.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if got.Sequence != 3 || header.Get("X-Event-Id") != "e-3" || header.Get("X-Event-Type") != model.EventEmployeeUpdated {
+		t.Errorf("webhook received %+v with %v", got, header)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Publish(context.Background(), testEvent()); err == nil {
+		t.Errorf("Publish() should fail on a %d response", status)
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	reject := false
+	var records kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/employee-events" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&records)
+		if reject {
+			io.WriteString(w, `{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"broker unavailable"}]}`)
+			return
+		}
+		io.WriteString(w, `{"offsets":[{"partition":0,"offset":41,"error_code":null,"error":null}]}`)
+	}))
+	defer server.Close()
+
+	sink := NewKafkaSink(server.URL, "employee-events", time.Second)
+	if err := sink.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Records) != 1 || records.Records[0].Key != "7" || records.Records[0].Value.IdEvent != "e-3" {
+		t.Errorf("kafka received %+v", records)
+	}
+
+	reject = true
+	if err := sink.Publish(context.Background(), testEvent()); err == nil || !strings.Contains(err.Error(), "broker unavailable") {
+		t.Errorf("Publish() error = %v", err)
+	}
+}
+
+// fakeNATS accepts connections and records the messages published to it,
+// answering PING with PONG like a NATS server.
+func fakeNATS(t *testing.T) (url string, messages chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	messages = make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				rd := bufio.NewReader(conn)
+				io.WriteString(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+				for {
+					line, err := rd.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fields := strings.Fields(line)
+					switch {
+					case len(fields) == 0:
+					case fields[0] == "PING":
+						io.WriteString(conn, "PONG\r\n")
+					case fields[0] == "PUB" && len(fields) == 3:
+						size, _ := strconv.Atoi(fields[2])
+						payload := make([]byte, size+2)
+						if _, err := io.ReadFull(rd, payload); err != nil {
+							return
+						}
+						if fields[1] == "employees.Forbidden" {
+							io.WriteString(conn, "-ERR 'Permissions Violation'\r\n")
+							continue
+						}
+						messages <- fields[1] + " " + string(payload[:size])
+					}
+				}
+			}()
+		}
+	}()
+	return "nats://user:secret@" + l.Addr().String(), messages
+}
+
+func TestNATSSink(t *testing.T) {
+	url, messages := fakeNATS(t)
+	sink := NewNATSSink(url, "employees")
+	defer sink.Close()
+
+	if err := sink.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-messages
+	if !strings.HasPrefix(msg, "employees.EmployeeUpdated {") || !strings.Contains(msg, `"idEvent":"e-3"`) {
+		t.Errorf("nats received %q", msg)
+	}
+
+	forbidden := testEvent()
+	forbidden.Type = "Forbidden"
+	if err := sink.Publish(context.Background(), forbidden); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("Publish() error = %v", err)
+	}
+	// the connection is re-dialled after an error
+	if err := sink.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	<-messages
+}
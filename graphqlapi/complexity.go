@@ -0,0 +1,123 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/graphql-go/graphql/language/ast"
+	"strconv"
+	"strings"
+)
+
+// listEstimate is the size assumed for lists that are not paginated.
+const listEstimate = 10
+
+// checkLimits rejects an operation deeper than MaxDepth or costing more
+// than MaxComplexity. Every field costs one, and the selections under a
+// list cost once per item: first items for a page of employees,
+// listEstimate items for the other lists. Introspection is free.
+func (s *Server) checkLimits(doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) error {
+	w := &costWalker{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: make(map[string]interface{}),
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			w.variables[def.Variable.Name.Value] = def.DefaultValue.GetValue()
+		}
+	}
+	for k, v := range variables {
+		w.variables[k] = v
+	}
+
+	cost := w.selectionCost(op.SelectionSet, 1, defaultPageSize)
+	if w.depth > s.MaxDepth {
+		return badRequest(fmt.Sprintf("query depth %d exceeds the limit of %d", w.depth, s.MaxDepth))
+	}
+	if cost > s.MaxComplexity {
+		return badRequest(fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, s.MaxComplexity))
+	}
+	return nil
+}
+
+type costWalker struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	depth     int
+}
+
+// selectionCost is the cost of set at depth, where page is the size of the
+// nearest enclosing employee page.
+func (w *costWalker) selectionCost(set *ast.SelectionSet, depth, page int) int {
+	if set == nil {
+		return 0
+	}
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			if depth > w.depth {
+				w.depth = depth
+			}
+			items, childPage := 1, page
+			switch name {
+			case "nodes", "edges":
+				items = page
+			case "directReports", "departments", "positions":
+				items = listEstimate
+			case "employees":
+				childPage = w.first(selection)
+			}
+			cost += 1 + items*w.selectionCost(selection.SelectionSet, depth+1, childPage)
+		case *ast.InlineFragment:
+			cost += w.selectionCost(selection.SelectionSet, depth, page)
+		case *ast.FragmentSpread:
+			// Validation has already rejected unknown and cyclic fragments.
+			if fragment, ok := w.fragments[selection.Name.Value]; ok {
+				cost += w.selectionCost(fragment.SelectionSet, depth, page)
+			}
+		}
+	}
+	return cost
+}
+
+// first is the page size an employees field asks for, capped at the
+// largest page the resolver serves.
+func (w *costWalker) first(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		var v interface{}
+		if variable, ok := arg.Value.(*ast.Variable); ok {
+			v = w.variables[variable.Name.Value]
+		} else {
+			v = arg.Value.GetValue()
+		}
+		n := defaultPageSize
+		switch v := v.(type) {
+		case int:
+			n = v
+		case float64:
+			n = int(v)
+		case string:
+			n, _ = strconv.Atoi(v)
+		case json.Number:
+			i, _ := v.Int64()
+			n = int(i)
+		}
+		if n < 1 || n > maxPageSize {
+			return maxPageSize
+		}
+		return n
+	}
+	return defaultPageSize
+}
@@ -0,0 +1,66 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader batches the lookups made while a query level resolves. Load only
+// queues the key and returns a thunk; the executor runs the thunks of a
+// level after resolving all of its fields, and the first one fetches every
+// queued key in one call. Results are kept for the rest of the request.
+type Loader[V any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]V, error)
+
+	mu      sync.Mutex
+	pending []string
+	results map[string]*loaded[V]
+}
+
+type loaded[V any] struct {
+	value V
+	found bool
+	err   error
+	done  bool
+}
+
+func NewLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *Loader[V] {
+	return &Loader[V]{fetch: fetch, results: make(map[string]*loaded[V])}
+}
+
+// Load returns a thunk resolving to the value of key, or nil when fetch
+// did not return it.
+func (l *Loader[V]) Load(ctx context.Context, key string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = &loaded[V]{}
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		r := l.dispatch(ctx, key)
+		if r.err != nil || !r.found {
+			return nil, r.err
+		}
+		return r.value, nil
+	}
+}
+
+// dispatch fetches the queued keys unless key has already been fetched.
+func (l *Loader[V]) dispatch(ctx context.Context, key string) *loaded[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r := l.results[key]; r.done {
+		return r
+	}
+	keys := l.pending
+	l.pending = nil
+	values, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		r := l.results[k]
+		r.value, r.found = values[k]
+		r.err, r.done = err, true
+	}
+	return l.results[key]
+}
@@ -0,0 +1,468 @@
+package graphqlapi
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/service"
+	"encoding/base64"
+	"errors"
+	"github.com/graphql-go/graphql"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	cursorPrefix    = "employee:"
+)
+
+type employeeConnection struct {
+	TotalCount int               `json:"totalCount"`
+	Nodes      []*model.Employee `json:"nodes"`
+	Edges      []employeeEdge    `json:"edges"`
+	PageInfo   pageInfo          `json:"pageInfo"`
+}
+
+type employeeEdge struct {
+	Cursor string          `json:"cursor"`
+	Node   *model.Employee `json:"node"`
+}
+
+type pageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+// codedError carries the REST API's status names as the "code" extension
+// so clients can tell failures apart without parsing messages.
+type codedError struct {
+	code    string
+	message string
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+func (e *codedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func badRequest(message string) error {
+	return &codedError{code: "BAD_REQUEST", message: message}
+}
+
+// toError maps service errors the way the REST handlers map them to
+// status codes.
+func toError(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return &codedError{code: "NOT_FOUND", message: "Data Not Found"}
+	case err.Error() == "employee already exists":
+		return &codedError{code: "CONFLICTED", message: err.Error()}
+	case errors.Is(err, model.ErrStatusTransition), errors.Is(err, model.ErrNotTerminated):
+		return &codedError{code: "CONFLICTED", message: err.Error()}
+	case isInvalidEmployeeError(err):
+		return badRequest(err.Error())
+	}
+	return &codedError{code: "INTERNAL_ERROR", message: err.Error()}
+}
+
+func isInvalidEmployeeError(err error) bool {
+	for _, target := range []error{
+		model.ErrDepartmentNotFound,
+		model.ErrManagerNotFound,
+		model.ErrManagerCycle,
+		model.ErrHierarchyTooDeep,
+		model.ErrPositionNotFound,
+		model.ErrSalaryOutOfBand,
+		money.ErrUnknownCurrency,
+		model.ErrStatusNotEditable,
+		model.ErrUnknownStatus,
+		model.ErrTerminationReason,
+		model.ErrStatusFutureDate,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) buildSchema() (graphql.Schema, error) {
+	department := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Department",
+		Fields: graphql.Fields{
+			"idDepartment": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description":  &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	position := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Position",
+		Fields: graphql.Fields{
+			"idPosition": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"title":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"grade":      &graphql.Field{Type: graphql.String},
+			"minSalary": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Position).MinSalary.String(), nil
+			}},
+			"maxSalary": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Position).MaxSalary.String(), nil
+			}},
+			"currency": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	var employee *graphql.Object
+	employee = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Employee",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"idEmployee": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"firstName":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"lastName":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"email":      &graphql.Field{Type: graphql.String},
+				"phone":      &graphql.Field{Type: graphql.String},
+				"hireDate":   &graphql.Field{Type: graphql.String},
+				"salary": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*model.Employee).Salary.String(), nil
+				}},
+				"currency":          &graphql.Field{Type: graphql.String},
+				"status":            &graphql.Field{Type: graphql.String},
+				"terminationDate":   &graphql.Field{Type: graphql.String},
+				"terminationReason": &graphql.Field{Type: graphql.String},
+				"photoUrl":          &graphql.Field{Type: graphql.String},
+				"departmentId":      &graphql.Field{Type: graphql.ID},
+				"managerId":         &graphql.Field{Type: graphql.ID},
+				"positionId":        &graphql.Field{Type: graphql.ID},
+				"department": &graphql.Field{Type: department, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id := p.Source.(*model.Employee).DepartmentId
+					if id == "" {
+						return nil, nil
+					}
+					return loadersFrom(p.Context).departments.Load(p.Context, id), nil
+				}},
+				"position": &graphql.Field{Type: position, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id := p.Source.(*model.Employee).PositionId
+					if id == "" {
+						return nil, nil
+					}
+					return loadersFrom(p.Context).positions.Load(p.Context, id), nil
+				}},
+				"manager": &graphql.Field{Type: employee, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id := p.Source.(*model.Employee).ManagerId
+					if id == "" {
+						return nil, nil
+					}
+					return loadersFrom(p.Context).employees.Load(p.Context, id), nil
+				}},
+				"directReports": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(employee))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadersFrom(p.Context).reports.Load(p.Context, p.Source.(*model.Employee).IdEmployee), nil
+					},
+				},
+			}
+		}),
+	})
+
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EmployeeEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(employee)},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	connection := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EmployeeConnection",
+		Fields: graphql.Fields{
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"nodes":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(employee)))},
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edge)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	filter := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "EmployeeFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"status":       &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"departmentId": &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"managerId":    &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"positionId":   &graphql.InputObjectFieldConfig{Type: graphql.ID},
+		},
+	})
+
+	input := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "EmployeeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"idEmployee":           &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"firstName":            &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"lastName":             &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"email":                &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"phone":                &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"hireDate":             &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"salary":               &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"currency":             &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"departmentId":         &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"managerId":            &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"positionId":           &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"status":               &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"salaryOverrideReason": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"employee": &graphql.Field{
+				Type: employee,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: s.resolveEmployee,
+			},
+			"employees": &graphql.Field{
+				Type: graphql.NewNonNull(connection),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: filter},
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: s.resolveEmployees,
+			},
+			"departments": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(department))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rs, err := s.Departments.GetDepartments(p.Context)
+					if err != nil {
+						return nil, toError(err)
+					}
+					return rs, nil
+				},
+			},
+			"positions": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(position))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rs, err := s.Positions.GetPositions(p.Context)
+					if err != nil {
+						return nil, toError(err)
+					}
+					return rs, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createEmployee": &graphql.Field{
+				Type: graphql.NewNonNull(employee),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(input)},
+				},
+				Resolve: s.resolveCreateEmployee,
+			},
+			"updateEmployee": &graphql.Field{
+				Type: graphql.NewNonNull(employee),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(input)},
+				},
+				Resolve: s.resolveUpdateEmployee,
+			},
+			"deleteEmployee": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: s.resolveDeleteEmployee,
+			},
+			"changeEmployeeStatus": &graphql.Field{
+				Type: graphql.NewNonNull(employee),
+				Args: graphql.FieldConfigArgument{
+					"id":            &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"status":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"effectiveDate": &graphql.ArgumentConfig{Type: graphql.String},
+					"reason":        &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: s.resolveChangeStatus,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// resolveEmployee answers null rather than an error for an unknown id.
+func (s *Server) resolveEmployee(p graphql.ResolveParams) (interface{}, error) {
+	rs, err := s.Employees.GetEmployeeById(p.Context, p.Args["id"].(string))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(err)
+	}
+	return rs, nil
+}
+
+// resolveEmployees pages through the filtered employees ordered by id. The
+// cursor is the last id seen, so pages stay stable when rows are deleted.
+func (s *Server) resolveEmployees(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxPageSize {
+		return nil, badRequest("first must be between 1 and 100")
+	}
+	after := ""
+	if v, ok := p.Args["after"].(string); ok && v != "" {
+		raw, err := base64.StdEncoding.DecodeString(v)
+		if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+			return nil, badRequest("after is not a valid cursor")
+		}
+		after = strings.TrimPrefix(string(raw), cursorPrefix)
+	}
+
+	filter, _ := p.Args["filter"].(map[string]interface{})
+	var statuses []string
+	if list, ok := filter["status"].([]interface{}); ok {
+		for _, v := range list {
+			statuses = append(statuses, v.(string))
+		}
+	}
+	rs, err := s.Employees.GetEmployeesByStatus(p.Context, strings.Join(statuses, ","))
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var matched []*model.Employee
+	for _, e := range rs {
+		if matches(filter, "departmentId", e.DepartmentId) &&
+			matches(filter, "managerId", e.ManagerId) &&
+			matches(filter, "positionId", e.PositionId) {
+			matched = append(matched, e)
+		}
+	}
+	page, more := service.PageEmployees(matched, after, first)
+	res := &employeeConnection{
+		TotalCount: len(matched),
+		Nodes:      []*model.Employee{},
+		Edges:      []employeeEdge{},
+		PageInfo:   pageInfo{HasNextPage: more},
+	}
+	for _, e := range page {
+		cursor := base64.StdEncoding.EncodeToString([]byte(cursorPrefix + e.IdEmployee))
+		res.Nodes = append(res.Nodes, e)
+		res.Edges = append(res.Edges, employeeEdge{Cursor: cursor, Node: e})
+		res.PageInfo.EndCursor = &cursor
+	}
+	return res, nil
+}
+
+func matches(filter map[string]interface{}, key, value string) bool {
+	want, ok := filter[key].(string)
+	return !ok || want == value
+}
+
+func (s *Server) resolveCreateEmployee(p graphql.ResolveParams) (interface{}, error) {
+	employee, err := s.employeeFromInput(p.Args["input"].(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.Employees.InsertEmployee(p.Context, employee); err != nil {
+		return nil, toError(err)
+	}
+	return s.getEmployee(p.Context, employee.IdEmployee)
+}
+
+func (s *Server) resolveUpdateEmployee(p graphql.ResolveParams) (interface{}, error) {
+	employee, err := s.employeeFromInput(p.Args["input"].(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.Employees.UpdateEmployee(p.Context, employee); err != nil {
+		return nil, toError(err)
+	}
+	return s.getEmployee(p.Context, employee.IdEmployee)
+}
+
+func (s *Server) resolveDeleteEmployee(p graphql.ResolveParams) (interface{}, error) {
+	rs, err := s.Employees.DeleteEmployee(p.Context, p.Args["id"].(string))
+	if err != nil {
+		return nil, toError(err)
+	}
+	return rs, nil
+}
+
+func (s *Server) resolveChangeStatus(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(string)
+	change := &model.StatusChange{
+		Status:        p.Args["status"].(string),
+		EffectiveDate: stringArg(p.Args, "effectiveDate"),
+		Reason:        stringArg(p.Args, "reason"),
+	}
+	if err := s.validator.Struct(change); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	if _, err := s.Employees.ChangeStatus(p.Context, id, change); err != nil {
+		return nil, toError(err)
+	}
+	return s.getEmployee(p.Context, id)
+}
+
+func (s *Server) getEmployee(ctx context.Context, id string) (interface{}, error) {
+	rs, err := s.Employees.GetEmployeeById(ctx, id)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return rs, nil
+}
+
+// employeeFromInput converts and validates an EmployeeInput the way the
+// REST handlers bind and validate the request body.
+func (s *Server) employeeFromInput(in map[string]interface{}) (*model.Employee, error) {
+	salary := decimal.Zero
+	if v := stringArg(in, "salary"); v != "" {
+		var err error
+		if salary, err = decimal.NewFromString(v); err != nil {
+			return nil, badRequest("salary must be a decimal number")
+		}
+	}
+	employee := &model.Employee{
+		IdEmployee:           stringArg(in, "idEmployee"),
+		FirstName:            stringArg(in, "firstName"),
+		LastName:             stringArg(in, "lastName"),
+		Email:                stringArg(in, "email"),
+		Phone:                stringArg(in, "phone"),
+		HireDate:             stringArg(in, "hireDate"),
+		Salary:               model.NewMoney(salary),
+		Currency:             stringArg(in, "currency"),
+		DepartmentId:         stringArg(in, "departmentId"),
+		ManagerId:            stringArg(in, "managerId"),
+		PositionId:           stringArg(in, "positionId"),
+		Status:               stringArg(in, "status"),
+		SalaryOverrideReason: stringArg(in, "salaryOverrideReason"),
+	}
+	if err := s.validator.Struct(employee); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	return employee, nil
+}
+
+func stringArg(args map[string]interface{}, key string) string {
+	v, _ := args[key].(string)
+	return v
+}
@@ -0,0 +1,166 @@
+package graphqlapi
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/service"
+	"employee-golang/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	// QueryOnly rejects mutations, for requests that arrive over GET.
+	QueryOnly bool `json:"-"`
+}
+
+// Server executes GraphQL requests against the employee services.
+type Server struct {
+	Employees     service.IEmployeeService
+	Departments   service.IDepartmentService
+	Positions     service.IPositionService
+	MaxComplexity int
+	MaxDepth      int
+	schema        graphql.Schema
+	validator     *validator.Validate
+}
+
+func NewServer(employees service.IEmployeeService, departments service.IDepartmentService, positions service.IPositionService) (*Server, error) {
+	s := &Server{
+		Employees:     employees,
+		Departments:   departments,
+		Positions:     positions,
+		MaxComplexity: config.GetGraphQLMaxComplexity(),
+		MaxDepth:      config.GetGraphQLMaxDepth(),
+		validator:     util.NewValidator(),
+	}
+	schema, err := s.buildSchema()
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+	return s, nil
+}
+
+// Execute runs rq. It reports rejected when the document never reached
+// execution because it does not parse, fails validation or exceeds the
+// complexity limits; such requests are answered with 400.
+func (s *Server) Execute(ctx context.Context, rq Request) (res *graphql.Result, rejected bool) {
+	doc, err := parser.Parse(parser.ParseParams{Source: rq.Query})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, true
+	}
+	if vr := graphql.ValidateDocument(&s.schema, doc, nil); !vr.IsValid {
+		return &graphql.Result{Errors: vr.Errors}, true
+	}
+	if op := selectOperation(doc, rq.OperationName); op != nil {
+		if rq.QueryOnly && op.Operation != ast.OperationTypeQuery {
+			return &graphql.Result{Errors: gqlerrors.FormatErrors(badRequest("only queries may be sent with GET"))}, true
+		}
+		if err := s.checkLimits(doc, op, rq.Variables); err != nil {
+			return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, true
+		}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: rq.OperationName,
+		Args:          rq.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, s.newLoaders()),
+	}), false
+}
+
+// selectOperation returns the operation Execute would run, or nil when the
+// name does not pick exactly one; Execute then reports the error itself.
+func selectOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+type loadersKey struct{}
+
+// loaders batch the lookups of related entities for one request.
+type loaders struct {
+	employees   *Loader[*model.Employee]
+	reports     *Loader[[]*model.Employee]
+	departments *Loader[*model.Department]
+	positions   *Loader[*model.Position]
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func (s *Server) newLoaders() *loaders {
+	return &loaders{
+		employees: NewLoader(func(ctx context.Context, ids []string) (map[string]*model.Employee, error) {
+			rs, err := s.Employees.GetEmployeesByIds(ctx, ids)
+			if err != nil {
+				return nil, toError(err)
+			}
+			res := make(map[string]*model.Employee, len(rs))
+			for _, e := range rs {
+				res[e.IdEmployee] = e
+			}
+			return res, nil
+		}),
+		reports: NewLoader(func(ctx context.Context, ids []string) (map[string][]*model.Employee, error) {
+			rs, err := s.Employees.GetEmployeesByManagers(ctx, ids)
+			if err != nil {
+				return nil, toError(err)
+			}
+			res := make(map[string][]*model.Employee, len(ids))
+			for _, id := range ids {
+				res[id] = []*model.Employee{}
+			}
+			for _, e := range rs {
+				res[e.ManagerId] = append(res[e.ManagerId], e)
+			}
+			return res, nil
+		}),
+		departments: NewLoader(func(ctx context.Context, ids []string) (map[string]*model.Department, error) {
+			rs, err := s.Departments.GetDepartmentsByIds(ctx, ids)
+			if err != nil {
+				return nil, toError(err)
+			}
+			res := make(map[string]*model.Department, len(rs))
+			for _, d := range rs {
+				res[d.IdDepartment] = d
+			}
+			return res, nil
+		}),
+		positions: NewLoader(func(ctx context.Context, ids []string) (map[string]*model.Position, error) {
+			rs, err := s.Positions.GetPositionsByIds(ctx, ids)
+			if err != nil {
+				return nil, toError(err)
+			}
+			res := make(map[string]*model.Position, len(rs))
+			for _, p := range rs {
+				res[p.IdPosition] = p
+			}
+			return res, nil
+		}),
+	}
+}
@@ -0,0 +1,230 @@
+package graphqlapi
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/service"
+	"employee-golang/util"
+	"encoding/json"
+	"errors"
+	"github.com/shopspring/decimal"
+	"strings"
+	"testing"
+)
+
+type fakeEmployeeService struct {
+	service.IEmployeeService
+	employees    map[string]*model.Employee
+	byIdCalls    [][]string
+	byManagerIds [][]string
+}
+
+func (s *fakeEmployeeService) GetEmployeesByStatus(_ context.Context, filter string) ([]*model.Employee, error) {
+	var rs []*model.Employee
+	for _, e := range s.employees {
+		if filter == "" || strings.Contains(filter, e.Status) {
+			rs = append(rs, e)
+		}
+	}
+	return rs, nil
+}
+
+func (s *fakeEmployeeService) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	if e, ok := s.employees[id]; ok {
+		return e, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *fakeEmployeeService) GetEmployeesByIds(_ context.Context, ids []string) ([]*model.Employee, error) {
+	s.byIdCalls = append(s.byIdCalls, ids)
+	var rs []*model.Employee
+	for _, id := range ids {
+		if e, ok := s.employees[id]; ok {
+			rs = append(rs, e)
+		}
+	}
+	return rs, nil
+}
+
+func (s *fakeEmployeeService) GetEmployeesByManagers(_ context.Context, ids []string) ([]*model.Employee, error) {
+	s.byManagerIds = append(s.byManagerIds, ids)
+	var rs []*model.Employee
+	for _, e := range s.employees {
+		for _, id := range ids {
+			if e.ManagerId == id {
+				rs = append(rs, e)
+			}
+		}
+	}
+	return rs, nil
+}
+
+func (s *fakeEmployeeService) InsertEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	if _, ok := s.employees[employee.IdEmployee]; ok {
+		return "Employee already exists", errors.New("employee already exists")
+	}
+	employee.Status = "onboarding"
+	s.employees[employee.IdEmployee] = employee
+	return "Employee was added", nil
+}
+
+type fakeDepartmentService struct {
+	service.IDepartmentService
+	calls [][]string
+}
+
+func (s *fakeDepartmentService) GetDepartmentsByIds(_ context.Context, ids []string) ([]*model.Department, error) {
+	s.calls = append(s.calls, ids)
+	var rs []*model.Department
+	for _, id := range ids {
+		rs = append(rs, &model.Department{IdDepartment: id, Name: "Department " + id})
+	}
+	return rs, nil
+}
+
+type fakePositionService struct {
+	service.IPositionService
+}
+
+func newTestServer() (*Server, *fakeEmployeeService, *fakeDepartmentService) {
+	employees := &fakeEmployeeService{employees: map[string]*model.Employee{
+		"1": {IdEmployee: "1", FirstName: "Jane", DepartmentId: "D1", Salary: model.NewMoney(decimal.RequireFromString("1200.50")), Status: "active"},
+		"2": {IdEmployee: "2", FirstName: "John", DepartmentId: "D1", ManagerId: "1", Status: "active"},
+		"3": {IdEmployee: "3", FirstName: "Mary", DepartmentId: "D2", ManagerId: "1", Status: "on_leave"},
+	}}
+	departments := &fakeDepartmentService{}
+	s := &Server{
+		Employees:     employees,
+		Departments:   departments,
+		Positions:     &fakePositionService{},
+		MaxComplexity: 1000,
+		MaxDepth:      10,
+		validator:     util.NewValidator(),
+	}
+	schema, err := s.buildSchema()
+	if err != nil {
+		panic(err)
+	}
+	s.schema = schema
+	return s, employees, departments
+}
+
+func execute(t *testing.T, s *Server, rq Request) map[string]interface{} {
+	t.Helper()
+	res, rejected := s.Execute(context.Background(), rq)
+	if rejected || res.HasErrors() {
+		t.Fatalf("unexpected errors: %v", res.Errors)
+	}
+	b, _ := json.Marshal(res.Data)
+	var data map[string]interface{}
+	json.Unmarshal(b, &data)
+	return data
+}
+
+func TestServer_BatchesRelatedLookups(t *testing.T) {
+	s, employees, departments := newTestServer()
+	data := execute(t, s, Request{Query: `{
+		employees { nodes { firstName salary department { name } manager { firstName } directReports { idEmployee } } }
+	}`})
+
+	nodes := data["employees"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 3 {
+		t.Fatalf("got %d employees", len(nodes))
+	}
+	jane := nodes[0].(map[string]interface{})
+	if jane["salary"] != "1200.5" || jane["department"].(map[string]interface{})["name"] != "Department D1" || jane["manager"] != nil {
+		t.Errorf("unexpected employee %v", jane)
+	}
+	if reports := jane["directReports"].([]interface{}); len(reports) != 2 {
+		t.Errorf("got %d direct reports", len(reports))
+	}
+	if manager := nodes[1].(map[string]interface{})["manager"].(map[string]interface{}); manager["firstName"] != "Jane" {
+		t.Errorf("unexpected manager %v", manager)
+	}
+	if len(departments.calls) != 1 || len(departments.calls[0]) != 2 {
+		t.Errorf("departments fetched in %v", departments.calls)
+	}
+	if len(employees.byIdCalls) != 1 || len(employees.byIdCalls[0]) != 1 {
+		t.Errorf("managers fetched in %v", employees.byIdCalls)
+	}
+	if len(employees.byManagerIds) != 1 || len(employees.byManagerIds[0]) != 3 {
+		t.Errorf("direct reports fetched in %v", employees.byManagerIds)
+	}
+}
+
+func TestServer_PaginatesEmployees(t *testing.T) {
+	s, _, _ := newTestServer()
+	query := `query($after: String) {
+		employees(filter: {status: ["active"]}, first: 1, after: $after) {
+			totalCount edges { cursor node { idEmployee } } pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	page := execute(t, s, Request{Query: query})["employees"].(map[string]interface{})
+	info := page["pageInfo"].(map[string]interface{})
+	if page["totalCount"].(float64) != 2 || info["hasNextPage"] != true {
+		t.Fatalf("unexpected first page %v", page)
+	}
+
+	page = execute(t, s, Request{Query: query, Variables: map[string]interface{}{"after": info["endCursor"]}})["employees"].(map[string]interface{})
+	edges := page["edges"].([]interface{})
+	if len(edges) != 1 || edges[0].(map[string]interface{})["node"].(map[string]interface{})["idEmployee"] != "2" {
+		t.Errorf("unexpected second page %v", edges)
+	}
+	if page["pageInfo"].(map[string]interface{})["hasNextPage"] != false {
+		t.Errorf("expected the last page")
+	}
+}
+
+func TestServer_RejectsCostlyQueries(t *testing.T) {
+	s, _, _ := newTestServer()
+	s.MaxComplexity = 100
+	res, rejected := s.Execute(context.Background(), Request{
+		Query:     `query($n: Int) { employees(first: $n) { nodes { ...names directReports { ...names } } } } fragment names on Employee { firstName lastName }`,
+		Variables: map[string]interface{}{"n": float64(5)},
+	})
+	if !rejected || !strings.Contains(res.Errors[0].Message, "complexity 117") {
+		t.Fatalf("expected a complexity error, got %v", res.Errors)
+	}
+
+	s.MaxComplexity, s.MaxDepth = 1000, 3
+	res, rejected = s.Execute(context.Background(), Request{Query: `{ employee(id: "2") { manager { manager { firstName } } } }`})
+	if !rejected || !strings.Contains(res.Errors[0].Message, "depth 4") {
+		t.Fatalf("expected a depth error, got %v", res.Errors)
+	}
+}
+
+func TestServer_RejectsMutationsOverGet(t *testing.T) {
+	s, _, _ := newTestServer()
+	_, rejected := s.Execute(context.Background(), Request{Query: `mutation { deleteEmployee(id: "1") }`, QueryOnly: true})
+	if !rejected {
+		t.Error("expected the mutation to be rejected")
+	}
+}
+
+func TestServer_CreateEmployee(t *testing.T) {
+	s, _, _ := newTestServer()
+	query := `mutation($input: EmployeeInput!) { createEmployee(input: $input) { idEmployee status salary } }`
+	input := map[string]interface{}{"idEmployee": "4", "firstName": "Ann", "lastName": "Lee", "email": "ann@example.com", "phone": "555", "salary": "900"}
+
+	data := execute(t, s, Request{Query: query, Variables: map[string]interface{}{"input": input}})
+	created := data["createEmployee"].(map[string]interface{})
+	if created["status"] != "onboarding" || created["salary"] != "900" {
+		t.Errorf("unexpected employee %v", created)
+	}
+
+	res, _ := s.Execute(context.Background(), Request{Query: query, Variables: map[string]interface{}{"input": input}})
+	if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != "CONFLICTED" {
+		t.Errorf("expected a conflict, got %v", res.Errors)
+	}
+}
+
+func TestServer_UnknownEmployeeIsNull(t *testing.T) {
+	s, _, _ := newTestServer()
+	data := execute(t, s, Request{Query: `{ employee(id: "9") { firstName } }`})
+	if data["employee"] != nil {
+		t.Errorf("expected null, got %v", data["employee"])
+	}
+}
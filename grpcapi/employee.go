@@ -0,0 +1,274 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/grpcapi/employeepb"
+	"employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/service"
+	"employee-golang/util"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// EmployeeServer implements EmployeeService on top of
+// service.IEmployeeService.
+type EmployeeServer struct {
+	employeepb.UnimplementedEmployeeServiceServer
+	Service   service.IEmployeeService
+	validator *validator.Validate
+}
+
+func NewEmployeeServer(employees service.IEmployeeService) *EmployeeServer {
+	return &EmployeeServer{
+		Service:   employees,
+		validator: util.NewValidator(),
+	}
+}
+
+func (s *EmployeeServer) ListEmployees(rq *employeepb.ListEmployeesRequest, stream employeepb.EmployeeService_ListEmployeesServer) error {
+	rs, err := s.Service.GetEmployeesByStatus(stream.Context(), strings.Join(rq.GetStatuses(), ","))
+	if err != nil {
+		return toStatus(err)
+	}
+	return sendEmployees(stream, rs)
+}
+
+func (s *EmployeeServer) GetEmployee(ctx context.Context, rq *employeepb.GetEmployeeRequest) (*employeepb.Employee, error) {
+	if rq.GetIdEmployee() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id_employee is required")
+	}
+	return s.getEmployee(ctx, rq.GetIdEmployee())
+}
+
+func (s *EmployeeServer) CreateEmployee(ctx context.Context, rq *employeepb.CreateEmployeeRequest) (*employeepb.Employee, error) {
+	employee, err := s.employeeFromRequest(rq.GetEmployee())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.Service.InsertEmployee(ctx, employee); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.getEmployee(ctx, employee.IdEmployee)
+}
+
+func (s *EmployeeServer) UpdateEmployee(ctx context.Context, rq *employeepb.UpdateEmployeeRequest) (*employeepb.Employee, error) {
+	employee, err := s.employeeFromRequest(rq.GetEmployee())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.Service.UpdateEmployee(ctx, employee); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.getEmployee(ctx, employee.IdEmployee)
+}
+
+func (s *EmployeeServer) DeleteEmployee(ctx context.Context, rq *employeepb.DeleteEmployeeRequest) (*employeepb.DeleteEmployeeResponse, error) {
+	if rq.GetIdEmployee() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id_employee is required")
+	}
+	rs, err := s.Service.DeleteEmployee(ctx, rq.GetIdEmployee())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &employeepb.DeleteEmployeeResponse{Message: rs}, nil
+}
+
+func (s *EmployeeServer) ListDirectReports(rq *employeepb.GetEmployeeRequest, stream employeepb.EmployeeService_ListDirectReportsServer) error {
+	rs, err := s.Service.GetDirectReports(stream.Context(), rq.GetIdEmployee())
+	if err != nil {
+		return toStatus(err)
+	}
+	return sendEmployees(stream, rs)
+}
+
+func (s *EmployeeServer) ListReportingChain(rq *employeepb.GetEmployeeRequest, stream employeepb.EmployeeService_ListReportingChainServer) error {
+	rs, err := s.Service.GetReportingChain(stream.Context(), rq.GetIdEmployee())
+	if err != nil {
+		return toStatus(err)
+	}
+	return sendEmployees(stream, rs)
+}
+
+func (s *EmployeeServer) GetOrgChart(ctx context.Context, rq *employeepb.GetEmployeeRequest) (*employeepb.OrgChartNode, error) {
+	rs, err := s.Service.GetOrgChart(ctx, rq.GetIdEmployee())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return orgChartToProto(rs), nil
+}
+
+func (s *EmployeeServer) ChangeStatus(ctx context.Context, rq *employeepb.ChangeStatusRequest) (*employeepb.Employee, error) {
+	change := &model.StatusChange{Status: rq.GetStatus(), EffectiveDate: rq.GetEffectiveDate(), Reason: rq.GetReason()}
+	if err := s.validator.Struct(change); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := s.Service.ChangeStatus(ctx, rq.GetIdEmployee(), change); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.getEmployee(ctx, rq.GetIdEmployee())
+}
+
+func (s *EmployeeServer) Rehire(ctx context.Context, rq *employeepb.RehireRequest) (*employeepb.Employee, error) {
+	rehire := &model.Rehire{Status: rq.GetStatus(), HireDate: rq.GetHireDate(), Reason: rq.GetReason()}
+	if err := s.validator.Struct(rehire); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := s.Service.Rehire(ctx, rq.GetIdEmployee(), rehire); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.getEmployee(ctx, rq.GetIdEmployee())
+}
+
+func (s *EmployeeServer) ListStatusHistory(rq *employeepb.GetEmployeeRequest, stream employeepb.EmployeeService_ListStatusHistoryServer) error {
+	rs, err := s.Service.GetStatusHistory(stream.Context(), rq.GetIdEmployee())
+	if err != nil {
+		return toStatus(err)
+	}
+	for _, change := range rs {
+		if err := stream.Send(&employeepb.StatusChange{
+			IdEmployee:    change.IdEmployee,
+			FromStatus:    change.FromStatus,
+			Status:        change.Status,
+			EffectiveDate: change.EffectiveDate,
+			Reason:        change.Reason,
+			CreatedAt:     change.CreatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EmployeeServer) getEmployee(ctx context.Context, id string) (*employeepb.Employee, error) {
+	rs, err := s.Service.GetEmployeeById(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return employeeToProto(rs), nil
+}
+
+// employeeFromRequest converts and validates an employee the way the REST
+// handlers bind and validate the request body.
+func (s *EmployeeServer) employeeFromRequest(e *employeepb.Employee) (*model.Employee, error) {
+	if e == nil {
+		return nil, status.Error(codes.InvalidArgument, "employee is required")
+	}
+	salary := decimal.Zero
+	if e.GetSalary() != "" {
+		var err error
+		if salary, err = decimal.NewFromString(e.GetSalary()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "salary must be a decimal number")
+		}
+	}
+	employee := &model.Employee{
+		IdEmployee:           e.GetIdEmployee(),
+		FirstName:            e.GetFirstName(),
+		LastName:             e.GetLastName(),
+		Email:                e.GetEmail(),
+		Phone:                e.GetPhone(),
+		HireDate:             e.GetHireDate(),
+		Salary:               model.NewMoney(salary),
+		Currency:             e.GetCurrency(),
+		DepartmentId:         e.GetDepartmentId(),
+		ManagerId:            e.GetManagerId(),
+		PositionId:           e.GetPositionId(),
+		Status:               e.GetStatus(),
+		SalaryOverrideReason: e.GetSalaryOverrideReason(),
+	}
+	if err := s.validator.Struct(employee); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return employee, nil
+}
+
+type employeeStream interface {
+	Send(*employeepb.Employee) error
+}
+
+func sendEmployees(stream employeeStream, employees []*model.Employee) error {
+	for _, e := range employees {
+		if err := stream.Send(employeeToProto(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func employeeToProto(e *model.Employee) *employeepb.Employee {
+	return &employeepb.Employee{
+		IdEmployee:        e.IdEmployee,
+		FirstName:         e.FirstName,
+		LastName:          e.LastName,
+		Email:             e.Email,
+		Phone:             e.Phone,
+		HireDate:          e.HireDate,
+		Salary:            e.Salary.String(),
+		Currency:          e.Currency,
+		DepartmentId:      e.DepartmentId,
+		ManagerId:         e.ManagerId,
+		PositionId:        e.PositionId,
+		Status:            e.Status,
+		TerminationDate:   e.TerminationDate,
+		TerminationReason: e.TerminationReason,
+		PhotoUrl:          e.PhotoUrl,
+	}
+}
+
+func orgChartToProto(node *model.OrgChartNode) *employeepb.OrgChartNode {
+	res := &employeepb.OrgChartNode{Employee: employeeToProto(node.Employee)}
+	for _, report := range node.Reports {
+		res.Reports = append(res.Reports, orgChartToProto(report))
+	}
+	return res
+}
+
+// toStatus maps service errors to the status codes grpc-gateway turns
+// into the HTTP statuses the REST API answers with. Other errors are logged
+// and answered as Internal without their text, which may describe the
+// database.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "Data Not Found")
+	case err.Error() == "employee already exists":
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrStatusTransition), errors.Is(err, model.ErrNotTerminated), errors.Is(err, model.ErrEmployeeHasReports):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case isInvalidArgument(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	logrus.Errorf("internal error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func isInvalidArgument(err error) bool {
+	for _, target := range []error{
+		model.ErrDepartmentNotFound,
+		model.ErrManagerNotFound,
+		model.ErrManagerCycle,
+		model.ErrHierarchyTooDeep,
+		model.ErrPositionNotFound,
+		model.ErrSalaryOutOfBand,
+		money.ErrUnknownCurrency,
+		model.ErrStatusNotEditable,
+		model.ErrUnknownStatus,
+		model.ErrTerminationReason,
+		model.ErrStatusFutureDate,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/grpcapi/employeepb"
+	"employee-golang/model"
+	"employee-golang/service"
+	"employee-golang/util"
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+)
+
+type fakeEmployeeService struct {
+	service.IEmployeeService
+	employees map[string]*model.Employee
+	statuses  string
+	client    string
+}
+
+func (s *fakeEmployeeService) GetEmployeesByStatus(ctx context.Context, filter string) ([]*model.Employee, error) {
+	s.statuses, s.client = filter, util.ClientFrom(ctx)
+	if filter == "retired" {
+		return nil, model.ErrUnknownStatus
+	}
+	return []*model.Employee{s.employees["1"], s.employees["2"]}, nil
+}
+
+func (s *fakeEmployeeService) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	if id == "unreachable" {
+		return nil, errors.New("dial tcp 10.0.0.5:3306: connect: connection refused")
+	}
+	if e, ok := s.employees[id]; ok {
+		return e, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *fakeEmployeeService) InsertEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	if _, ok := s.employees[employee.IdEmployee]; ok {
+		return "Employee already exists", errors.New("employee already exists")
+	}
+	if employee.DepartmentId == "missing" {
+		return "", model.ErrDepartmentNotFound
+	}
+	employee.Status = "onboarding"
+	s.employees[employee.IdEmployee] = employee
+	return "Employee was added", nil
+}
+
+func newTestClient(t *testing.T, employees service.IEmployeeService) employeepb.EmployeeServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(employees)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return employeepb.NewEmployeeServiceClient(conn)
+}
+
+func newFakeEmployeeService() *fakeEmployeeService {
+	return &fakeEmployeeService{employees: map[string]*model.Employee{
+		"1": {IdEmployee: "1", FirstName: "Jane", Salary: model.NewMoney(decimal.RequireFromString("1200.50")), Currency: "USD", Status: "active"},
+		"2": {IdEmployee: "2", FirstName: "John", ManagerId: "1", Status: "on_leave"},
+	}}
+}
+
+func TestEmployeeServer_ListEmployees(t *testing.T) {
+	viper.Set("api.keys", []string{"payroll-key"})
+	t.Cleanup(viper.Reset)
+	employees := newFakeEmployeeService()
+	client := newTestClient(t, employees)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "payroll-key")
+	stream, err := client.ListEmployees(ctx, &employeepb.ListEmployeesRequest{Statuses: []string{"active", "on_leave"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, e.IdEmployee)
+		if e.IdEmployee == "1" && e.Salary != "1200.5" {
+			t.Errorf("salary = %q", e.Salary)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Errorf("streamed %v", ids)
+	}
+	if employees.statuses != "active,on_leave" || employees.client != util.NewAPIKeys([]string{"payroll-key"}).ClientId("payroll-key") {
+		t.Errorf("service called with statuses %q by %q", employees.statuses, employees.client)
+	}
+
+	stream, err = client.ListEmployees(context.Background(), &employeepb.ListEmployeesRequest{Statuses: []string{"retired"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown status error = %v", err)
+	}
+}
+
+func TestEmployeeServer_errorCodes(t *testing.T) {
+	client := newTestClient(t, newFakeEmployeeService())
+	ctx := context.Background()
+	valid := func(id, department string) *employeepb.Employee {
+		return &employeepb.Employee{IdEmployee: id, FirstName: "Ann", LastName: "Lee", Email: "ann@example.com",
+			Phone: "555", Salary: "900", Currency: "EUR", DepartmentId: department}
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{name: "get", want: codes.OK, call: func() error {
+			_, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{IdEmployee: "1"})
+			return err
+		}},
+		{name: "get missing", want: codes.NotFound, call: func() error {
+			_, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{IdEmployee: "9"})
+			return err
+		}},
+		{name: "create", want: codes.OK, call: func() error {
+			e, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: valid("3", "")})
+			if err == nil && (e.Status != "onboarding" || e.Salary != "900") {
+				t.Errorf("created %v", e)
+			}
+			return err
+		}},
+		{name: "create existing", want: codes.AlreadyExists, call: func() error {
+			_, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: valid("1", "")})
+			return err
+		}},
+		{name: "create in missing department", want: codes.InvalidArgument, call: func() error {
+			_, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: valid("4", "missing")})
+			return err
+		}},
+		{name: "create without name", want: codes.InvalidArgument, call: func() error {
+			e := valid("5", "")
+			e.FirstName = ""
+			_, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: e})
+			return err
+		}},
+		{name: "create with bad salary", want: codes.InvalidArgument, call: func() error {
+			e := valid("6", "")
+			e.Salary = "a lot"
+			_, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: e})
+			return err
+		}},
+		{name: "create with negative salary", want: codes.InvalidArgument, call: func() error {
+			e := valid("7", "")
+			e.Salary = "-0.01"
+			_, err := client.CreateEmployee(ctx, &employeepb.CreateEmployeeRequest{Employee: e})
+			return err
+		}},
+		{name: "database down", want: codes.Internal, call: func() error {
+			_, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{IdEmployee: "unreachable"})
+			if msg := status.Convert(err).Message(); msg != "internal error" {
+				t.Errorf("message = %q, want the cause left out", msg)
+			}
+			return err
+		}},
+		{name: "not implemented by the fake", want: codes.Internal, call: func() error {
+			_, err := client.DeleteEmployee(ctx, &employeepb.DeleteEmployeeRequest{IdEmployee: "1"})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,595 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: employee/v1/employee.proto
+
+// The employee API over gRPC. It mirrors /api/v1/employees and is served by
+// the same service layer, so both behave alike.
+
+package employeepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	EmployeeService_ListEmployees_FullMethodName      = "/employee.v1.EmployeeService/ListEmployees"
+	EmployeeService_GetEmployee_FullMethodName        = "/employee.v1.EmployeeService/GetEmployee"
+	EmployeeService_CreateEmployee_FullMethodName     = "/employee.v1.EmployeeService/CreateEmployee"
+	EmployeeService_UpdateEmployee_FullMethodName     = "/employee.v1.EmployeeService/UpdateEmployee"
+	EmployeeService_DeleteEmployee_FullMethodName     = "/employee.v1.EmployeeService/DeleteEmployee"
+	EmployeeService_ListDirectReports_FullMethodName  = "/employee.v1.EmployeeService/ListDirectReports"
+	EmployeeService_ListReportingChain_FullMethodName = "/employee.v1.EmployeeService/ListReportingChain"
+	EmployeeService_GetOrgChart_FullMethodName        = "/employee.v1.EmployeeService/GetOrgChart"
+	EmployeeService_ChangeStatus_FullMethodName       = "/employee.v1.EmployeeService/ChangeStatus"
+	EmployeeService_Rehire_FullMethodName             = "/employee.v1.EmployeeService/Rehire"
+	EmployeeService_ListStatusHistory_FullMethodName  = "/employee.v1.EmployeeService/ListStatusHistory"
+)
+
+// EmployeeServiceClient is the client API for EmployeeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EmployeeServiceClient interface {
+	// ListEmployees streams every employee, or those whose status is listed.
+	ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (EmployeeService_ListEmployeesClient, error)
+	GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	DeleteEmployee(ctx context.Context, in *DeleteEmployeeRequest, opts ...grpc.CallOption) (*DeleteEmployeeResponse, error)
+	ListDirectReports(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListDirectReportsClient, error)
+	// ListReportingChain streams the managers of an employee, nearest first.
+	ListReportingChain(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListReportingChainClient, error)
+	GetOrgChart(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*OrgChartNode, error)
+	ChangeStatus(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*Employee, error)
+	Rehire(ctx context.Context, in *RehireRequest, opts ...grpc.CallOption) (*Employee, error)
+	ListStatusHistory(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListStatusHistoryClient, error)
+}
+
+type employeeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmployeeServiceClient(cc grpc.ClientConnInterface) EmployeeServiceClient {
+	return &employeeServiceClient{cc}
+}
+
+func (c *employeeServiceClient) ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (EmployeeService_ListEmployeesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EmployeeService_ServiceDesc.Streams[0], EmployeeService_ListEmployees_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &employeeServiceListEmployeesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EmployeeService_ListEmployeesClient interface {
+	Recv() (*Employee, error)
+	grpc.ClientStream
+}
+
+type employeeServiceListEmployeesClient struct {
+	grpc.ClientStream
+}
+
+func (x *employeeServiceListEmployeesClient) Recv() (*Employee, error) {
+	m := new(Employee)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *employeeServiceClient) GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_GetEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_CreateEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_UpdateEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) DeleteEmployee(ctx context.Context, in *DeleteEmployeeRequest, opts ...grpc.CallOption) (*DeleteEmployeeResponse, error) {
+	out := new(DeleteEmployeeResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_DeleteEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ListDirectReports(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListDirectReportsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EmployeeService_ServiceDesc.Streams[1], EmployeeService_ListDirectReports_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &employeeServiceListDirectReportsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EmployeeService_ListDirectReportsClient interface {
+	Recv() (*Employee, error)
+	grpc.ClientStream
+}
+
+type employeeServiceListDirectReportsClient struct {
+	grpc.ClientStream
+}
+
+func (x *employeeServiceListDirectReportsClient) Recv() (*Employee, error) {
+	m := new(Employee)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *employeeServiceClient) ListReportingChain(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListReportingChainClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EmployeeService_ServiceDesc.Streams[2], EmployeeService_ListReportingChain_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &employeeServiceListReportingChainClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EmployeeService_ListReportingChainClient interface {
+	Recv() (*Employee, error)
+	grpc.ClientStream
+}
+
+type employeeServiceListReportingChainClient struct {
+	grpc.ClientStream
+}
+
+func (x *employeeServiceListReportingChainClient) Recv() (*Employee, error) {
+	m := new(Employee)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *employeeServiceClient) GetOrgChart(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*OrgChartNode, error) {
+	out := new(OrgChartNode)
+	err := c.cc.Invoke(ctx, EmployeeService_GetOrgChart_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ChangeStatus(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*Employee, error) {
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_ChangeStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) Rehire(ctx context.Context, in *RehireRequest, opts ...grpc.CallOption) (*Employee, error) {
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_Rehire_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ListStatusHistory(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (EmployeeService_ListStatusHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EmployeeService_ServiceDesc.Streams[3], EmployeeService_ListStatusHistory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &employeeServiceListStatusHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EmployeeService_ListStatusHistoryClient interface {
+	Recv() (*StatusChange, error)
+	grpc.ClientStream
+}
+
+type employeeServiceListStatusHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *employeeServiceListStatusHistoryClient) Recv() (*StatusChange, error) {
+	m := new(StatusChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EmployeeServiceServer is the server API for EmployeeService service.
+// All implementations must embed UnimplementedEmployeeServiceServer
+// for forward compatibility
+type EmployeeServiceServer interface {
+	// ListEmployees streams every employee, or those whose status is listed.
+	ListEmployees(*ListEmployeesRequest, EmployeeService_ListEmployeesServer) error
+	GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error)
+	CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error)
+	UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error)
+	DeleteEmployee(context.Context, *DeleteEmployeeRequest) (*DeleteEmployeeResponse, error)
+	ListDirectReports(*GetEmployeeRequest, EmployeeService_ListDirectReportsServer) error
+	// ListReportingChain streams the managers of an employee, nearest first.
+	ListReportingChain(*GetEmployeeRequest, EmployeeService_ListReportingChainServer) error
+	GetOrgChart(context.Context, *GetEmployeeRequest) (*OrgChartNode, error)
+	ChangeStatus(context.Context, *ChangeStatusRequest) (*Employee, error)
+	Rehire(context.Context, *RehireRequest) (*Employee, error)
+	ListStatusHistory(*GetEmployeeRequest, EmployeeService_ListStatusHistoryServer) error
+	mustEmbedUnimplementedEmployeeServiceServer()
+}
+
+// UnimplementedEmployeeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEmployeeServiceServer struct {
+}
+
+func (UnimplementedEmployeeServiceServer) ListEmployees(*ListEmployeesRequest, EmployeeService_ListEmployeesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) DeleteEmployee(context.Context, *DeleteEmployeeRequest) (*DeleteEmployeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListDirectReports(*GetEmployeeRequest, EmployeeService_ListDirectReportsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDirectReports not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListReportingChain(*GetEmployeeRequest, EmployeeService_ListReportingChainServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListReportingChain not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetOrgChart(context.Context, *GetEmployeeRequest) (*OrgChartNode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrgChart not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ChangeStatus(context.Context, *ChangeStatusRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeStatus not implemented")
+}
+func (UnimplementedEmployeeServiceServer) Rehire(context.Context, *RehireRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rehire not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListStatusHistory(*GetEmployeeRequest, EmployeeService_ListStatusHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListStatusHistory not implemented")
+}
+func (UnimplementedEmployeeServiceServer) mustEmbedUnimplementedEmployeeServiceServer() {}
+
+// UnsafeEmployeeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmployeeServiceServer will
+// result in compilation errors.
+type UnsafeEmployeeServiceServer interface {
+	mustEmbedUnimplementedEmployeeServiceServer()
+}
+
+func RegisterEmployeeServiceServer(s grpc.ServiceRegistrar, srv EmployeeServiceServer) {
+	s.RegisterService(&EmployeeService_ServiceDesc, srv)
+}
+
+func _EmployeeService_ListEmployees_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListEmployeesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmployeeServiceServer).ListEmployees(m, &employeeServiceListEmployeesServer{stream})
+}
+
+type EmployeeService_ListEmployeesServer interface {
+	Send(*Employee) error
+	grpc.ServerStream
+}
+
+type employeeServiceListEmployeesServer struct {
+	grpc.ServerStream
+}
+
+func (x *employeeServiceListEmployeesServer) Send(m *Employee) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EmployeeService_GetEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetEmployee(ctx, req.(*GetEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_CreateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).CreateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_CreateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).CreateEmployee(ctx, req.(*CreateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_UpdateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).UpdateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_UpdateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).UpdateEmployee(ctx, req.(*UpdateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_DeleteEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).DeleteEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_DeleteEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).DeleteEmployee(ctx, req.(*DeleteEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ListDirectReports_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEmployeeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmployeeServiceServer).ListDirectReports(m, &employeeServiceListDirectReportsServer{stream})
+}
+
+type EmployeeService_ListDirectReportsServer interface {
+	Send(*Employee) error
+	grpc.ServerStream
+}
+
+type employeeServiceListDirectReportsServer struct {
+	grpc.ServerStream
+}
+
+func (x *employeeServiceListDirectReportsServer) Send(m *Employee) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EmployeeService_ListReportingChain_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEmployeeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmployeeServiceServer).ListReportingChain(m, &employeeServiceListReportingChainServer{stream})
+}
+
+type EmployeeService_ListReportingChainServer interface {
+	Send(*Employee) error
+	grpc.ServerStream
+}
+
+type employeeServiceListReportingChainServer struct {
+	grpc.ServerStream
+}
+
+func (x *employeeServiceListReportingChainServer) Send(m *Employee) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EmployeeService_GetOrgChart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetOrgChart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetOrgChart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetOrgChart(ctx, req.(*GetEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ChangeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).ChangeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_ChangeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).ChangeStatus(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_Rehire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RehireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).Rehire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_Rehire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).Rehire(ctx, req.(*RehireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ListStatusHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEmployeeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmployeeServiceServer).ListStatusHistory(m, &employeeServiceListStatusHistoryServer{stream})
+}
+
+type EmployeeService_ListStatusHistoryServer interface {
+	Send(*StatusChange) error
+	grpc.ServerStream
+}
+
+type employeeServiceListStatusHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *employeeServiceListStatusHistoryServer) Send(m *StatusChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// EmployeeService_ServiceDesc is the grpc.ServiceDesc for EmployeeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmployeeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "employee.v1.EmployeeService",
+	HandlerType: (*EmployeeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEmployee",
+			Handler:    _EmployeeService_GetEmployee_Handler,
+		},
+		{
+			MethodName: "CreateEmployee",
+			Handler:    _EmployeeService_CreateEmployee_Handler,
+		},
+		{
+			MethodName: "UpdateEmployee",
+			Handler:    _EmployeeService_UpdateEmployee_Handler,
+		},
+		{
+			MethodName: "DeleteEmployee",
+			Handler:    _EmployeeService_DeleteEmployee_Handler,
+		},
+		{
+			MethodName: "GetOrgChart",
+			Handler:    _EmployeeService_GetOrgChart_Handler,
+		},
+		{
+			MethodName: "ChangeStatus",
+			Handler:    _EmployeeService_ChangeStatus_Handler,
+		},
+		{
+			MethodName: "Rehire",
+			Handler:    _EmployeeService_Rehire_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListEmployees",
+			Handler:       _EmployeeService_ListEmployees_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListDirectReports",
+			Handler:       _EmployeeService_ListDirectReports_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListReportingChain",
+			Handler:       _EmployeeService_ListReportingChain_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListStatusHistory",
+			Handler:       _EmployeeService_ListStatusHistory_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "employee/v1/employee.proto",
+}
@@ -0,0 +1,106 @@
+// Package grpcapi serves the employee API over gRPC, backed by the same
+// service layer as the REST controllers.
+package grpcapi
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=employee-golang --go-grpc_out=.. --go-grpc_opt=module=employee-golang employee/v1/employee.proto
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/grpcapi/employeepb"
+	"employee-golang/service"
+	"employee-golang/util"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"net"
+	"runtime/debug"
+)
+
+// Serve listens on the configured address and serves until the listener
+// fails.
+func Serve() error {
+	lis, err := net.Listen("tcp", config.GetGRPCAddress())
+	if err != nil {
+		return err
+	}
+	logrus.Infof("gRPC server listening on %s", lis.Addr())
+	return NewServer(service.NewEmployeeService()).Serve(lis)
+}
+
+// NewServer builds a server exposing EmployeeService, the standard health
+// service and reflection.
+func NewServer(employees service.IEmployeeService) *grpc.Server {
+	i := &interceptors{keys: util.NewAPIKeys(config.GetAPIKeys()), jwt: util.JWTSecret(config.GetJWTSecret())}
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	)
+	employeepb.RegisterEmployeeServiceServer(s, NewEmployeeServer(employees))
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	reflection.Register(s)
+	return s
+}
+
+type interceptors struct {
+	keys util.APIKeys
+	jwt  util.JWTSecret
+}
+
+// unary records the calling client on the context, as the REST handlers
+// do, and turns panics into Internal errors.
+func (i *interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer recoverCall(info.FullMethod, &err)
+	return handler(util.WithClient(ctx, i.clientId(ctx)), req)
+}
+
+func (i *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverCall(info.FullMethod, &err)
+	return handler(srv, &clientStream{ServerStream: ss, ctx: util.WithClient(ss.Context(), i.clientId(ss.Context()))})
+}
+
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context {
+	return s.ctx
+}
+
+func recoverCall(method string, err *error) {
+	if r := recover(); r != nil {
+		logrus.Errorf("panic in %s: %v\n%s", method, r, debug.Stack())
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// clientId identifies the caller like util.ClientId: by the x-api-key
+// metadata when it is a configured key, else by the subject of a verified
+// bearer token in the authorization metadata, otherwise by the peer address.
+func (i *interceptors) clientId(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-api-key"); len(v) > 0 {
+		if id := i.keys.ClientId(v[0]); id != "" {
+			return id
+		}
+	}
+	if v := md.Get("authorization"); len(v) > 0 {
+		if id := i.jwt.ClientId(v[0]); id != "" {
+			return id
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "ip:" + host
+		}
+		return "ip:" + p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+// Package imaging scales images down for thumbnails using only the standard
+// library.
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// FitSize is the size of an image of width w and height h scaled to fit a
+// box x box square, keeping its aspect ratio. Images already inside the box
+// keep their size.
+func FitSize(w, h, box int) (int, int) {
+	if w <= box && h <= box {
+		return w, h
+	}
+	if w >= h {
+		return box, atLeastOne((h*box + w/2) / w)
+	}
+	return atLeastOne((w*box + h/2) / h), box
+}
+
+// Fit scales src down to fit a box x box square. Each destination pixel is
+// the average of the source pixels it covers, which avoids the aliasing of
+// nearest-neighbour sampling when shrinking photos by large factors.
+// Averaging happens on premultiplied colour so transparent pixels do not
+// darken their neighbours.
+func Fit(src image.Image, box int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	dw, dh := FitSize(sw, sh, box)
+	if dw == sw && dh == sh {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := span(dy, sh, dh)
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := span(dx, sw, dw)
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				row := rgba.Pix[y*rgba.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					bl += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			o := dst.PixOffset(dx, dy)
+			dst.Pix[o] = uint8((r + n/2) / n)
+			dst.Pix[o+1] = uint8((g + n/2) / n)
+			dst.Pix[o+2] = uint8((bl + n/2) / n)
+			dst.Pix[o+3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span is the range of source pixels destination pixel d covers when src
+// pixels are shrunk to dst.
+func span(d, src, dst int) (int, int) {
+	from, to := d*src/dst, (d+1)*src/dst
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+func atLeastOne(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
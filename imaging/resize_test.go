@@ -0,0 +1,58 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		w, h, box    int
+		wantW, wantH int
+	}{
+		{w: 1000, h: 500, box: 100, wantW: 100, wantH: 50},
+		{w: 500, h: 1000, box: 100, wantW: 50, wantH: 100},
+		{w: 80, h: 60, box: 100, wantW: 80, wantH: 60},
+		{w: 3000, h: 2, box: 64, wantW: 64, wantH: 1},
+		{w: 333, h: 200, box: 64, wantW: 64, wantH: 38},
+	}
+	for _, tt := range tests {
+		if w, h := FitSize(tt.w, tt.h, tt.box); w != tt.wantW || h != tt.wantH {
+			t.Errorf("FitSize(%d, %d, %d) = %d, %d, want %d, %d", tt.w, tt.h, tt.box, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	// a 4x2 image of black and white columns averages to grey
+	src := image.NewGray(image.Rect(10, 10, 14, 12))
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 14; x++ {
+			if x%2 == 0 {
+				src.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	dst := Fit(src, 2)
+	if dst.Bounds() != image.Rect(0, 0, 2, 1) {
+		t.Fatalf("Fit() bounds = %v", dst.Bounds())
+	}
+	for x := 0; x < 2; x++ {
+		if c := dst.RGBAAt(x, 0); c != (color.RGBA{R: 128, G: 128, B: 128, A: 255}) {
+			t.Errorf("Fit() pixel %d = %v, want grey", x, c)
+		}
+	}
+
+	// transparent pixels do not darken the opaque ones they are averaged with
+	src2 := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src2.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	if c := Fit(src2, 1).RGBAAt(0, 0); c != (color.RGBA{R: 128, A: 128}) {
+		t.Errorf("Fit() pixel = %v, want half-transparent red", c)
+	}
+
+	small := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	if Fit(small, 10) != small {
+		t.Errorf("Fit() should return images inside the box unchanged")
+	}
+}
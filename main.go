@@ -3,7 +3,13 @@ package main
 import (
 	"employee-golang/config"
 	"employee-golang/controller"
+	"employee-golang/grpcapi"
+	"employee-golang/middleware"
+	"employee-golang/repositories"
+	"employee-golang/service"
+	"employee-golang/util"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 func init() {
@@ -11,7 +17,53 @@ func init() {
 }
 
 func main() {
+	if err := repositories.WaitForConnection(); err != nil {
+		logrus.Fatal(err)
+	}
 	e := *echo.New()
 	config.InitSwagger(&e)
+	e.Pre(middleware.APIVersioning())
+	e.Pre(middleware.ContentNegotiation())
+	e.Binder = &util.Binder{}
+	e.Validator = &controller.CustomValidator{Validator: util.NewValidator()}
+	e.IPExtractor = util.IPExtractor(config.GetTrustedProxies())
+	e.Use(middleware.ClientIdentification())
+	if config.IsRateLimitEnabled() {
+		e.Use(middleware.RateLimit())
+	}
+	if config.IsIdempotencyEnabled() {
+		e.Use(middleware.IdempotencyKeys())
+	}
+	spec := controller.NewOpenAPISpec(&e)
+	if config.IsOpenAPIValidationEnabled() {
+		e.Use(middleware.NewOpenAPIValidation(spec))
+	}
+	if config.IsEventRelayEnabled() {
+		relay, err := service.NewEventRelay()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		relay.Start()
+	}
+	if config.IsWebhookDispatcherEnabled() {
+		service.NewWebhookDispatcher().Start()
+	}
+	if config.IsGRPCEnabled() {
+		go func() {
+			logrus.Fatal(grpcapi.Serve())
+		}()
+	}
+	controller.DepartmentController(&e)
+	controller.PositionController(&e)
+	controller.ReportController(&e)
+	controller.LeaveController(&e)
+	controller.AttendanceController(&e)
+	controller.DocumentController(&e)
+	controller.PhotoController(&e)
+	controller.SearchController(&e)
+	controller.WebhookController(&e)
+	controller.GraphQLController(&e)
+	controller.OpenAPIController(&e, spec)
 	controller.EmployeeController(&e)
+	e.Logger.Fatal(e.Start(":8080"))
 }
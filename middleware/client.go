@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"employee-golang/config"
+	"employee-golang/util"
+	"github.com/labstack/echo/v4"
+)
+
+// ClientIdentity identifies callers by their X-API-Key when it is one of
+// Keys, else by the subject of a bearer token signed with JWT. Other
+// callers, including those sending an unknown key or a token that does not
+// verify, are identified by their IP address.
+type ClientIdentity struct {
+	Keys util.APIKeys
+	JWT  util.JWTSecret
+}
+
+// ClientIdentification builds the middleware from the api.keys and
+// api.jwt_secret settings.
+func ClientIdentification() echo.MiddlewareFunc {
+	return (&ClientIdentity{
+		Keys: util.NewAPIKeys(config.GetAPIKeys()),
+		JWT:  util.JWTSecret(config.GetJWTSecret()),
+	}).Middleware
+}
+
+func (m *ClientIdentity) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header
+		if id := m.Keys.ClientId(header.Get("X-API-Key")); id != "" {
+			util.SetClientId(c, id)
+		} else if id := m.JWT.ClientId(header.Get(echo.HeaderAuthorization)); id != "" {
+			util.SetClientId(c, id)
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"employee-golang/util"
+	"encoding/hex"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header. Keys are scoped per client; reusing a key with
+// a different method, path or body is rejected with 422, and a retry that
+// arrives while the first request is still running gets 409. Server errors
+// are not stored so the client can retry them.
+//
+// A key is claimed for Lease while its request runs, so one left behind by a
+// crash frees up quickly, and its response is kept for TTL. Bodies are
+// buffered to be hashed, so requests with a key are capped at MaxBody.
+type Idempotency struct {
+	Store   repositories.IIdempotencyStore
+	TTL     time.Duration
+	Lease   time.Duration
+	MaxBody int64
+	Methods map[string]bool
+}
+
+func NewIdempotency(store repositories.IIdempotencyStore, ttl time.Duration, methods []string) *Idempotency {
+	m := &Idempotency{
+		Store:   store,
+		TTL:     ttl,
+		Lease:   time.Minute,
+		MaxBody: 1 << 20,
+		Methods: map[string]bool{},
+	}
+	for _, method := range methods {
+		m.Methods[method] = true
+	}
+	return m
+}
+
+// IdempotencyKeys builds the idempotency middleware from configuration.
+func IdempotencyKeys() echo.MiddlewareFunc {
+	m := NewIdempotency(repositories.NewIdempotencyStore(), config.GetIdempotencyTTL(), config.GetIdempotencyMethods())
+	m.Lease, m.MaxBody = config.GetIdempotencyLease(), config.GetIdempotencyMaxBody()
+	return m.Middleware
+}
+
+func (m *Idempotency) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idempotencyKey := c.Request().Header.Get(HeaderIdempotencyKey)
+		if idempotencyKey == "" || !m.Methods[c.Request().Method] {
+			return next(c)
+		}
+		if len(idempotencyKey) > 200 {
+			return idempotencyError(c, http.StatusBadRequest, "BAD_REQUEST", "Idempotency-Key is too long")
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, m.MaxBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return idempotencyError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Body is too large for an Idempotency-Key")
+		}
+		if err != nil {
+			return idempotencyError(c, http.StatusBadRequest, "BAD_REQUEST", "Body required")
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request().Method + " " + c.Request().URL.Path + "\n"))
+		if version := util.APIVersion(c); version > 1 {
+			// A v1 response must not be replayed to a v2 request, or back.
+			hash.Write([]byte("v" + strconv.Itoa(version) + "\n"))
+		}
+		if mediaType := util.ResponseType(c.Request().Header.Get(echo.HeaderAccept)); mediaType != echo.MIMEApplicationJSON {
+			// nor a response in one format to a request for another
+			hash.Write([]byte(mediaType + "\n"))
+		}
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request().Context()
+		// hashed to fit the store's key column whatever the client id
+		sum := sha256.Sum256([]byte(util.ClientId(c) + "|" + idempotencyKey))
+		key := hex.EncodeToString(sum[:])
+		rec, created, err := m.Store.Begin(ctx, key, requestHash, m.Lease)
+		if err != nil {
+			logrus.Errorf("Error claiming idempotency key: %v", err)
+			return idempotencyError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Idempotency store unavailable")
+		}
+		if !created {
+			return m.replay(c, rec, requestHash)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		err = next(c)
+		if err != nil {
+			// let echo render the error now so the stored body matches the client's
+			c.Error(err)
+		}
+
+		status := c.Response().Status
+		if status >= http.StatusInternalServerError || !c.Response().Committed {
+			if errRelease := m.Store.Release(context.Background(), key); errRelease != nil {
+				logrus.Errorf("Error releasing idempotency key: %v", errRelease)
+			}
+			return nil
+		}
+		contentType := c.Response().Header().Get(echo.HeaderContentType)
+		if errComplete := m.Store.Complete(context.Background(), key, status, contentType, recorder.body.Bytes(), m.TTL); errComplete != nil {
+			logrus.Errorf("Error storing idempotent response: %v", errComplete)
+		}
+		return nil
+	}
+}
+
+func (m *Idempotency) replay(c echo.Context, rec *model.IdempotencyRecord, requestHash string) error {
+	switch {
+	case rec.RequestHash != requestHash:
+		return idempotencyError(c, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "Idempotency-Key was already used with a different request")
+	case !rec.Completed:
+		return idempotencyError(c, http.StatusConflict, "CONFLICTED", "A request with this Idempotency-Key is still being processed")
+	}
+	c.Response().Header().Set("Idempotent-Replayed", "true")
+	return c.Blob(rec.StatusCode, rec.ContentType, rec.Body)
+}
+
+func idempotencyError(c echo.Context, code int, status, message string) error {
+	response := model.GenericResponse[any]{
+		Code:   code,
+		Status: status,
+		Data:   message,
+	}
+	return util.RespJSONData(c, code, response, errors.New(message))
+}
+
+// responseRecorder copies everything written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
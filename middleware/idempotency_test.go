@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"employee-golang/repositories"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_Middleware(t *testing.T) {
+	calls := 0
+	e := echo.New()
+	m := NewIdempotency(repositories.NewMemoryIdempotencyStore(), time.Hour, []string{http.MethodPost})
+	m.MaxBody = 64
+	e.Use(m.Middleware)
+	e.POST("/api/v1/employees", func(c echo.Context) error {
+		calls++
+		if calls > 1 {
+			return c.JSON(http.StatusConflict, map[string]string{"data": "employee already exists"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"data": "Successfully inserted a new employee"})
+	})
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/employees", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(HeaderIdempotencyKey, key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("abc", `{"idEmployee":"1"}`)
+	replayed := post("abc", `{"idEmployee":"1"}`)
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if replayed.Code != first.Code || replayed.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replayed.Code, replayed.Body, first.Code, first.Body)
+	}
+	if replayed.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("replay is missing the Idempotent-Replayed header")
+	}
+
+	if rec := post("abc", `{"idEmployee":"2"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another payload: status = %d, want 422", rec.Code)
+	}
+	if rec := post("other", `{"idEmployee":"1"}`); rec.Code != http.StatusConflict || calls != 2 {
+		t.Errorf("new key should reach the handler, got %d after %d calls", rec.Code, calls)
+	}
+	if rec := post("large", `{"idEmployee":"`+strings.Repeat("1", 64)+`"}`); rec.Code != http.StatusRequestEntityTooLarge || calls != 2 {
+		t.Errorf("oversized body: status = %d after %d calls, want 413 before the handler", rec.Code, calls)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"employee-golang/util"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+)
+
+// ContentNegotiation answers 406 to API requests that change data but
+// accept no format a response can be written in, before they are handled.
+// Reads are negotiated when their response is written, as some of them
+// answer with images, documents or CSV instead. It must run with echo's Pre,
+// after APIVersioning.
+func ContentNegotiation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rq := c.Request()
+			if rq.Method == http.MethodGet || rq.Method == http.MethodHead || !strings.HasPrefix(rq.URL.Path, "/api/") {
+				return next(c)
+			}
+			if !util.Acceptable(rq.Header.Get(echo.HeaderAccept), false) {
+				return util.NotAcceptable(c)
+			}
+			return next(c)
+		}
+	}
+}
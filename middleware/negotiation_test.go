@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"employee-golang/model"
+	"employee-golang/util"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newNegotiationServer(created *[]*model.Employee) *echo.Echo {
+	e := echo.New()
+	e.Binder = &util.Binder{}
+	e.Pre((&Versioning{}).Middleware)
+	e.Pre(ContentNegotiation())
+	employees := []*model.Employee{
+		{IdEmployee: "1", FirstName: "Jane", Salary: model.NewMoney(decimal.RequireFromString("1200.50")), Status: "active"},
+		{IdEmployee: "2", FirstName: "John, Jr.", Status: "on_leave"},
+	}
+	e.GET("/api/v1/employees", func(c echo.Context) error {
+		return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "OK", Data: employees}, nil)
+	})
+	e.GET("/api/v1/employees/:id", func(c echo.Context) error {
+		return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "OK", Data: employees[0]}, nil)
+	})
+	e.POST("/api/v1/employees", func(c echo.Context) error {
+		rq := new(model.Employee)
+		if err := c.Bind(rq); err != nil {
+			return util.RespJSONData(c, 400, model.GenericResponse[any]{Code: 400, Status: "BAD_REQUEST", Data: err.Error()}, err)
+		}
+		*created = append(*created, rq)
+		return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "OK", Data: "Employee was added"}, nil)
+	})
+	return e
+}
+
+func serve(e *echo.Echo, method, path, accept, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRespJSONData_Negotiation(t *testing.T) {
+	e := newNegotiationServer(nil)
+
+	rec := serve(e, http.MethodGet, "/api/v1/employees/1", "application/xml", "", nil)
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<response><code>200</code><status>OK</status><data><idEmployee>1</idEmployee><firstName>Jane</firstName>` +
+		`<hireDate></hireDate><salary>1200.5</salary><status>active</status></data></response>`
+	if rec.Header().Get(echo.HeaderContentType) != "application/xml; charset=UTF-8" || rec.Body.String() != want {
+		t.Errorf("xml = %s %s, want %s", rec.Header().Get(echo.HeaderContentType), rec.Body, want)
+	}
+
+	rec = serve(e, http.MethodGet, "/api/v1/employees", "text/csv, application/json;q=0.5", "", nil)
+	if rec.Header().Get(echo.HeaderContentType) != "text/csv; charset=UTF-8" {
+		t.Fatalf("csv content type = %s", rec.Header().Get(echo.HeaderContentType))
+	}
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "idEmployee,firstName,") || !strings.HasPrefix(lines[2], `2,"John, Jr.",`) {
+		t.Errorf("csv body = %s", rec.Body.String())
+	}
+
+	// a single employee is not a list, so JSON is next in line
+	rec = serve(e, http.MethodGet, "/api/v1/employees/1", "text/csv, application/json;q=0.5", "", nil)
+	if rec.Header().Get(echo.HeaderContentType) != "application/json; charset=UTF-8" {
+		t.Errorf("fallback content type = %s", rec.Header().Get(echo.HeaderContentType))
+	}
+	if rec := serve(e, http.MethodGet, "/api/v1/employees/1", "text/csv", "", nil); rec.Code != http.StatusNotAcceptable {
+		t.Errorf("csv only for one employee = %d, want 406", rec.Code)
+	}
+
+	rec = serve(e, http.MethodGet, "/api/v1/employees", "application/msgpack", "", nil)
+	var body struct {
+		Code int              `msgpack:"code"`
+		Data []map[string]any `msgpack:"data"`
+	}
+	if err := msgpack.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != 200 || len(body.Data) != 2 || body.Data[0]["firstName"] != "Jane" || body.Data[0]["salary"] != 1200.5 {
+		t.Errorf("msgpack body = %+v", body)
+	}
+
+	rec = serve(e, http.MethodGet, "/api/v1/employees", "text/html, application/json;q=0, */*;q=0.1", "", nil)
+	if rec.Header().Get(echo.HeaderContentType) != "application/xml; charset=UTF-8" {
+		t.Errorf("*/* without JSON content type = %s", rec.Header().Get(echo.HeaderContentType))
+	}
+	if rec := serve(e, http.MethodGet, "/api/v1/employees", "text/html", "", nil); rec.Code != http.StatusNotAcceptable {
+		t.Errorf("text/html = %d, want 406", rec.Code)
+	}
+}
+
+func TestBinder_Bind(t *testing.T) {
+	var created []*model.Employee
+	e := newNegotiationServer(&created)
+
+	xmlBody := `<employee><idEmployee>3</idEmployee><firstName>Mary</firstName><salary> 900.25 </salary></employee>`
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "application/xml", []byte(xmlBody)); rec.Code != 200 {
+		t.Fatalf("xml post = %d %s", rec.Code, rec.Body)
+	}
+	packed, _ := msgpack.Marshal(map[string]any{"idEmployee": "4", "firstName": "Max", "salary": 1000})
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "application/msgpack", packed); rec.Code != 200 {
+		t.Fatalf("msgpack post = %d %s", rec.Code, rec.Body)
+	}
+	if len(created) != 2 || created[0].FirstName != "Mary" || created[0].Salary.String() != "900.25" ||
+		created[1].IdEmployee != "4" || created[1].Salary.String() != "1000" {
+		t.Errorf("bound %+v %+v", created[0], created[1])
+	}
+
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "text/plain", []byte("3")); rec.Code != 400 {
+		t.Errorf("text/plain post = %d", rec.Code)
+	}
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "text/csv", "application/json", []byte(`{"idEmployee":"5"}`)); rec.Code != http.StatusNotAcceptable || len(created) != 2 {
+		t.Errorf("post accepting only csv = %d, handled %d", rec.Code, len(created))
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/util"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAPIValidation checks API traffic against the OpenAPI document: a
+// request that violates it is answered with 400 before it is handled, and a
+// response that violates it is replaced with 500. Parameters are always
+// checked, bodies only when they are JSON. Responses are held in memory
+// until checked, so this is meant for tests and staging rather than
+// production.
+type OpenAPIValidation struct {
+	Spec *openapi.Spec
+}
+
+func init() {
+	// v2 answers are JSON under their own media type
+	openapi3filter.RegisterBodyDecoder(util.MediaTypeV2, openapi3filter.RegisteredBodyDecoder(echo.MIMEApplicationJSON))
+}
+
+func NewOpenAPIValidation(spec *openapi.Spec) echo.MiddlewareFunc {
+	return (&OpenAPIValidation{Spec: spec}).Middleware
+}
+
+func (m *OpenAPIValidation) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		path := c.Path()
+		if util.APIVersion(c) == 2 {
+			// v1 paths answer in v2 when the client asks for its media type
+			path = strings.Replace(path, "/api/v1/", "/api/v2/", 1)
+		}
+		route, err := m.Spec.Route(c.Request().Method, path)
+		if err != nil {
+			return specError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "OpenAPI document unavailable", err)
+		}
+		if route == nil {
+			return next(c)
+		}
+		pathParams := map[string]string{}
+		for i, name := range c.ParamNames() {
+			pathParams[name] = c.ParamValues()[i]
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request(),
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				ExcludeRequestBody:  !isJSON(c.Request().Header.Get(echo.HeaderContentType)),
+				SkipSettingDefaults: true,
+				AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		ctx := c.Request().Context()
+		if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+			return specError(c, http.StatusBadRequest, "BAD_REQUEST", "Request does not match the API spec: "+err.Error(), err)
+		}
+
+		res := c.Response()
+		writer := res.Writer
+		buffer := &bufferedWriter{ResponseWriter: writer}
+		res.Writer = buffer
+		err = next(c)
+		res.Writer = writer
+		if err != nil || buffer.body.Len() == 0 || !isJSON(res.Header().Get(echo.HeaderContentType)) {
+			buffer.flush()
+			return err
+		}
+
+		errValidate := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 res.Status,
+			Header:                 res.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(buffer.body.Bytes())),
+			Options:                &openapi3filter.Options{},
+		})
+		if errValidate == nil {
+			buffer.flush()
+			return nil
+		}
+		logrus.Errorf("Response to %s %s does not match the API spec: %v", c.Request().Method, c.Path(), errValidate)
+		res.Committed, res.Status, res.Size = false, http.StatusOK, 0
+		res.Header().Del(echo.HeaderContentType)
+		res.Header().Del(echo.HeaderContentLength)
+		return specError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Response does not match the API spec", errValidate)
+	}
+}
+
+func specError(c echo.Context, code int, status, message string, err error) error {
+	response := model.GenericResponse[any]{
+		Code:   code,
+		Status: status,
+		Data:   message,
+	}
+	return util.RespJSONData(c, code, response, err)
+}
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(contentType, echo.MIMEApplicationJSON) || strings.HasPrefix(contentType, util.MediaTypeV2)
+}
+
+// bufferedWriter holds the status and body until flushed.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+		logrus.Errorf("Error writing response %v", err)
+	}
+}
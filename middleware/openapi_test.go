@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"employee-golang/model"
+	"employee-golang/openapi"
+	"employee-golang/util"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIValidation_Middleware(t *testing.T) {
+	e := echo.New()
+	e.Binder = &util.Binder{}
+	e.Pre((&Versioning{}).Middleware)
+	operations := openapi.NewOperations()
+	e.Use(NewOpenAPIValidation(openapi.NewSpec(e, operations)))
+
+	for _, version := range []string{"/api/v1", "/api/v2"} {
+		e.GET(version+"/employees/:id", func(c echo.Context) error {
+			var data any = &model.Employee{IdEmployee: c.Param("id"), FirstName: "Jane"}
+			if c.Param("id") == "broken" {
+				data = []string{"not", "an", "employee"}
+			}
+			return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "Success", Data: data}, nil)
+		})
+		operations.Add("GET", version+"/employees/:id", openapi.Operation{
+			Query:    []openapi.Param{{Name: "limit", Type: "integer"}},
+			Response: &model.Employee{},
+		})
+	}
+	handled := 0
+	e.POST("/api/v1/employees", func(c echo.Context) error {
+		handled++
+		return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "Success", Data: "Employee was added"}, nil)
+	})
+	operations.Add("POST", "/api/v1/employees", openapi.Operation{Body: &model.Employee{}, Response: ""})
+
+	if rec := serve(e, http.MethodGet, "/api/v1/employees/1", "", "", nil); rec.Code != 200 || !strings.Contains(rec.Body.String(), `"firstName":"Jane"`) {
+		t.Errorf("valid response = %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodGet, "/api/v1/employees/1?limit=many", "", "", nil); rec.Code != 400 || !strings.Contains(rec.Body.String(), "does not match the API spec") {
+		t.Errorf("invalid query = %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(e, http.MethodGet, "/api/v1/employees/broken", "", "", nil); rec.Code != 500 || rec.Body.String() != `{"code":500,"status":"INTERNAL_ERROR","data":"Response does not match the API spec"}`+"\n" {
+		t.Errorf("invalid response = %d %s", rec.Code, rec.Body)
+	}
+
+	// v2 answers are checked under their own media type
+	if rec := serve(e, http.MethodGet, "/api/v2/employees/1", "", "", nil); rec.Code != 200 || rec.Header().Get(echo.HeaderContentType) != util.MediaTypeV2+"; charset=UTF-8" {
+		t.Errorf("valid v2 response = %d %s %s", rec.Code, rec.Header().Get(echo.HeaderContentType), rec.Body)
+	}
+	if rec := serve(e, http.MethodGet, "/api/v2/employees/broken", "", "", nil); rec.Code != 500 {
+		t.Errorf("invalid v2 response = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "application/json", []byte(`{"idEmployee":"1","salary":"lots"}`)); rec.Code != 400 || handled != 0 {
+		t.Errorf("invalid body = %d, handled %d", rec.Code, handled)
+	}
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "application/json", []byte(`{"idEmployee":"1","salary":100}`)); rec.Code != 200 || handled != 1 {
+		t.Errorf("valid body = %d %s, handled %d", rec.Code, rec.Body, handled)
+	}
+	// only JSON bodies are checked
+	if rec := serve(e, http.MethodPost, "/api/v1/employees", "", "application/xml", []byte(`<employee><idEmployee>1</idEmployee></employee>`)); rec.Code != 200 || handled != 2 {
+		t.Errorf("xml body = %d %s, handled %d", rec.Code, rec.Body, handled)
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/util"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter keeps one token bucket per client and route. Each bucket holds
+// up to Limit tokens and refills at Limit/Period per second. At most
+// MaxBuckets are kept: once full, requests that would need a new bucket are
+// rejected until idle buckets are swept.
+type RateLimiter struct {
+	Default    config.RateLimit
+	Routes     map[string]config.RateLimit
+	MaxBuckets int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewRateLimiter(def config.RateLimit, routes map[string]config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		Default:    def,
+		Routes:     routes,
+		MaxBuckets: 100000,
+		now:        time.Now,
+		buckets:    map[string]*bucket{},
+	}
+}
+
+// RateLimit builds the limiter middleware from configuration.
+func RateLimit() echo.MiddlewareFunc {
+	limiter := NewRateLimiter(config.GetDefaultRateLimit(), config.GetRouteRateLimits())
+	limiter.MaxBuckets = config.GetRateLimitMaxBuckets()
+	return limiter.Middleware
+}
+
+func (l *RateLimiter) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// v2 routes share the limits and buckets of their v1 counterparts.
+		route := c.Request().Method + " " + strings.Replace(c.Path(), "/api/v2/", "/api/v1/", 1)
+		limit, ok := l.Routes[route]
+		if !ok {
+			limit = l.Default
+		}
+		allowed, remaining, reset := l.take(route+"|"+util.ClientId(c), limit)
+
+		header := c.Response().Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(limit.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+		if !allowed {
+			header.Set("Retry-After", strconv.Itoa(seconds(reset)))
+			response := model.GenericResponse[any]{
+				Code:   http.StatusTooManyRequests,
+				Status: "TOO_MANY_REQUESTS",
+				Data:   "Rate limit exceeded",
+			}
+			return util.RespJSONData(c, http.StatusTooManyRequests, response, errors.New("rate limit exceeded"))
+		}
+		return next(c)
+	}
+}
+
+// take consumes a token from the bucket for key. reset is how long until the
+// next token is available when the request is rejected, or until the bucket
+// is full again when it is allowed.
+func (l *RateLimiter) take(key string, limit config.RateLimit) (allowed bool, remaining int, reset time.Duration) {
+	now := l.now()
+	rate := float64(limit.Limit) / limit.Period.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now, time.Minute)
+
+	b, ok := l.buckets[key]
+	if !ok && len(l.buckets) >= l.MaxBuckets {
+		if l.sweep(now, time.Second); len(l.buckets) >= l.MaxBuckets {
+			return false, 0, time.Second
+		}
+	}
+	if !ok {
+		b = &bucket{tokens: float64(limit.Limit), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Limit), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, 0, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, int(b.tokens), time.Duration((float64(limit.Limit) - b.tokens) / rate * float64(time.Second))
+}
+
+// sweep drops buckets idle for longer than the longest period, since they
+// have refilled completely and carry no state. It runs at most once every
+// interval.
+func (l *RateLimiter) sweep(now time.Time, interval time.Duration) {
+	if now.Sub(l.swept) < interval {
+		return
+	}
+	l.swept = now
+	idle := l.Default.Period
+	for _, limit := range l.Routes {
+		if limit.Period > idle {
+			idle = limit.Period
+		}
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > idle {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"employee-golang/config"
+	"employee-golang/util"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const HeaderRequestId = "X-Request-Id"
+
+// Versioning selects the API version of requests under /api/: v2 for
+// /api/v2/ paths, and for /api/v1/ paths that prefer the v2 media type.
+// Answers in v1 carry Deprecation, Sunset and successor Link headers. Every
+// API request gets a request id, taken from X-Request-Id when the client
+// sends one.
+type Versioning struct {
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+// APIVersioning builds the versioning middleware from configuration. It
+// must run before routing, with echo's Pre.
+func APIVersioning() echo.MiddlewareFunc {
+	return (&Versioning{DeprecatedAt: config.GetV1DeprecatedAt(), Sunset: config.GetV1Sunset()}).Middleware
+}
+
+func (m *Versioning) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		path := c.Request().URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			return next(c)
+		}
+		header := c.Response().Header()
+		requestId := c.Request().Header.Get(HeaderRequestId)
+		if requestId == "" || len(requestId) > 200 {
+			requestId = newRequestId()
+		}
+		util.SetRequestId(c, requestId)
+		header.Set(HeaderRequestId, requestId)
+
+		switch {
+		case strings.HasPrefix(path, "/api/v2/"):
+			header.Add(echo.HeaderVary, echo.HeaderAccept)
+			util.SetAPIVersion(c, 2)
+		case strings.HasPrefix(path, "/api/v1/"):
+			header.Add(echo.HeaderVary, echo.HeaderAccept)
+			if util.AcceptsV2(c.Request().Header.Get(echo.HeaderAccept)) {
+				util.SetAPIVersion(c, 2)
+				break
+			}
+			util.SetAPIVersion(c, 1)
+			if m.DeprecatedAt.IsZero() {
+				header.Set("Deprecation", "true")
+			} else {
+				header.Set("Deprecation", "@"+strconv.FormatInt(m.DeprecatedAt.Unix(), 10))
+			}
+			if !m.Sunset.IsZero() {
+				header.Set("Sunset", m.Sunset.UTC().Format(http.TimeFormat))
+			}
+			header.Set("Link", `<`+"/api/v2/"+strings.TrimPrefix(path, "/api/v1/")+`>; rel="successor-version"`)
+		}
+		return next(c)
+	}
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
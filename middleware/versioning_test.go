@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"employee-golang/model"
+	"employee-golang/util"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersioning_Middleware(t *testing.T) {
+	versioning := &Versioning{
+		DeprecatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:       time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	e := echo.New()
+	e.Pre(versioning.Middleware)
+	for _, version := range []string{"/api/v1", "/api/v2"} {
+		e.GET(version+"/employees", func(c echo.Context) error {
+			util.SetPagination(c, &model.Pagination{Limit: 1, NextCursor: "1", HasMore: true})
+			return util.RespJSONData(c, 200, model.GenericResponse[any]{Code: 200, Status: "OK", Data: []string{"1"}}, nil)
+		})
+		e.GET(version+"/employees/:id", func(c echo.Context) error {
+			return util.RespJSONData(c, 404, model.GenericResponse[any]{Code: 404, Status: "NOT_FOUND", Data: "Employee not found"}, nil)
+		})
+	}
+
+	call := func(path, accept, requestId string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		if requestId != "" {
+			req.Header.Set(HeaderRequestId, requestId)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	v1 := call("/api/v1/employees", "application/json", "req-1")
+	if got := v1.Body.String(); got != `{"code":200,"status":"OK","data":["1"]}`+"\n" {
+		t.Errorf("v1 body = %s", got)
+	}
+	if got := v1.Header().Get("Deprecation"); got != "@1767225600" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := v1.Header().Get("Sunset"); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := v1.Header().Get("Link"); got != `</api/v2/employees>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+	if got := v1.Header().Get(HeaderRequestId); got != "req-1" {
+		t.Errorf("X-Request-Id = %q, want the client's", got)
+	}
+	if got := v1.Header().Get(echo.HeaderContentType); got != echo.MIMEApplicationJSONCharsetUTF8 {
+		t.Errorf("v1 Content-Type = %q", got)
+	}
+
+	wantList := `{"data":["1"],"meta":{"requestId":"req-2","pagination":{"limit":1,"nextCursor":"1","hasMore":true}}}` + "\n"
+	for _, rec := range []*httptest.ResponseRecorder{
+		call("/api/v2/employees", "", "req-2"),
+		call("/api/v1/employees", util.MediaTypeV2, "req-2"),
+	} {
+		if got := rec.Body.String(); got != wantList {
+			t.Errorf("v2 body = %s, want %s", got, wantList)
+		}
+		if rec.Header().Get("Deprecation") != "" {
+			t.Error("v2 answers must not be deprecated")
+		}
+		if got := rec.Header().Get(echo.HeaderContentType); got != util.MediaTypeV2+"; charset=UTF-8" {
+			t.Errorf("v2 Content-Type = %q", got)
+		}
+	}
+	for _, rec := range []*httptest.ResponseRecorder{v1, call("/api/v2/employees", "", "")} {
+		if got := rec.Header().Values(echo.HeaderVary); len(got) != 1 || got[0] != echo.HeaderAccept {
+			t.Errorf("Vary = %q, want Accept once", got)
+		}
+	}
+
+	// the v2 media type is only chosen when it is preferred to plain JSON
+	for accept, want := range map[string]int{
+		util.MediaTypeV2 + ";q=0":                                  1,
+		"application/json, " + util.MediaTypeV2 + ";q=0.5":         1,
+		util.MediaTypeV2 + ", application/json;q=0.9":              2,
+		"application/xml, " + util.MediaTypeV2 + ";q=0.8":          1,
+		"application/vnd.employee.v20+json, application/json":      1,
+		"text/html;q=0.1, " + util.MediaTypeV2 + ";q=0.9, */*;q=0": 2,
+	} {
+		deprecated := call("/api/v1/employees", accept, "").Header().Get("Deprecation") != ""
+		if got := map[bool]int{true: 1, false: 2}[deprecated]; got != want {
+			t.Errorf("Accept %q answered in v%d, want v%d", accept, got, want)
+		}
+	}
+
+	wantError := `{"error":{"code":"NOT_FOUND","message":"Employee not found"},"meta":{"requestId":"req-3"}}` + "\n"
+	if got := call("/api/v2/employees/9", "", "req-3").Body.String(); got != wantError {
+		t.Errorf("v2 error = %s, want %s", got, wantError)
+	}
+	if got := call("/api/v2/employees/9", "", "").Header().Get(HeaderRequestId); len(got) != 32 {
+		t.Errorf("generated request id = %q", got)
+	}
+}
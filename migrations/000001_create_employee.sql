@@ -0,0 +1,10 @@
+create table if not exists employee
+(
+    employee_id varchar(64)  not null primary key,
+    first_name  varchar(100) not null,
+    last_name   varchar(100) not null,
+    email       varchar(255) not null unique,
+    phone       varchar(50)  not null,
+    hire_date   date         null,
+    salary      double       null
+);
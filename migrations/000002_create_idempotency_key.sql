@@ -0,0 +1,11 @@
+create table if not exists idempotency_key
+(
+    idempotency_key varchar(255) not null primary key,
+    request_hash    char(64)     not null,
+    status_code     int          null,
+    content_type    varchar(255) null,
+    response_body   mediumblob   null,
+    completed       boolean      not null default false,
+    expires_at      bigint       not null,
+    index idx_idempotency_key_expires_at (expires_at)
+);
@@ -0,0 +1,10 @@
+create table if not exists department
+(
+    department_id varchar(64)  not null primary key,
+    name          varchar(100) not null,
+    description   varchar(255) null
+);
+
+alter table employee
+    add column department_id varchar(64) null,
+    add constraint fk_employee_department foreign key (department_id) references department (department_id);
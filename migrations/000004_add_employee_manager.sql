@@ -0,0 +1,4 @@
+alter table employee
+    add column manager_id varchar(64) null,
+    add constraint fk_employee_manager foreign key (manager_id) references employee (employee_id),
+    add index idx_employee_manager_id (manager_id);
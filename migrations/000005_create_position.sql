@@ -0,0 +1,25 @@
+create table if not exists position
+(
+    position_id varchar(64)    not null primary key,
+    title       varchar(100)   not null,
+    grade       varchar(20)    not null,
+    min_salary  decimal(19, 4) not null,
+    max_salary  decimal(19, 4) not null
+);
+
+alter table employee
+    add column position_id varchar(64) null,
+    add constraint fk_employee_position foreign key (position_id) references position (position_id);
+
+create table if not exists salary_override
+(
+    id          bigint auto_increment primary key,
+    employee_id varchar(64)    not null,
+    position_id varchar(64)    not null,
+    salary      decimal(19, 4) not null,
+    min_salary  decimal(19, 4) not null,
+    max_salary  decimal(19, 4) not null,
+    reason      varchar(500)   not null,
+    created_at  timestamp      not null default current_timestamp,
+    index idx_salary_override_employee_id (employee_id)
+);
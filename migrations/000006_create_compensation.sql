@@ -0,0 +1,17 @@
+create table if not exists compensation
+(
+    id             bigint auto_increment primary key,
+    employee_id    varchar(64)    not null,
+    salary         decimal(19, 4) not null,
+    effective_date date           not null,
+    reason         varchar(255)   null,
+    created_at     timestamp      not null default current_timestamp,
+    index idx_compensation_employee_effective (employee_id, effective_date),
+    constraint fk_compensation_employee foreign key (employee_id) references employee (employee_id) on delete cascade
+);
+
+-- seed the history with the salary each employee has today
+insert into compensation (employee_id, salary, effective_date, reason)
+select employee_id, salary, coalesce(hire_date, current_date), 'initial salary'
+from employee
+where salary is not null;
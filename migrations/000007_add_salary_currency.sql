@@ -0,0 +1,17 @@
+-- salaries become exact decimal amounts with an ISO-4217 currency; rows
+-- written before this migration are taken to be in USD
+update employee set salary = 0 where salary is null;
+
+alter table employee
+    modify column salary decimal(19, 4) not null default 0,
+    add column currency char(3) not null default 'USD' after salary;
+
+alter table compensation
+    add column currency char(3) not null default 'USD' after salary;
+
+alter table position
+    add column currency char(3) not null default 'USD' after max_salary;
+
+alter table salary_override
+    add column currency      char(3) not null default 'USD' after salary,
+    add column band_currency char(3) not null default 'USD' after max_salary;
@@ -0,0 +1,23 @@
+-- every employee present before this migration is taken to be working
+alter table employee
+    add column status             varchar(20)  not null default 'active',
+    add column termination_date   date         null,
+    add column termination_reason varchar(500) null,
+    add index idx_employee_status (status);
+
+create table if not exists employment_status_history
+(
+    id             bigint auto_increment primary key,
+    employee_id    varchar(64)  not null,
+    from_status    varchar(20)  null,
+    to_status      varchar(20)  not null,
+    effective_date date         not null,
+    reason         varchar(500) null,
+    created_at     timestamp    not null default current_timestamp,
+    index idx_status_history_employee (employee_id),
+    constraint fk_status_history_employee foreign key (employee_id) references employee (employee_id) on delete cascade
+);
+
+insert into employment_status_history (employee_id, to_status, effective_date, reason)
+select employee_id, status, coalesce(hire_date, current_date), 'hired'
+from employee;
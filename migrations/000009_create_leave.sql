@@ -0,0 +1,28 @@
+create table if not exists leave_type
+(
+    leave_type_id   varchar(64)   not null primary key,
+    name            varchar(100)  not null,
+    annual_days     decimal(5, 1) not null,
+    accrual         varchar(20)   not null default 'annual',
+    carry_over_days decimal(5, 1) not null default 0
+);
+
+create table if not exists leave_request
+(
+    id            bigint auto_increment primary key,
+    employee_id   varchar(64)   not null,
+    leave_type_id varchar(64)   not null,
+    start_date    date          not null,
+    end_date      date          not null,
+    days          decimal(5, 1) not null,
+    reason        varchar(500)  null,
+    status        varchar(20)   not null default 'pending',
+    approver_id   varchar(64)   null,
+    decision_note varchar(500)  null,
+    decided_at    timestamp     null,
+    created_at    timestamp     not null default current_timestamp,
+    index idx_leave_request_employee_dates (employee_id, start_date, end_date),
+    constraint fk_leave_request_employee foreign key (employee_id) references employee (employee_id) on delete cascade,
+    constraint fk_leave_request_type foreign key (leave_type_id) references leave_type (leave_type_id),
+    constraint fk_leave_request_approver foreign key (approver_id) references employee (employee_id) on delete set null
+);
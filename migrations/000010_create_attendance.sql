@@ -0,0 +1,12 @@
+create table if not exists attendance_event
+(
+    id          bigint auto_increment primary key,
+    employee_id varchar(64)  not null,
+    kind        varchar(3)   not null,
+    occurred_at datetime     not null,
+    note        varchar(255) null,
+    created_at  timestamp    not null default current_timestamp,
+    index idx_attendance_employee_time (employee_id, occurred_at),
+    index idx_attendance_time (occurred_at),
+    constraint fk_attendance_employee foreign key (employee_id) references employee (employee_id) on delete cascade
+);
@@ -0,0 +1,13 @@
+create table if not exists employee_document
+(
+    id           bigint auto_increment primary key,
+    employee_id  varchar(64)  not null,
+    file_name    varchar(255) not null,
+    content_type varchar(100) not null,
+    size         bigint       not null,
+    checksum     char(64)     not null,
+    storage_key  varchar(255) not null unique,
+    created_at   timestamp    not null default current_timestamp,
+    index idx_employee_document_employee (employee_id),
+    constraint fk_employee_document_employee foreign key (employee_id) references employee (employee_id) on delete cascade
+);
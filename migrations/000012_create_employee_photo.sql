@@ -0,0 +1,13 @@
+-- content lives in storage under photos/<employee_id>/<checksum>/, one
+-- object for the original and one per thumbnail size
+create table if not exists employee_photo
+(
+    employee_id  varchar(64) not null primary key,
+    content_type varchar(20) not null,
+    width        int         not null,
+    height       int         not null,
+    size         bigint      not null,
+    checksum     char(64)    not null,
+    updated_at   timestamp   not null default current_timestamp on update current_timestamp,
+    constraint fk_employee_photo_employee foreign key (employee_id) references employee (employee_id) on delete cascade
+);
@@ -0,0 +1,4 @@
+-- the ngram parser indexes two-character tokens, so natural language
+-- searches still rank names with a typo or a missing ending
+alter table employee
+    add fulltext index ft_employee_search (first_name, last_name, email, phone) with parser ngram;
@@ -0,0 +1,16 @@
+-- events are written in the transaction of the change they describe and
+-- published in id order by the relay; published rows are purged after the
+-- configured retention
+create table if not exists outbox_event
+(
+    id           bigint auto_increment primary key,
+    event_id     char(36)      not null unique,
+    event_type   varchar(64)   not null,
+    aggregate_id varchar(64)   not null,
+    payload      json          not null,
+    occurred_at  datetime(6)   not null,
+    published_at datetime(6)   null,
+    attempts     int           not null default 0,
+    last_error   varchar(1000) null,
+    index idx_outbox_event_pending (published_at, id)
+);
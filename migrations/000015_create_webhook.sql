@@ -0,0 +1,48 @@
+-- partner webhook subscriptions; event_types is a comma-separated list and
+-- empty selects every event type
+create table if not exists webhook_subscription
+(
+    id          bigint auto_increment primary key,
+    url         varchar(2048) not null,
+    secret      varchar(100)  not null,
+    event_types varchar(255)  not null default '',
+    description varchar(255)  not null default '',
+    active      boolean       not null default true,
+    created_at  datetime      not null default current_timestamp,
+    updated_at  datetime      not null default current_timestamp on update current_timestamp
+);
+
+-- one row per event and subscription, enqueued by the event relay and sent
+-- by the webhook dispatcher; the unique key drops events the relay
+-- publishes twice
+create table if not exists webhook_delivery
+(
+    id              bigint auto_increment primary key,
+    subscription_id bigint        not null,
+    event_id        char(36)      not null,
+    event_type      varchar(64)   not null,
+    payload         json          not null,
+    status          varchar(16)   not null default 'pending',
+    attempts        int           not null default 0,
+    next_attempt_at datetime(6)   not null,
+    last_attempt_at datetime(6)   null,
+    response_status int           null,
+    last_error      varchar(1000) null,
+    created_at      datetime(6)   not null default current_timestamp(6),
+    unique key uk_webhook_delivery_event (subscription_id, event_id),
+    index idx_webhook_delivery_due (status, next_attempt_at),
+    constraint fk_webhook_delivery_subscription foreign key (subscription_id)
+        references webhook_subscription (id) on delete cascade
+);
+
+create table if not exists webhook_delivery_attempt
+(
+    id              bigint auto_increment primary key,
+    delivery_id     bigint        not null,
+    attempted_at    datetime(6)   not null,
+    response_status int           null,
+    error           varchar(1000) null,
+    duration_ms     int           not null,
+    constraint fk_webhook_attempt_delivery foreign key (delivery_id)
+        references webhook_delivery (id) on delete cascade
+);
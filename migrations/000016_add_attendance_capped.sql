@@ -0,0 +1,3 @@
+-- marks clock-outs moved back to the end of the longest allowed shift
+alter table attendance_event
+    add column capped boolean not null default false after note;
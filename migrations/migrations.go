@@ -0,0 +1,83 @@
+// Package migrations embeds the schema migrations. Files are named
+// <version>_<name>.sql and applied once each, in version order.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+	// Checksum is the hex SHA-256 of the statements, so changes to comments
+	// and blank lines leave it alone.
+	Checksum string
+}
+
+// Load returns the embedded migrations ordered by version.
+func Load() ([]Migration, error) {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		version, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		n, err := strconv.Atoi(version)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.sql", entry.Name())
+		}
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		statements := splitStatements(string(content))
+		res = append(res, Migration{Version: n, Name: name, Statements: statements, Checksum: checksum(statements)})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	for i := 1; i < len(res); i++ {
+		if res[i].Version == res[i-1].Version {
+			return nil, fmt.Errorf("migration version %d is used twice", res[i].Version)
+		}
+	}
+	return res, nil
+}
+
+func checksum(statements []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(statements, ";\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitStatements drops comment lines and splits on semicolons ending a
+// line, which is all the migrations need; the driver runs one statement
+// per call.
+func splitStatements(content string) []string {
+	var res []string
+	var statement strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			res = append(res, strings.TrimSuffix(strings.TrimSpace(statement.String()), ";"))
+			statement.Reset()
+		}
+	}
+	if s := strings.TrimSpace(statement.String()); s != "" {
+		res = append(res, s)
+	}
+	return res
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	rs, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) < 15 || rs[0].Version != 1 || rs[0].Name != "create_employee" {
+		t.Fatalf("unexpected migrations %v", rs[:1])
+	}
+	for i, m := range rs {
+		if m.Version != i+1 {
+			t.Errorf("migration %d has version %d", i+1, m.Version)
+		}
+		if len(m.Statements) == 0 {
+			t.Errorf("migration %d has no statements", m.Version)
+		}
+		if len(m.Checksum) != 64 {
+			t.Errorf("migration %d has checksum %q", m.Version, m.Checksum)
+		}
+	}
+}
+
+func Test_splitStatements(t *testing.T) {
+	got := splitStatements("-- a comment; not a statement\nalter table employee\n    add column status varchar(20);\n\ninsert into t\nselect 1;\nselect 2")
+	if len(got) != 3 {
+		t.Fatalf("got %d statements: %q", len(got), got)
+	}
+	if got[0] != "alter table employee\n    add column status varchar(20)" || !strings.HasPrefix(got[1], "insert into t") || got[2] != "select 2" {
+		t.Errorf("unexpected statements %q", got)
+	}
+}
+
+func Test_checksum(t *testing.T) {
+	original := checksum(splitStatements("create table t (id int);\n"))
+	if got := checksum(splitStatements("-- the first table\ncreate table t (id int);\n\n")); got != original {
+		t.Errorf("comments and blank lines changed the checksum")
+	}
+	if got := checksum(splitStatements("create table t (id bigint);\n")); got == original {
+		t.Errorf("a changed statement kept the checksum")
+	}
+}
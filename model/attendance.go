@@ -0,0 +1,55 @@
+package model
+
+const (
+	ClockIn  = "in"
+	ClockOut = "out"
+)
+
+// AttendanceEvent is one clock-in or clock-out. OccurredAt is RFC 3339.
+type AttendanceEvent struct {
+	IdEvent    int64  `json:"idEvent,omitempty" db:"id"`
+	IdEmployee string `json:"idEmployee" db:"employee_id"`
+	Kind       string `json:"kind" db:"kind"`
+	OccurredAt string `json:"occurredAt" db:"occurred_at"`
+	Note       string `json:"note,omitempty" db:"note"`
+	// Capped marks a clock-out given later than the longest shift allows
+	// after its clock-in. It is recorded at the end of that shift instead,
+	// and the session should be reviewed.
+	Capped bool `json:"capped,omitempty" db:"capped"`
+}
+
+// Clock records a clock-in or clock-out at Time, now when omitted.
+type Clock struct {
+	Time string `json:"time,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Note string `json:"note,omitempty"`
+}
+
+// Timesheet is one employee's week. Open is set when the employee is still
+// clocked in; the running session is not counted until they clock out.
+type Timesheet struct {
+	IdEmployee    string          `json:"idEmployee"`
+	WeekStart     string          `json:"weekStart"`
+	WeekEnd       string          `json:"weekEnd"`
+	Hours         Quantity        `json:"hours"`
+	RegularHours  Quantity        `json:"regularHours"`
+	OvertimeHours Quantity        `json:"overtimeHours"`
+	Open          bool            `json:"open"`
+	Days          []*TimesheetDay `json:"days"`
+}
+
+type TimesheetDay struct {
+	Date          string   `json:"date"`
+	Hours         Quantity `json:"hours"`
+	RegularHours  Quantity `json:"regularHours"`
+	OvertimeHours Quantity `json:"overtimeHours"`
+}
+
+// HoursReportRow is the hours one employee worked in one period.
+type HoursReportRow struct {
+	IdEmployee    string   `json:"idEmployee"`
+	PeriodStart   string   `json:"periodStart"`
+	PeriodEnd     string   `json:"periodEnd"`
+	Hours         Quantity `json:"hours"`
+	RegularHours  Quantity `json:"regularHours"`
+	OvertimeHours Quantity `json:"overtimeHours"`
+}
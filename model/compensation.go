@@ -0,0 +1,16 @@
+package model
+
+// Compensation is one entry of an employee's salary history. The entry with
+// the latest EffectiveDate not after a given day is the salary on that day.
+type Compensation struct {
+	IdCompensation int64  `json:"idCompensation,omitempty" db:"id"`
+	IdEmployee     string `json:"idEmployee,omitempty" db:"employee_id"`
+	Salary         Money  `json:"salary" db:"salary" validate:"decimal_gte=0"`
+	Currency       string `json:"currency,omitempty" db:"currency" validate:"omitempty,iso4217"`
+	EffectiveDate  string `json:"effectiveDate" db:"effective_date" validate:"required,datetime=2006-01-02"`
+	Reason         string `json:"reason,omitempty" db:"reason"`
+	CreatedAt      string `json:"createdAt,omitempty" db:"created_at"`
+	// SalaryOverrideReason justifies a salary outside the band of the
+	// employee's position, as on Employee.
+	SalaryOverrideReason string `json:"salaryOverrideReason,omitempty" db:"-"`
+}
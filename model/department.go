@@ -0,0 +1,7 @@
+package model
+
+type Department struct {
+	IdDepartment string `json:"idDepartment,omitempty" db:"department_id" validate:"required"`
+	Name         string `json:"name,omitempty" db:"name" validate:"required"`
+	Description  string `json:"description" db:"description"`
+}
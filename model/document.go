@@ -0,0 +1,15 @@
+package model
+
+// Document is a file kept for an employee, such as a contract or an ID
+// scan. ContentType is sniffed from the content rather than taken from the
+// upload, and Checksum is the hex SHA-256 of the content.
+type Document struct {
+	IdDocument  int64  `json:"idDocument" db:"id"`
+	IdEmployee  string `json:"idEmployee" db:"employee_id"`
+	FileName    string `json:"fileName" db:"file_name"`
+	ContentType string `json:"contentType" db:"content_type"`
+	Size        int64  `json:"size" db:"size"`
+	Checksum    string `json:"checksum" db:"checksum"`
+	StorageKey  string `json:"-" db:"storage_key"`
+	CreatedAt   string `json:"createdAt,omitempty" db:"created_at"`
+}
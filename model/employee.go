@@ -1,11 +1,33 @@
 package model
 
 type Employee struct {
-	IdEmployee string  `json:"idEmployee,omitempty" db:"id" validate:"required"`
-	FirstName  string  `json:"firstName,omitempty" db:"first_name" validate:"required"`
-	LastName   string  `json:"lastName,omitempty" db:"last_name" validate:"required"`
-	Email      string  `json:"email,omitempty" db:"email" validate:"required"`
-	Phone      string  `json:"phone,omitempty" db:"phone" validate:"required"`
-	HireDate   string  `json:"hireDate" db:"hire_date"`
-	Salary     float64 `json:"salary" db:"salary"`
+	IdEmployee   string `json:"idEmployee,omitempty" db:"id" validate:"required"`
+	FirstName    string `json:"firstName,omitempty" db:"first_name" validate:"required"`
+	LastName     string `json:"lastName,omitempty" db:"last_name" validate:"required"`
+	Email        string `json:"email,omitempty" db:"email" validate:"required"`
+	Phone        string `json:"phone,omitempty" db:"phone" validate:"required"`
+	HireDate     string `json:"hireDate" db:"hire_date"`
+	Salary       Money  `json:"salary" db:"salary" validate:"decimal_gte=0"`
+	Currency     string `json:"currency,omitempty" db:"currency" validate:"omitempty,iso4217"`
+	DepartmentId string `json:"departmentId,omitempty" db:"department_id"`
+	ManagerId    string `json:"managerId,omitempty" db:"manager_id"`
+	PositionId   string `json:"positionId,omitempty" db:"position_id"`
+	// Status moves only through the lifecycle transitions; updates that
+	// would change it directly are rejected.
+	Status            string `json:"status,omitempty" db:"status" validate:"omitempty,oneof=onboarding active on_leave terminated"`
+	TerminationDate   string `json:"terminationDate,omitempty" db:"termination_date"`
+	TerminationReason string `json:"terminationReason,omitempty" db:"termination_reason"`
+	// PhotoUrl points at the current profile photo; it is derived from the
+	// stored photo and ignored on requests.
+	PhotoUrl string `json:"photoUrl,omitempty" db:"-"`
+	// SalaryOverrideReason justifies a salary outside the position's band.
+	// It is only read from requests and stored as a SalaryOverride.
+	SalaryOverrideReason string `json:"salaryOverrideReason,omitempty" db:"-"`
+	// SalaryOverride is the override accepted for the salary; it is stored
+	// in the same transaction as the employee.
+	SalaryOverride *SalaryOverride `json:"-" db:"-"`
+	// Compensation is the history entry for the salary, which reads take
+	// the current salary from; it is stored in the same transaction as the
+	// employee when the salary or currency changes.
+	Compensation *Compensation `json:"-" db:"-"`
 }
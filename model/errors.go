@@ -0,0 +1,55 @@
+package model
+
+import "errors"
+
+var (
+	ErrDepartmentNotFound = errors.New("department doesn't exists")
+	ErrDepartmentExists   = errors.New("department already exists")
+	ErrDepartmentInUse    = errors.New("department still has employees")
+	ErrManagerNotFound    = errors.New("manager doesn't exists")
+	ErrManagerCycle       = errors.New("manager would create a reporting cycle")
+	ErrHierarchyTooDeep   = errors.New("manager's reporting chain is too deep")
+	ErrEmployeeHasReports = errors.New("employee still manages other employees")
+	ErrPositionNotFound   = errors.New("position doesn't exists")
+	ErrPositionExists     = errors.New("position already exists")
+	ErrPositionInUse      = errors.New("position is still held by employees")
+	ErrSalaryOutOfBand    = errors.New("salary is outside the position's band")
+	ErrEffectiveDatePast  = errors.New("effective date must not be in the past")
+	ErrStatusFutureDate   = errors.New("status changes take effect when made, the effective date must not be in the future")
+	ErrStatusTransition   = errors.New("employment status transition is not allowed")
+	ErrStatusNotEditable  = errors.New("employment status can only change through a status transition")
+	ErrTerminationReason  = errors.New("termination requires a reason")
+	ErrNotTerminated      = errors.New("only terminated employees can be rehired")
+	ErrUnknownStatus      = errors.New("unknown employment status")
+	ErrLeaveTypeNotFound  = errors.New("leave type doesn't exists")
+	ErrLeaveTypeExists    = errors.New("leave type already exists")
+	ErrLeaveTypeInUse     = errors.New("leave type has leave requests")
+	ErrLeaveDates         = errors.New("leave must end on or after its start, within one year, and include a working day")
+	ErrLeaveOverlap       = errors.New("leave overlaps another pending or approved request")
+	ErrLeaveBalance       = errors.New("not enough leave balance")
+	ErrLeaveNotPending    = errors.New("leave request is no longer pending")
+	ErrLeaveNotApprover   = errors.New("approver is not a manager of the employee")
+	ErrLeaveInactive      = errors.New("terminated employees cannot request leave")
+	ErrAlreadyClockedIn   = errors.New("employee is already clocked in")
+	ErrNotClockedIn       = errors.New("employee is not clocked in")
+	ErrClockOrder         = errors.New("clock time must be after the previous clock event")
+	ErrClockFuture        = errors.New("clock time must not be in the future")
+	ErrClockInactive      = errors.New("terminated employees cannot clock in")
+	ErrReportPeriod       = errors.New("report period is invalid")
+	ErrDocumentTooLarge   = errors.New("document is too large")
+	ErrDocumentType       = errors.New("document type is not allowed")
+	ErrDocumentEmpty      = errors.New("document is empty")
+	ErrPhotoTooLarge      = errors.New("photo is too large")
+	ErrPhotoType          = errors.New("photo must be a JPEG or PNG image")
+	ErrPhotoDimensions    = errors.New("photo dimensions are out of range")
+	ErrPhotoSize          = errors.New("unknown photo size")
+	ErrSearchQuery        = errors.New("search query must contain a letter or digit")
+	ErrWebhookUrl         = errors.New("webhook url must be an absolute http(s) url")
+	ErrWebhookEventType   = errors.New("unknown webhook event type")
+	ErrWebhookInactive    = errors.New("webhook subscription is not active")
+	ErrWebhookAddress     = errors.New("webhook url resolves to a private address")
+	ErrDeliveryStatus     = errors.New("unknown delivery status")
+	ErrMigrationLocked    = errors.New("another migration run holds the lock")
+	ErrMigrationVersion   = errors.New("unknown migration version")
+	ErrMigrationChecksum  = errors.New("applied migration has changed since it ran")
+)
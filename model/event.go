@@ -0,0 +1,30 @@
+package model
+
+import "encoding/json"
+
+const (
+	EventEmployeeCreated = "EmployeeCreated"
+	EventEmployeeUpdated = "EmployeeUpdated"
+	EventEmployeeDeleted = "EmployeeDeleted"
+)
+
+// Event is a domain event as published to sinks. Sequence orders events
+// across the outbox; IdEvent is unique per event, so consumers can drop
+// the duplicates at-least-once delivery may produce.
+type Event struct {
+	Sequence    int64           `json:"sequence"`
+	IdEvent     string          `json:"idEvent"`
+	Type        string          `json:"type"`
+	AggregateId string          `json:"aggregateId"`
+	OccurredAt  string          `json:"occurredAt"`
+	Payload     json.RawMessage `json:"payload"`
+	// Attempts counts earlier publish attempts.
+	Attempts int `json:"-"`
+}
+
+// EmployeeChange is the payload of employee events. Before is nil for
+// EmployeeCreated and After is nil for EmployeeDeleted.
+type EmployeeChange struct {
+	Before *Employee `json:"before"`
+	After  *Employee `json:"after"`
+}
@@ -5,3 +5,31 @@ type GenericResponse[T any] struct {
 	Status string `json:"status,omitempty"`
 	Data   T      `json:"data,omitempty"`
 }
+
+// Envelope is the v2 response body: Data on success, Error otherwise, and
+// Meta on every response.
+type Envelope struct {
+	Data  any            `json:"data,omitempty"`
+	Error *EnvelopeError `json:"error,omitempty"`
+	Meta  EnvelopeMeta   `json:"meta"`
+}
+
+// EnvelopeError names the failure with the status the v1 envelope carries,
+// such as NOT_FOUND.
+type EnvelopeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type EnvelopeMeta struct {
+	RequestId  string      `json:"requestId"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes a page of a list; NextCursor continues it while
+// HasMore is set.
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
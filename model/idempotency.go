@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the first response produced for an Idempotency-Key.
+// Until Completed is set the original request is still being processed.
+type IdempotencyRecord struct {
+	Key         string    `db:"idempotency_key"`
+	RequestHash string    `db:"request_hash"`
+	StatusCode  int       `db:"status_code"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"response_body"`
+	Completed   bool      `db:"completed"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+const (
+	LeaveAccrualAnnual  = "annual"
+	LeaveAccrualMonthly = "monthly"
+
+	LeavePending   = "pending"
+	LeaveApproved  = "approved"
+	LeaveRejected  = "rejected"
+	LeaveCancelled = "cancelled"
+)
+
+// LeaveType is a kind of leave with its yearly allowance in working days.
+// An annual accrual grants the whole allowance on the first of January, a
+// monthly one a twelfth at the start of each month. Up to CarryOverDays left
+// unused at the end of a year move to the next.
+type LeaveType struct {
+	IdLeaveType   string   `json:"idLeaveType,omitempty" db:"leave_type_id" validate:"required"`
+	Name          string   `json:"name,omitempty" db:"name" validate:"required"`
+	AnnualDays    Quantity `json:"annualDays" db:"annual_days" validate:"decimal_gte=0"`
+	Accrual       string   `json:"accrual" db:"accrual" validate:"required,oneof=annual monthly"`
+	CarryOverDays Quantity `json:"carryOverDays" db:"carry_over_days" validate:"decimal_gte=0"`
+}
+
+// LeaveRequest asks for leave from StartDate to EndDate inclusive. Days is
+// the number of working days in that range and is computed by the service.
+type LeaveRequest struct {
+	IdLeaveRequest int64    `json:"idLeaveRequest,omitempty" db:"id"`
+	IdEmployee     string   `json:"idEmployee,omitempty" db:"employee_id"`
+	IdLeaveType    string   `json:"idLeaveType" db:"leave_type_id" validate:"required"`
+	StartDate      string   `json:"startDate" db:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate        string   `json:"endDate" db:"end_date" validate:"required,datetime=2006-01-02"`
+	Days           Quantity `json:"days" db:"days"`
+	Reason         string   `json:"reason,omitempty" db:"reason"`
+	Status         string   `json:"status,omitempty" db:"status"`
+	ApproverId     string   `json:"approverId,omitempty" db:"approver_id"`
+	DecisionNote   string   `json:"decisionNote,omitempty" db:"decision_note"`
+	DecidedAt      string   `json:"decidedAt,omitempty" db:"decided_at"`
+	CreatedAt      string   `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// LeaveDecision approves or rejects a pending request. ApproverId must be a
+// manager in the requesting employee's reporting line.
+type LeaveDecision struct {
+	ApproverId string `json:"approverId" validate:"required"`
+	Note       string `json:"note,omitempty"`
+}
+
+// LeaveBalance is an employee's standing for one leave type in Year.
+// Available is Entitled plus CarriedOver less Used and Pending.
+type LeaveBalance struct {
+	IdEmployee  string   `json:"idEmployee"`
+	IdLeaveType string   `json:"idLeaveType"`
+	Year        int      `json:"year"`
+	Entitled    Quantity `json:"entitled"`
+	CarriedOver Quantity `json:"carriedOver"`
+	Used        Quantity `json:"used"`
+	Pending     Quantity `json:"pending"`
+	Available   Quantity `json:"available"`
+}
+
+// LeaveUsage sums the days of one year's requests in one status.
+type LeaveUsage struct {
+	Year   int
+	Status string
+	Days   decimal.Decimal
+}
@@ -0,0 +1,28 @@
+package model
+
+const (
+	StatusOnboarding = "onboarding"
+	StatusActive     = "active"
+	StatusOnLeave    = "on_leave"
+	StatusTerminated = "terminated"
+)
+
+// StatusChange moves an employee to Status from EffectiveDate. It is also
+// one entry of the employee's status history, where FromStatus is the status
+// left behind and is empty for the status the employee was created with.
+type StatusChange struct {
+	IdEmployee    string `json:"idEmployee,omitempty" db:"employee_id"`
+	FromStatus    string `json:"fromStatus,omitempty" db:"from_status"`
+	Status        string `json:"status" db:"to_status" validate:"required,oneof=onboarding active on_leave terminated"`
+	EffectiveDate string `json:"effectiveDate,omitempty" db:"effective_date" validate:"omitempty,datetime=2006-01-02"`
+	Reason        string `json:"reason,omitempty" db:"reason"`
+	CreatedAt     string `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// Rehire brings a terminated employee back under their original id.
+type Rehire struct {
+	// Status is onboarding unless the employee starts straight away as active.
+	Status   string `json:"status,omitempty" validate:"omitempty,oneof=onboarding active"`
+	HireDate string `json:"hireDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Reason   string `json:"reason,omitempty"`
+}
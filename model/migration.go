@@ -0,0 +1,11 @@
+package model
+
+// MigrationStatus is a schema migration and when it was applied, empty
+// while it is pending.
+type MigrationStatus struct {
+	Version   int    `json:"version" db:"version"`
+	Name      string `json:"name" db:"name"`
+	AppliedAt string `json:"appliedAt,omitempty" db:"applied_at"`
+	// Checksum is the SHA-256 of the statements the migration ran with.
+	Checksum string `json:"-" db:"checksum"`
+}
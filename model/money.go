@@ -0,0 +1,52 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+// Money is an exact amount written to JSON as a number holding its decimal
+// digits, so clients reading salary as a number keep working. It reads
+// both numbers and quoted strings.
+type Money struct {
+	decimal.Decimal
+}
+
+// NewMoney wraps d as Money.
+func NewMoney(d decimal.Decimal) Money {
+	return Money{Decimal: d}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Quantity is an exact count of days or hours, written to JSON the same
+// way as Money.
+type Quantity struct {
+	decimal.Decimal
+}
+
+// NewQuantity wraps d as a Quantity.
+func NewQuantity(d decimal.Decimal) Quantity {
+	return Quantity{Decimal: d}
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// SalaryReport lists salaries converted to a single currency.
+type SalaryReport struct {
+	Currency     string             `json:"currency"`
+	BaseCurrency string             `json:"baseCurrency"`
+	Total        Money              `json:"total"`
+	Employees    []*SalaryReportRow `json:"employees"`
+	// Unconverted holds the ids of employees paid in a currency missing
+	// from the exchange-rate table; they are left out of Total.
+	Unconverted []string `json:"unconverted"`
+}
+
+type SalaryReportRow struct {
+	IdEmployee      string `json:"idEmployee"`
+	Salary          Money  `json:"salary"`
+	Currency        string `json:"currency"`
+	ConvertedSalary Money  `json:"convertedSalary"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// OrgChartNode is an employee with everyone reporting to them, nested for
+// org-chart rendering.
+type OrgChartNode struct {
+	*Employee
+	Reports []*OrgChartNode `json:"reports"`
+}
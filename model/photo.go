@@ -0,0 +1,45 @@
+package model
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Photo is an employee's profile picture. The original is kept as uploaded
+// and a thumbnail is generated for each configured size, all in the
+// original's format.
+type Photo struct {
+	IdEmployee  string `json:"idEmployee" db:"employee_id"`
+	ContentType string `json:"contentType" db:"content_type"`
+	Width       int    `json:"width" db:"width"`
+	Height      int    `json:"height" db:"height"`
+	Size        int64  `json:"size" db:"size"`
+	Checksum    string `json:"checksum" db:"checksum"`
+	UpdatedAt   string `json:"updatedAt,omitempty" db:"updated_at"`
+	Url         string `json:"url,omitempty" db:"-"`
+	// Thumbnails maps each thumbnail size to its URL.
+	Thumbnails map[int]string `json:"thumbnails,omitempty" db:"-"`
+}
+
+// Version identifies the photo content in URLs, so a replaced photo gets
+// new URLs and the old ones can be cached indefinitely.
+func (p *Photo) Version() string {
+	return PhotoVersion(p.Checksum)
+}
+
+func PhotoVersion(checksum string) string {
+	if len(checksum) > 16 {
+		return checksum[:16]
+	}
+	return checksum
+}
+
+// PhotoURL is the path serving the photo of the employee with the given
+// checksum; a size of 0 is the original.
+func PhotoURL(employeeId, checksum string, size int) string {
+	query := url.Values{"v": {PhotoVersion(checksum)}}
+	if size > 0 {
+		query.Set("size", strconv.Itoa(size))
+	}
+	return "/api/v1/employees/" + url.PathEscape(employeeId) + "/photo?" + query.Encode()
+}
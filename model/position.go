@@ -0,0 +1,26 @@
+package model
+
+// Position is a job title with its grade and the salary band employees
+// holding it are paid within, expressed in Currency.
+type Position struct {
+	IdPosition string `json:"idPosition,omitempty" db:"position_id" validate:"required"`
+	Title      string `json:"title,omitempty" db:"title" validate:"required"`
+	Grade      string `json:"grade,omitempty" db:"grade" validate:"required"`
+	MinSalary  Money  `json:"minSalary" db:"min_salary" validate:"decimal_gte=0"`
+	MaxSalary  Money  `json:"maxSalary" db:"max_salary" validate:"decimal_gtefield=MinSalary"`
+	Currency   string `json:"currency" db:"currency" validate:"required,iso4217"`
+}
+
+// SalaryOverride records a salary accepted outside its position's band.
+// Salary is in Currency, the band in BandCurrency.
+type SalaryOverride struct {
+	IdEmployee   string `json:"idEmployee" db:"employee_id"`
+	IdPosition   string `json:"idPosition" db:"position_id"`
+	Salary       Money  `json:"salary" db:"salary"`
+	Currency     string `json:"currency" db:"currency"`
+	MinSalary    Money  `json:"minSalary" db:"min_salary"`
+	MaxSalary    Money  `json:"maxSalary" db:"max_salary"`
+	BandCurrency string `json:"bandCurrency" db:"band_currency"`
+	Reason       string `json:"reason" db:"reason"`
+	CreatedAt    string `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// SearchResult is an employee matching a search, with its relevance and
+// the matching fields HTML-escaped with matches wrapped in <em>.
+type SearchResult struct {
+	Employee   *Employee         `json:"employee"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
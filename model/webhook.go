@@ -0,0 +1,58 @@
+package model
+
+import "encoding/json"
+
+const (
+	DeliveryPending      = "pending"
+	DeliverySucceeded    = "succeeded"
+	DeliveryDeadLettered = "dead_lettered"
+)
+
+// EventTypes lists the event types webhook subscriptions can select.
+var EventTypes = []string{EventEmployeeCreated, EventEmployeeUpdated, EventEmployeeDeleted}
+
+// WebhookSubscription is a partner URL that receives employee events.
+// EventTypes selects the events it receives, all of them when empty. The
+// Secret signs every delivery and is only returned when it is set.
+type WebhookSubscription struct {
+	IdSubscription int64    `json:"idSubscription" db:"id"`
+	Url            string   `json:"url" db:"url" validate:"required,url,max=2048"`
+	EventTypes     []string `json:"eventTypes" db:"event_types"`
+	Description    string   `json:"description" db:"description" validate:"max=255"`
+	Active         bool     `json:"active" db:"active"`
+	Secret         string   `json:"secret,omitempty" db:"secret" validate:"omitempty,min=16,max=100"`
+	CreatedAt      string   `json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt      string   `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// WebhookDelivery is one event on its way to one subscription. Payload and
+// AttemptLog are only filled in when a single delivery is read.
+type WebhookDelivery struct {
+	IdDelivery     int64             `json:"idDelivery" db:"id"`
+	IdSubscription int64             `json:"idSubscription" db:"subscription_id"`
+	IdEvent        string            `json:"idEvent" db:"event_id"`
+	EventType      string            `json:"eventType" db:"event_type"`
+	Status         string            `json:"status" db:"status"`
+	Attempts       int               `json:"attempts" db:"attempts"`
+	NextAttemptAt  string            `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  string            `json:"lastAttemptAt,omitempty" db:"last_attempt_at"`
+	ResponseStatus int               `json:"responseStatus,omitempty" db:"response_status"`
+	LastError      string            `json:"lastError,omitempty" db:"last_error"`
+	CreatedAt      string            `json:"createdAt,omitempty" db:"created_at"`
+	Payload        json.RawMessage   `json:"payload,omitempty" db:"payload"`
+	AttemptLog     []*WebhookAttempt `json:"attemptLog,omitempty" db:"-"`
+
+	// Url and Secret come from the subscription when a delivery is claimed
+	// for sending.
+	Url    string `json:"-" db:"-"`
+	Secret string `json:"-" db:"-"`
+}
+
+// WebhookAttempt records one request made for a delivery. ResponseStatus
+// is 0 when no response arrived.
+type WebhookAttempt struct {
+	AttemptedAt    string `json:"attemptedAt" db:"attempted_at"`
+	ResponseStatus int    `json:"responseStatus,omitempty" db:"response_status"`
+	Error          string `json:"error,omitempty" db:"error"`
+	DurationMs     int64  `json:"durationMs" db:"duration_ms"`
+}
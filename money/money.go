@@ -0,0 +1,69 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// minorUnits lists ISO-4217 currencies whose minor unit is not two digits.
+var minorUnits = map[string]int32{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorUnits is the number of decimal places amounts in currency are kept to.
+func MinorUnits(currency string) int32 {
+	if v, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return v
+	}
+	return 2
+}
+
+// Round rounds amount half away from zero to the minor unit of currency.
+func Round(amount decimal.Decimal, currency string) decimal.Decimal {
+	return amount.Round(MinorUnits(currency))
+}
+
+// Rates is an exchange-rate table: how many units of each currency one unit
+// of Base buys. Base itself is implicitly 1.
+type Rates struct {
+	Base  string
+	Rates map[string]decimal.Decimal
+}
+
+func (r Rates) rate(currency string) (decimal.Decimal, error) {
+	currency = strings.ToUpper(currency)
+	if currency == strings.ToUpper(r.Base) {
+		return decimal.NewFromInt(1), nil
+	}
+	v, ok := r.Rates[currency]
+	if !ok || !v.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+	}
+	return v, nil
+}
+
+// Convert changes amount from one currency to another through Base and
+// rounds the result to the minor unit of the target currency.
+func (r Rates) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	// divide last with extra precision so the rounding happens exactly once
+	converted := amount.Mul(toRate).DivRound(fromRate, 16)
+	return Round(converted, to), nil
+}
@@ -0,0 +1,57 @@
+package money
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func TestRates_Convert(t *testing.T) {
+	rates := Rates{Base: "USD", Rates: map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.92"),
+		"JPY": decimal.RequireFromString("149.5"),
+	}}
+	tests := []struct {
+		name    string
+		amount  string
+		from    string
+		to      string
+		want    string
+		wantErr error
+	}{
+		{name: "same currency", amount: "1234.5678", from: "EUR", to: "eur", want: "1234.5678"},
+		{name: "from base", amount: "100", from: "USD", to: "EUR", want: "92"},
+		{name: "to base", amount: "92", from: "EUR", to: "USD", want: "100"},
+		{name: "cross rate rounds to minor unit", amount: "0.10", from: "EUR", to: "JPY", want: "16"},
+		{name: "repeating fraction", amount: "1", from: "EUR", to: "USD", want: "1.09"},
+		{name: "unknown currency", amount: "1", from: "USD", to: "GBP", wantErr: ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(decimal.RequireFromString(tt.amount), tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("Convert() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		{amount: "10.005", currency: "USD", want: "10.01"},
+		{amount: "10.5", currency: "JPY", want: "11"},
+		{amount: "1.2345", currency: "KWD", want: "1.235"},
+	}
+	for _, tt := range tests {
+		if got := Round(decimal.RequireFromString(tt.amount), tt.currency); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("Round(%s, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"employee-golang/model"
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"strings"
+)
+
+var (
+	moneyType    = reflect.TypeOf(model.Money{})
+	quantityType = reflect.TypeOf(model.Quantity{})
+	rawType      = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas builds schemas from Go types the way encoding/json writes them:
+// fields are named and omitted by their json tags, amounts are numbers and
+// nil slices, maps and pointers are null. Named structs become components.
+type schemas struct {
+	components openapi3.Schemas
+}
+
+func (s *schemas) of(t reflect.Type) *openapi3.SchemaRef {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+	switch t {
+	case moneyType, quantityType:
+		schema := openapi3.NewFloat64Schema()
+		schema.Nullable = nullable
+		return openapi3.NewSchemaRef("", schema)
+	case rawType:
+		return openapi3.NewSchemaRef("", &openapi3.Schema{Nullable: true})
+	}
+
+	var schema *openapi3.Schema
+	switch t.Kind() {
+	case reflect.Struct:
+		return s.component(t, nullable)
+	case reflect.Slice, reflect.Array:
+		schema = openapi3.NewArraySchema().WithNullable()
+		schema.Items = s.of(t.Elem())
+		return openapi3.NewSchemaRef("", schema)
+	case reflect.Map:
+		schema = openapi3.NewObjectSchema().WithNullable()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: s.of(t.Elem())}
+		return openapi3.NewSchemaRef("", schema)
+	case reflect.String:
+		schema = openapi3.NewStringSchema()
+	case reflect.Bool:
+		schema = openapi3.NewBoolSchema()
+	case reflect.Int64, reflect.Uint64:
+		schema = openapi3.NewInt64Schema()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		schema = openapi3.NewIntegerSchema()
+	case reflect.Float32, reflect.Float64:
+		schema = openapi3.NewFloat64Schema()
+	default:
+		schema, nullable = &openapi3.Schema{}, true
+	}
+	schema.Nullable = nullable
+	return openapi3.NewSchemaRef("", schema)
+}
+
+// component returns a reference to the component of a named struct,
+// adding it on first use, or the schema itself for anonymous structs.
+func (s *schemas) component(t reflect.Type, nullable bool) *openapi3.SchemaRef {
+	if t.Name() == "" {
+		schema := openapi3.NewObjectSchema()
+		s.fields(schema, t)
+		schema.Nullable = nullable
+		return openapi3.NewSchemaRef("", schema)
+	}
+	ref := "#/components/schemas/" + t.Name()
+	schema, ok := s.components[t.Name()]
+	if !ok {
+		// registered before its fields so recursive types refer to it
+		schema = openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+		s.components[t.Name()] = schema
+		s.fields(schema.Value, t)
+	}
+	if nullable {
+		// a $ref cannot carry nullable, so wrap it
+		wrapper := &openapi3.Schema{Nullable: true, AllOf: openapi3.SchemaRefs{openapi3.NewSchemaRef(ref, schema.Value)}}
+		return openapi3.NewSchemaRef("", wrapper)
+	}
+	return openapi3.NewSchemaRef(ref, schema.Value)
+}
+
+// fields adds the json fields of t to schema. Fields validated as required
+// and never omitted are required; oneof validations become enums.
+func (s *schemas) fields(schema *openapi3.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		if f.Anonymous && tag[0] == "" && f.Type.Kind() == reflect.Struct {
+			s.fields(schema, f.Type)
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = f.Name
+		}
+		property := s.of(f.Type)
+		omitempty := len(tag) > 1 && tag[1] == "omitempty"
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			switch {
+			case rule == "required" && !omitempty:
+				schema.Required = append(schema.Required, name)
+			case strings.HasPrefix(rule, "oneof=") && f.Type.Kind() == reflect.String:
+				enum := *property.Value
+				for _, v := range strings.Fields(strings.TrimPrefix(rule, "oneof=")) {
+					enum.Enum = append(enum.Enum, v)
+				}
+				property = openapi3.NewSchemaRef("", &enum)
+			}
+		}
+		schema.WithPropertyRef(name, property)
+	}
+}
@@ -0,0 +1,293 @@
+// Package openapi builds the OpenAPI 3 document of the API from the echo
+// route table and the model types each route declares, so the document
+// cannot drift from the handlers.
+package openapi
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/util"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// bodyTypes are the media types request bodies and enveloped responses
+// may be written in.
+var bodyTypes = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, "application/msgpack"}
+
+// Operation describes a route. Body and Response are values of the request
+// and response data types; Response is wrapped in the envelope of the API
+// version the route belongs to.
+type Operation struct {
+	Summary string
+	Query   []Param
+	Body    any
+	// OptionalBody allows the request to be sent without Body.
+	OptionalBody bool
+	// Upload takes a multipart/form-data body with the file in the "file"
+	// field, and RawBody the file itself in one of the listed media types.
+	Upload  bool
+	RawBody []string
+	// Status is the success status, 200 when zero.
+	Status   int
+	Response any
+	// Produces lists the media types of success responses written as is
+	// rather than in the envelope, such as images.
+	Produces []string
+	// Empty lists statuses answered without a body.
+	Empty []int
+}
+
+// Param is a query parameter; Type is "integer" or "string".
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Operations holds the operation of each route, by method and echo path.
+type Operations struct {
+	mu      sync.RWMutex
+	byRoute map[string]Operation
+}
+
+func NewOperations() *Operations {
+	return &Operations{byRoute: map[string]Operation{}}
+}
+
+func (o *Operations) Add(method, path string, op Operation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.byRoute[method+" "+path] = op
+}
+
+func (o *Operations) get(method, path string) (Operation, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	op, ok := o.byRoute[method+" "+path]
+	return op, ok
+}
+
+// Spec is the document of the /api/ routes of an echo server. It is built
+// on first use, once every route is registered.
+type Spec struct {
+	echo       *echo.Echo
+	operations *Operations
+
+	once sync.Once
+	doc  *openapi3.T
+	err  error
+}
+
+func NewSpec(e *echo.Echo, operations *Operations) *Spec {
+	return &Spec{echo: e, operations: operations}
+}
+
+// Document returns the document, or why it could not be built, such as an
+// /api/ route without an operation.
+func (s *Spec) Document() (*openapi3.T, error) {
+	s.once.Do(func() {
+		s.doc, s.err = s.build()
+	})
+	return s.doc, s.err
+}
+
+// Route returns the operation of the route with the echo path, nil when it
+// is not in the document.
+func (s *Spec) Route(method, path string) (*routers.Route, error) {
+	doc, err := s.Document()
+	if err != nil {
+		return nil, err
+	}
+	path = openAPIPath(path)
+	item := doc.Paths[path]
+	if item == nil || item.GetOperation(method) == nil {
+		return nil, nil
+	}
+	return &routers.Route{Spec: doc, Path: path, PathItem: item, Method: method, Operation: item.GetOperation(method)}, nil
+}
+
+func (s *Spec) build() (*openapi3.T, error) {
+	schemas := &schemas{components: openapi3.Schemas{}}
+	schemas.components["Error"] = openapi3.NewSchemaRef("", required(openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewIntegerSchema()).
+		WithProperty("status", openapi3.NewStringSchema()).
+		WithProperty("data", openapi3.NewStringSchema()), "code", "status"))
+	schemas.components["ErrorV2"] = openapi3.NewSchemaRef("", required(openapi3.NewObjectSchema().
+		WithPropertyRef("error", schemas.of(reflect.TypeOf(model.EnvelopeError{}))).
+		WithPropertyRef("meta", schemas.of(reflect.TypeOf(model.EnvelopeMeta{}))), "error", "meta"))
+
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Employee API",
+			Description: "v1 answers with {code, status, data}; v2 with {data, error, meta}.",
+			Version:     "2.0",
+		},
+		Paths:      openapi3.Paths{},
+		Components: &openapi3.Components{Schemas: schemas.components},
+	}
+	routes := s.echo.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		op, ok := s.operations.get(route.Method, route.Path)
+		if !ok {
+			return nil, fmt.Errorf("route %s %s has no OpenAPI operation", route.Method, route.Path)
+		}
+		path := openAPIPath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &openapi3.PathItem{}
+			doc.Paths[path] = item
+		}
+		item.SetOperation(route.Method, schemas.operation(route, op))
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (s *schemas) operation(route *echo.Route, op Operation) *openapi3.Operation {
+	v2 := strings.HasPrefix(route.Path, "/api/v2/")
+	segments := strings.Split(strings.TrimPrefix(route.Path, "/"), "/")
+	res := &openapi3.Operation{
+		OperationID: operationId(route.Name, v2),
+		Summary:     op.Summary,
+		Tags:        []string{segments[2]},
+		Responses:   openapi3.Responses{},
+	}
+
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			res.AddParameter(openapi3.NewPathParameter(segment[1:]).WithSchema(openapi3.NewStringSchema()))
+		}
+	}
+	for _, param := range op.Query {
+		schema := openapi3.NewStringSchema()
+		if param.Type == "integer" {
+			schema = openapi3.NewIntegerSchema()
+		}
+		p := openapi3.NewQueryParameter(param.Name).WithSchema(schema)
+		p.Description = param.Description
+		res.AddParameter(p)
+	}
+
+	content := openapi3.Content{}
+	if op.Body != nil {
+		body := s.of(reflect.TypeOf(op.Body))
+		for _, mediaType := range bodyTypes {
+			content[mediaType] = openapi3.NewMediaType().WithSchemaRef(body)
+		}
+	}
+	if op.Upload {
+		content[echo.MIMEMultipartForm] = openapi3.NewMediaType().WithSchema(required(openapi3.NewObjectSchema().
+			WithProperty("file", openapi3.NewStringSchema().WithFormat("binary")), "file"))
+	}
+	for _, mediaType := range op.RawBody {
+		content[mediaType] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema().WithFormat("binary"))
+	}
+	if len(content) > 0 {
+		res.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithContent(content).WithRequired(!op.OptionalBody)}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = 200
+	}
+	content = openapi3.Content{}
+	for _, mediaType := range op.Produces {
+		content[mediaType] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema().WithFormat("binary"))
+	}
+	if op.Response != nil {
+		data := s.of(reflect.TypeOf(op.Response))
+		envelope := openapi3.NewObjectSchema()
+		if v2 {
+			required(envelope.WithPropertyRef("data", data).
+				WithPropertyRef("meta", s.of(reflect.TypeOf(model.EnvelopeMeta{}))), "meta")
+		} else {
+			required(envelope.WithProperty("code", openapi3.NewIntegerSchema()).
+				WithProperty("status", openapi3.NewStringSchema()).
+				WithPropertyRef("data", data), "code", "status")
+		}
+		for _, mediaType := range responseTypes(v2) {
+			content[mediaType] = openapi3.NewMediaType().WithSchema(envelope)
+		}
+		if data.Value.Type == openapi3.TypeArray {
+			// one row per item, one column per field
+			content["text/csv"] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+		}
+	}
+	res.AddResponse(status, openapi3.NewResponse().WithDescription(http.StatusText(status)).WithContent(content))
+	for _, empty := range op.Empty {
+		res.AddResponse(empty, openapi3.NewResponse().WithDescription(http.StatusText(empty)))
+	}
+
+	errorRef := "#/components/schemas/Error"
+	if v2 {
+		errorRef = "#/components/schemas/ErrorV2"
+	}
+	errorSchema := openapi3.NewSchemaRef(errorRef, s.components[strings.TrimPrefix(errorRef, "#/components/schemas/")].Value)
+	errorContent := openapi3.Content{}
+	for _, mediaType := range responseTypes(v2) {
+		errorContent[mediaType] = openapi3.NewMediaType().WithSchemaRef(errorSchema)
+	}
+	res.Responses["default"] = &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Error").WithContent(errorContent)}
+	return res
+}
+
+// responseTypes are the media types enveloped responses are written in;
+// v2 answers JSON as util.MediaTypeV2.
+func responseTypes(v2 bool) []string {
+	if !v2 {
+		return bodyTypes
+	}
+	return append([]string{util.MediaTypeV2}, bodyTypes[1:]...)
+}
+
+// openAPIPath turns echo's :name parameters into {name}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationId is the name of the handler method, such as getEmployee, with
+// a V2 suffix for v2 routes.
+func operationId(handler string, v2 bool) string {
+	name := strings.TrimSuffix(handler[strings.LastIndex(handler, ".")+1:], "-fm")
+	if name == "" {
+		return ""
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	if v2 {
+		return string(runes) + "V2"
+	}
+	return string(runes)
+}
+
+func required(schema *openapi3.Schema, properties ...string) *openapi3.Schema {
+	schema.Required = properties
+	return schema
+}
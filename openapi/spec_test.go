@@ -0,0 +1,66 @@
+package openapi_test
+
+import (
+	"employee-golang/controller"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"strings"
+	"testing"
+)
+
+func allRoutes() *echo.Echo {
+	e := echo.New()
+	(&controller.Controller{}).Routes(e)
+	(&controller.DepartmentHandler{}).Routes(e)
+	(&controller.PositionHandler{}).Routes(e)
+	(&controller.ReportHandler{}).Routes(e)
+	(&controller.LeaveHandler{}).Routes(e)
+	(&controller.AttendanceHandler{}).Routes(e)
+	(&controller.DocumentHandler{}).Routes(e)
+	(&controller.PhotoHandler{}).Routes(e)
+	(&controller.SearchHandler{}).Routes(e)
+	(&controller.WebhookHandler{}).Routes(e)
+	return e
+}
+
+func TestSpec_Document(t *testing.T) {
+	e := allRoutes()
+	doc, err := controller.NewOpenAPISpec(e).Document()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range e.Routes() {
+		path := strings.NewReplacer(":id", "{id}", ":documentId", "{documentId}", ":deliveryId", "{deliveryId}").Replace(route.Path)
+		if item := doc.Paths[path]; item == nil || item.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s is missing from the document", route.Method, route.Path)
+		}
+	}
+
+	get := doc.Paths["/api/v1/employees/{id}"].Get
+	if get.OperationID != "getEmployeeById" || get.Parameters[0].Value.Name != "id" {
+		t.Errorf("unexpected operation %s %v", get.OperationID, get.Parameters)
+	}
+	if v2 := doc.Paths["/api/v2/employees/{id}"].Get; v2.OperationID != "getEmployeeByIdV2" || v2.Responses.Get(200).Value.Content.Get("application/vnd.employee.v2+json").Schema.Value.Properties["meta"] == nil {
+		t.Error("v2 operations should answer with the v2 envelope")
+	}
+
+	employee := doc.Components.Schemas["Employee"].Value
+	if employee.Properties["idEmployee"] == nil || employee.Properties["salary"].Value.Type != "number" ||
+		len(employee.Properties["status"].Value.Enum) != 4 {
+		t.Errorf("unexpected employee schema %+v", employee.Properties)
+	}
+	if document := doc.Components.Schemas["Document"].Value; document.Properties["StorageKey"] != nil || document.Properties["fileName"] == nil {
+		t.Errorf("unexpected document schema %+v", document.Properties)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpec_DocumentMissingOperation(t *testing.T) {
+	e := allRoutes()
+	e.GET("/api/v1/undocumented", func(c echo.Context) error { return nil })
+	if _, err := controller.NewOpenAPISpec(e).Document(); err == nil || err.Error() != "route GET /api/v1/undocumented has no OpenAPI operation" {
+		t.Errorf("err = %v, want a missing operation", err)
+	}
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// dateTimeLayout is how datetime columns are written and read back. Times
+// are stored in UTC.
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+type attendanceRepositories struct {
+	repositories
+}
+
+func NewAttendanceRepositories() IAttendanceRepositories {
+	return &attendanceRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type IAttendanceRepositories interface {
+	// InsertAttendanceEvent inserts event once check accepts the employee
+	// and their last clock event, nil when there is none; check may adjust
+	// event before it is written. The employee row stays locked from those
+	// reads until the event is inserted, so concurrent clock events are
+	// checked one after the other.
+	InsertAttendanceEvent(ctx context.Context, event *model.AttendanceEvent, check func(employee *model.Employee, last *model.AttendanceEvent) error) (rs string, err error)
+	GetAttendanceEvents(ctx context.Context, employeeId string, from, to time.Time) (rs []*model.AttendanceEvent, err error)
+	GetAllAttendanceEvents(ctx context.Context, from, to time.Time) (rs []*model.AttendanceEvent, err error)
+}
+
+func (r attendanceRepositories) InsertAttendanceEvent(ctx context.Context, event *model.AttendanceEvent, check func(employee *model.Employee, last *model.AttendanceEvent) error) (rs string, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	employee := &model.Employee{}
+	if err = scanEmployee(tx.QueryRowContext(ctx, config.GetEmployeeByIdForUpdate(), event.IdEmployee), employee); err != nil {
+		return "", err
+	}
+	last, err := lastAttendanceEvent(ctx, tx, event.IdEmployee)
+	if err != nil {
+		return "", err
+	}
+	if err = check(employee, last); err != nil {
+		return "", err
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, event.OccurredAt)
+	if err != nil {
+		return "", err
+	}
+	res, err := tx.ExecContext(ctx, config.InsertAttendanceEvent(),
+		event.IdEmployee, event.Kind, occurredAt.UTC().Format(dateTimeLayout), event.Note, event.Capped)
+	if err != nil {
+		logrus.Errorf("Error inserting attendance event: %v", err)
+		return "", err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		event.IdEvent = id
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Clocked " + event.Kind, nil
+}
+
+// lastAttendanceEvent is the employee's latest clock event, nil when there
+// is none.
+func lastAttendanceEvent(ctx context.Context, tx *sql.Tx, employeeId string) (*model.AttendanceEvent, error) {
+	data := &model.AttendanceEvent{}
+	err := scanAttendanceEvent(tx.QueryRowContext(ctx, config.GetLastAttendanceEvent(), employeeId), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		logrus.Errorf("Error retrieving attendance: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r attendanceRepositories) GetAttendanceEvents(ctx context.Context, employeeId string, from, to time.Time) (rs []*model.AttendanceEvent, err error) {
+	return r.queryAttendanceEvents(ctx, config.GetAttendanceEvents(),
+		employeeId, from.UTC().Format(dateTimeLayout), to.UTC().Format(dateTimeLayout))
+}
+
+func (r attendanceRepositories) GetAllAttendanceEvents(ctx context.Context, from, to time.Time) (rs []*model.AttendanceEvent, err error) {
+	return r.queryAttendanceEvents(ctx, config.GetAllAttendanceEvents(),
+		from.UTC().Format(dateTimeLayout), to.UTC().Format(dateTimeLayout))
+}
+
+func (r attendanceRepositories) queryAttendanceEvents(ctx context.Context, query string, args ...any) ([]*model.AttendanceEvent, error) {
+	res := make([]*model.AttendanceEvent, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.AttendanceEvent)
+		if err := scanAttendanceEvent(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func scanAttendanceEvent(row scanner, data *model.AttendanceEvent) error {
+	var occurredAt string
+	err := row.Scan(
+		&data.IdEvent,
+		&data.IdEmployee,
+		&data.Kind,
+		&occurredAt,
+		&data.Note,
+		&data.Capped,
+	)
+	if err != nil {
+		return err
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, occurredAt, time.UTC)
+	if err != nil {
+		return err
+	}
+	data.OccurredAt = t.Format(time.RFC3339)
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test_attendanceRepositories_InsertAttendanceEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkErr error
+	}{
+		{name: "accepted"},
+		{name: "rejected by the check", checkErr: model.ErrAlreadyClockedIn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				logrus.Fatal("error creating mock")
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("1").
+				WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+					AddRow("1", "Jane", "Doe", "jane@example.com", "1", "2020-01-06", "0", "USD", "", "", "", "active", "", "", ""))
+			mock.ExpectQuery(config.GetLastAttendanceEvent()).WithArgs("1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "employee_id", "kind", "occurred_at", "note", "capped"}).
+					AddRow(4, "1", model.ClockIn, "2024-03-11 09:00:00", "", false))
+			if tt.checkErr != nil {
+				mock.ExpectRollback()
+			} else {
+				// the event is written as the check left it
+				mock.ExpectExec(config.InsertAttendanceEvent()).
+					WithArgs("1", model.ClockOut, "2024-03-12 09:00:00", "", true).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
+			}
+
+			var checked *model.AttendanceEvent
+			event := &model.AttendanceEvent{IdEmployee: "1", Kind: model.ClockOut, OccurredAt: "2024-03-13T09:00:00Z"}
+			r := attendanceRepositories{repositories: repositories{DB: db}}
+			_, err = r.InsertAttendanceEvent(context.Background(), event, func(employee *model.Employee, last *model.AttendanceEvent) error {
+				checked = last
+				event.OccurredAt, event.Capped = "2024-03-12T09:00:00Z", true
+				return tt.checkErr
+			})
+			if !errors.Is(err, tt.checkErr) {
+				t.Errorf("InsertAttendanceEvent() error = %v, wantErr %v", err, tt.checkErr)
+			}
+			if checked == nil || checked.Kind != model.ClockIn || checked.OccurredAt != "2024-03-11T09:00:00Z" {
+				t.Errorf("check got last event %+v", checked)
+			}
+			if tt.checkErr == nil && event.IdEvent != 5 {
+				t.Errorf("InsertAttendanceEvent() id = %d, want 5", event.IdEvent)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"strings"
+)
+
+// maxBatchIds caps the ids bound to one statement.
+const maxBatchIds = 500
+
+// queryBatches runs query for the ids, maxBatchIds at a time, expanding its
+// "in (?)" to one placeholder per id, and concatenates the results.
+func queryBatches[T any](ids []string, query string, run func(query string, args []any) ([]T, error)) ([]T, error) {
+	res := make([]T, 0, len(ids))
+	for start := 0; start < len(ids); start += maxBatchIds {
+		end := start + maxBatchIds
+		if end > len(ids) {
+			end = len(ids)
+		}
+		args := make([]any, 0, end-start)
+		for _, id := range ids[start:end] {
+			args = append(args, id)
+		}
+		expanded := strings.Replace(query, "in (?)", "in (?"+strings.Repeat(", ?", len(args)-1)+")", 1)
+		rows, err := run(expanded, args)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rows...)
+	}
+	return res, nil
+}
+
+// GetEmployeesByIds returns the employees with the given ids, in no
+// particular order; unknown ids are left out.
+func (r repositories) GetEmployeesByIds(ctx context.Context, ids []string) (rs []*model.Employee, err error) {
+	return queryBatches(ids, config.GetEmployeesByIds(), func(query string, args []any) ([]*model.Employee, error) {
+		return r.queryEmployees(ctx, r.reader(ctx), query, args...)
+	})
+}
+
+// GetEmployeesByManagers returns the direct reports of all the managers.
+func (r repositories) GetEmployeesByManagers(ctx context.Context, managerIds []string) (rs []*model.Employee, err error) {
+	return queryBatches(managerIds, config.GetEmployeesByManagers(), func(query string, args []any) ([]*model.Employee, error) {
+		return r.queryEmployees(ctx, r.reader(ctx), query, args...)
+	})
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"github.com/DATA-DOG/go-sqlmock"
+	"strings"
+	"testing"
+)
+
+func Test_repositories_GetEmployeesByIds(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	query := strings.Replace(config.GetEmployeesByIds(), "in (?)", "in (?, ?, ?)", 1)
+	mock.ExpectQuery(query).WithArgs("1", "2", "3").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("1", "John", "Doe", "john@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", "").
+			AddRow("3", "Jane", "Doe", "jane@example.com", "1", "", "0", "USD", "", "1", "", "active", "", "", ""))
+
+	r := repositories{DB: db}
+	rs, err := r.GetEmployeesByIds(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 2 || rs[0].IdEmployee != "1" || rs[1].IdEmployee != "3" {
+		t.Errorf("unexpected employees %v", rs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_queryBatches(t *testing.T) {
+	ids := make([]string, maxBatchIds+1)
+	var queries []string
+	var counts []int
+	_, err := queryBatches(ids, "select 1 where id in (?)", func(query string, args []any) ([]int, error) {
+		queries, counts = append(queries, query), append(counts, len(args))
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0] != maxBatchIds || counts[1] != 1 || queries[1] != "select 1 where id in (?)" {
+		t.Errorf("unexpected batches %v", counts)
+	}
+}
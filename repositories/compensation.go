@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+type compensationRepositories struct {
+	repositories
+}
+
+func NewCompensationRepositories() ICompensationRepositories {
+	return &compensationRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type ICompensationRepositories interface {
+	GetCompensationHistory(ctx context.Context, employeeId string) (rs []*model.Compensation, err error)
+	GetCompensationAsOf(ctx context.Context, employeeId, date string) (rs *model.Compensation, err error)
+}
+
+// insertCompensation adds compensation, if any, to the salary history in tx.
+func insertCompensation(ctx context.Context, tx *sql.Tx, compensation *model.Compensation) error {
+	if compensation == nil {
+		return nil
+	}
+	res, err := tx.ExecContext(ctx, config.InsertCompensation(),
+		compensation.IdEmployee, compensation.Salary, compensation.Currency, compensation.EffectiveDate, compensation.Reason)
+	if err != nil {
+		logrus.Errorf("Error recording compensation: %v", err)
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		compensation.IdCompensation = id
+	}
+	return nil
+}
+
+// ScheduleCompensation adds compensation, and the override accepting its
+// salary if any, as an update of the employee. The EmployeeUpdated event is
+// recorded even when the change takes effect later.
+func (r repositories) ScheduleCompensation(ctx context.Context, compensation *model.Compensation, override *model.SalaryOverride) (rs string, err error) {
+	err = r.writeEmployeeEvent(ctx, model.EventEmployeeUpdated, compensation.IdEmployee, true, func(tx *sql.Tx, _ *model.Employee) error {
+		if err := insertCompensation(ctx, tx, compensation); err != nil {
+			return err
+		}
+		return insertSalaryOverride(ctx, tx, override)
+	})
+	if err != nil {
+		logrus.Errorf("Error inserting compensation: %v", err)
+		return "", err
+	}
+	return "Compensation was scheduled", nil
+}
+
+func (r compensationRepositories) GetCompensationHistory(ctx context.Context, employeeId string) (rs []*model.Compensation, err error) {
+	res := make([]*model.Compensation, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetCompensationHistory(), employeeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Compensation)
+		if err := scanCompensation(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r compensationRepositories) GetCompensationAsOf(ctx context.Context, employeeId, date string) (rs *model.Compensation, err error) {
+	data := &model.Compensation{}
+	err = scanCompensation(r.reader(ctx).QueryRowContext(ctx, config.GetCompensationAsOf(), employeeId, date), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("No compensation for employee %v as of %v", employeeId, date)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving compensation: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func scanCompensation(row scanner, data *model.Compensation) error {
+	return row.Scan(
+		&data.IdCompensation,
+		&data.IdEmployee,
+		&data.Salary,
+		&data.Currency,
+		&data.EffectiveDate,
+		&data.Reason,
+		&data.CreatedAt,
+	)
+}
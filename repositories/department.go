@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+type departmentRepositories struct {
+	repositories
+}
+
+func NewDepartmentRepositories() IDepartmentRepositories {
+	return &departmentRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type IDepartmentRepositories interface {
+	GetDepartment(ctx context.Context) (rs []*model.Department, err error)
+	GetDepartmentById(ctx context.Context, id string) (rs *model.Department, err error)
+	GetDepartmentsByIds(ctx context.Context, ids []string) (rs []*model.Department, err error)
+	GetDepartmentEmployees(ctx context.Context, id string) (rs []*model.Employee, err error)
+	InsertDepartment(ctx context.Context, department *model.Department) (rs string, err error)
+	UpdateDepartment(ctx context.Context, department *model.Department) (rs string, err error)
+	DeleteDepartment(ctx context.Context, id string) (rs string, err error)
+}
+
+func (r departmentRepositories) GetDepartment(ctx context.Context) (rs []*model.Department, err error) {
+	return r.queryDepartments(ctx, config.GetDepartments())
+}
+
+func (r departmentRepositories) GetDepartmentsByIds(ctx context.Context, ids []string) (rs []*model.Department, err error) {
+	return queryBatches(ids, config.GetDepartmentsByIds(), func(query string, args []any) ([]*model.Department, error) {
+		return r.queryDepartments(ctx, query, args...)
+	})
+}
+
+func (r departmentRepositories) queryDepartments(ctx context.Context, query string, args ...any) ([]*model.Department, error) {
+	res := make([]*model.Department, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Department)
+		err := rows.Scan(
+			&data.IdDepartment,
+			&data.Name,
+			&data.Description,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r departmentRepositories) GetDepartmentById(ctx context.Context, id string) (rs *model.Department, err error) {
+	data := &model.Department{}
+	err = r.reader(ctx).QueryRowContext(ctx, config.GetDepartmentById(), id).Scan(
+		&data.IdDepartment,
+		&data.Name,
+		&data.Description,
+	)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Department %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving department: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r departmentRepositories) GetDepartmentEmployees(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.queryEmployees(ctx, r.reader(ctx), config.GetEmployeesByDepartment(), id)
+}
+
+func (r departmentRepositories) InsertDepartment(ctx context.Context, department *model.Department) (rs string, err error) {
+	exists, err := r.departmentExists(ctx, department.IdDepartment)
+	if err != nil {
+		logrus.Errorf("Error checking department existence: %v", err)
+		return "", err
+	}
+	if exists {
+		return "Department already exists", model.ErrDepartmentExists
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.InsertDepartment(),
+		department.IdDepartment, department.Name, department.Description)
+	if err != nil {
+		logrus.Errorf("Error inserting department: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Successfully inserted a new department", nil
+}
+
+func (r departmentRepositories) UpdateDepartment(ctx context.Context, department *model.Department) (rs string, err error) {
+	exists, err := r.departmentExists(ctx, department.IdDepartment)
+	if err != nil {
+		logrus.Errorf("Error checking department existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Department doesn't exists", model.ErrDepartmentNotFound
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.EditDepartment(),
+		department.Name, department.Description, department.IdDepartment)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Department was edited", nil
+}
+
+func (r departmentRepositories) DeleteDepartment(ctx context.Context, id string) (rs string, err error) {
+	exists, err := r.departmentExists(ctx, id)
+	if err != nil {
+		logrus.Errorf("Error checking department existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Department doesn't exists", model.ErrDepartmentNotFound
+	}
+
+	var employees int
+	err = r.DB.QueryRowContext(ctx, config.CountEmployeeByDepartment(), id).Scan(&employees)
+	if err != nil {
+		logrus.Errorf("Error counting department employees: %v", err)
+		return "", err
+	}
+	if employees > 0 {
+		return "Department still has employees", model.ErrDepartmentInUse
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.DeleteDepartment(), id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Department was deleted", nil
+}
+
+func (r departmentRepositories) departmentExists(ctx context.Context, id string) (bool, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, config.CountDepartment(), id).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
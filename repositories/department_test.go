@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test_departmentRepositories_DeleteDepartment(t *testing.T) {
+	tests := []struct {
+		name      string
+		exists    int
+		employees int
+		wantRs    string
+		wantErr   error
+	}{
+		{
+			name:      "success delete",
+			exists:    1,
+			employees: 0,
+			wantRs:    "Department was deleted",
+		},
+		{
+			name:    "department doesnt exist",
+			exists:  0,
+			wantRs:  "Department doesn't exists",
+			wantErr: model.ErrDepartmentNotFound,
+		},
+		{
+			name:      "department still has employees",
+			exists:    1,
+			employees: 2,
+			wantRs:    "Department still has employees",
+			wantErr:   model.ErrDepartmentInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				logrus.Fatal("error creating mock")
+			}
+			defer db.Close()
+
+			mock.ExpectQuery("select count(*) from department where department_id = ?").
+				WithArgs("D1").
+				WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(tt.exists))
+			if tt.exists > 0 {
+				mock.ExpectQuery("select count(*) from employee where department_id = ?").
+					WithArgs("D1").
+					WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(tt.employees))
+			}
+			if tt.wantErr == nil {
+				mock.ExpectExec("delete from department where department_id = ?").
+					WithArgs("D1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			r := departmentRepositories{repositories{DB: db}}
+			gotRs, err := r.DeleteDepartment(context.Background(), "D1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteDepartment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotRs != tt.wantRs {
+				t.Errorf("DeleteDepartment() gotRs = %v, want %v", gotRs, tt.wantRs)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_repositories_InsertEmployee_unknownDepartment(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	employee := &model.Employee{IdEmployee: "1", Email: "john.doe@example.com", DepartmentId: "D9"}
+	mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
+		WithArgs(employee.IdEmployee, employee.Email).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+	mock.ExpectQuery("select count(*) from department where department_id = ?").
+		WithArgs("D9").
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+	r := repositories{DB: db}
+	if _, err := r.InsertEmployee(context.Background(), employee); !errors.Is(err, model.ErrDepartmentNotFound) {
+		t.Errorf("InsertEmployee() error = %v, want %v", err, model.ErrDepartmentNotFound)
+	}
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/storage"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+type documentRepositories struct {
+	repositories
+}
+
+// NewDocumentStore builds the storage document contents go to, selected by
+// configuration.
+func NewDocumentStore() storage.Store {
+	switch config.GetStorageBackend() {
+	case "s3":
+		return storage.NewS3Store(config.GetS3Endpoint(), config.GetS3Region(), config.GetS3Bucket(),
+			config.GetS3AccessKey(), config.GetS3SecretKey(), config.IsS3PathStyle())
+	default:
+		return storage.NewFileStore(config.GetStorageRoot())
+	}
+}
+
+func NewDocumentRepositories() IDocumentRepositories {
+	return &documentRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type IDocumentRepositories interface {
+	InsertDocument(ctx context.Context, document *model.Document) (rs string, err error)
+	GetDocuments(ctx context.Context, employeeId string) (rs []*model.Document, err error)
+	GetDocumentById(ctx context.Context, employeeId string, id int64) (rs *model.Document, err error)
+	DeleteDocument(ctx context.Context, employeeId string, id int64) (rs string, err error)
+}
+
+func (r documentRepositories) InsertDocument(ctx context.Context, document *model.Document) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.InsertDocument(),
+		document.IdEmployee, document.FileName, document.ContentType, document.Size, document.Checksum, document.StorageKey)
+	if err != nil {
+		logrus.Errorf("Error inserting document: %v", err)
+		return "", err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		document.IdDocument = id
+	}
+	r.markWrite(ctx)
+	return "Document was uploaded", nil
+}
+
+func (r documentRepositories) GetDocuments(ctx context.Context, employeeId string) (rs []*model.Document, err error) {
+	return employeeDocuments(ctx, r.reader(ctx), employeeId)
+}
+
+func employeeDocuments(ctx context.Context, db queryer, employeeId string) ([]*model.Document, error) {
+	res := make([]*model.Document, 0)
+	rows, err := db.QueryContext(ctx, config.GetDocuments(), employeeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Document)
+		if err := scanDocument(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r documentRepositories) GetDocumentById(ctx context.Context, employeeId string, id int64) (rs *model.Document, err error) {
+	data := &model.Document{}
+	err = scanDocument(r.reader(ctx).QueryRowContext(ctx, config.GetDocumentById(), employeeId, id), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Document %v of employee %v not found", id, employeeId)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving document: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r documentRepositories) DeleteDocument(ctx context.Context, employeeId string, id int64) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.DeleteDocument(), employeeId, id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return "", sql.ErrNoRows
+	}
+	r.markWrite(ctx)
+	return "Document was deleted", nil
+}
+
+func scanDocument(row scanner, data *model.Document) error {
+	return row.Scan(
+		&data.IdDocument,
+		&data.IdEmployee,
+		&data.FileName,
+		&data.ContentType,
+		&data.Size,
+		&data.Checksum,
+		&data.StorageKey,
+		&data.CreatedAt,
+	)
+}
@@ -6,19 +6,25 @@ import (
 	"employee-golang/config"
 	"employee-golang/model"
 	"errors"
-	_ "github.com/go-sql-driver/mysql"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
+	"strings"
 	"sync"
 	"time"
 )
 
 type repositories struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Router *ReplicaRouter
 }
 
 var (
 	EmployeeDB             *sql.DB
+	EmployeeRouter         *ReplicaRouter
 	MutexConfigurationConn = &sync.Mutex{}
+	// errConfiguration is why the datasource settings could not be used.
+	errConfiguration error
 )
 
 func NewEmployeeRepositories() IEmployeeRepositories {
@@ -28,52 +34,139 @@ func InitConfiguration() *repositories {
 	// connect db
 	MutexConfigurationConn.Lock()
 	if EmployeeDB == nil {
-		configurationDB, err := sql.Open("mysql", config.GetConnection())
+		configurationDB, err := openConfiguration()
 		if err != nil {
 			logrus.Errorf("failed to create configuration connection %s", err)
 		}
-		err = configurationDB.Ping()
-		if err != nil {
-			logrus.Error(err)
-		}
-		configurationDB.SetConnMaxLifetime(time.Minute * 3)
-		configurationDB.SetMaxOpenConns(10)
-		configurationDB.SetMaxIdleConns(10)
+		errConfiguration = err
 		EmployeeDB = configurationDB
+		if configurationDB != nil {
+			EmployeeRouter, errConfiguration = newReplicaRouterFromConfig(configurationDB)
+		}
 	}
 	MutexConfigurationConn.Unlock()
 	return &repositories{
-		DB: EmployeeDB,
+		DB:     EmployeeDB,
+		Router: EmployeeRouter,
+	}
+}
+
+func openConfiguration() (*sql.DB, error) {
+	dsn, err := config.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetConnMaxLifetime(config.GetConnMaxLifetime())
+	db.SetConnMaxIdleTime(config.GetConnMaxIdleTime())
+	db.SetMaxOpenConns(config.GetMaxOpenConns())
+	db.SetMaxIdleConns(config.GetMaxIdleConns())
+	return db, nil
+}
+
+// WaitForConnection pings the employee database until it answers, giving up
+// after the configured number of attempts. It is meant to be called once at
+// startup so an unreachable database stops the service instead of failing
+// every request afterwards. Settings the connection cannot be built from,
+// such as an unknown TLS mode, fail it right away.
+func WaitForConnection() error {
+	db := InitConfiguration().DB
+	MutexConfigurationConn.Lock()
+	errConfig := errConfiguration
+	MutexConfigurationConn.Unlock()
+	if errConfig != nil {
+		return errConfig
 	}
+	if db == nil {
+		return errors.New("employee database is not configured")
+	}
+	retryMax := config.GetConnectRetryMax()
+	retryWait := config.GetConnectRetryWait()
+
+	var err error
+	for attempt := 1; attempt <= retryMax; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), retryWait)
+		err = db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		logrus.Warnf("database ping attempt %d/%d failed: %s", attempt, retryMax, err)
+		if attempt < retryMax {
+			time.Sleep(retryWait)
+		}
+	}
+	return fmt.Errorf("database unreachable after %d attempts: %w", retryMax, err)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEmployee reads one row selected with the employee column list shared
+// by every employee query.
+func scanEmployee(row scanner, data *model.Employee) error {
+	var photo string
+	err := row.Scan(
+		&data.IdEmployee,
+		&data.FirstName,
+		&data.LastName,
+		&data.Email,
+		&data.Phone,
+		&data.HireDate,
+		&data.Salary,
+		&data.Currency,
+		&data.DepartmentId,
+		&data.ManagerId,
+		&data.PositionId,
+		&data.Status,
+		&data.TerminationDate,
+		&data.TerminationReason,
+		&photo,
+	)
+	if err == nil && photo != "" {
+		data.PhotoUrl = model.PhotoURL(data.IdEmployee, photo, 0)
+	}
+	return err
 }
 
 type IEmployeeRepositories interface {
-	GetEmployee() (rs []*model.Employee, err error)
-	GetEmployeeById(id string) (rs *model.Employee, err error)
-	InsertEmployee(employee *model.Employee) (rs string, err error)
-	UpdateEmployee(employee *model.Employee) (rs string, err error)
-	DeleteEmployee(id string) (rs string, err error)
+	GetEmployee(ctx context.Context) (rs []*model.Employee, err error)
+	GetEmployeeById(ctx context.Context, id string) (rs *model.Employee, err error)
+	InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error)
+	UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error)
+	DeleteEmployee(ctx context.Context, id string, removed func(documents []*model.Document, photo *model.Photo)) (rs string, err error)
+	GetDirectReports(ctx context.Context, id string) (rs []*model.Employee, err error)
+	GetReportingChain(ctx context.Context, id string) (rs []*model.Employee, err error)
+	GetSubordinates(ctx context.Context, id string) (rs []*model.Employee, err error)
+	GetEmployeesByStatus(ctx context.Context, statuses []string) (rs []*model.Employee, err error)
+	GetEmployeesByIds(ctx context.Context, ids []string) (rs []*model.Employee, err error)
+	GetEmployeesByManagers(ctx context.Context, managerIds []string) (rs []*model.Employee, err error)
+	ChangeEmployeeStatus(ctx context.Context, employee *model.Employee, change *model.StatusChange) (rs string, err error)
+	InsertStatusChange(ctx context.Context, change *model.StatusChange) error
+	ScheduleCompensation(ctx context.Context, compensation *model.Compensation, override *model.SalaryOverride) (rs string, err error)
+	GetStatusHistory(ctx context.Context, id string) (rs []*model.StatusChange, err error)
+	GetPhoto(ctx context.Context, id string) (rs *model.Photo, err error)
+	SavePhoto(ctx context.Context, photo *model.Photo) (rs string, err error)
+	DeletePhoto(ctx context.Context, id string) (rs string, err error)
+	SearchEmployees(ctx context.Context, query string, limit int) (rs []*model.SearchResult, err error)
+	HasEmployeeFullText(ctx context.Context) (bool, error)
 }
 
-func (r repositories) GetEmployee() (rs []*model.Employee, err error) {
+func (r repositories) GetEmployee(ctx context.Context) (rs []*model.Employee, err error) {
 	res := make([]*model.Employee, 0)
 	query := config.GetEmployees()
-	rows, err := r.DB.Query(query)
+	rows, err := r.reader(ctx).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
 		data := new(model.Employee)
-		err := rows.Scan(
-			&data.IdEmployee,
-			&data.FirstName,
-			&data.LastName,
-			&data.Email,
-			&data.Phone,
-			&data.HireDate,
-			&data.Salary,
-		)
+		err := scanEmployee(rows, data)
 		if err != nil {
 			logrus.Error(err)
 			return nil, err
@@ -84,19 +177,11 @@ func (r repositories) GetEmployee() (rs []*model.Employee, err error) {
 	return res, nil
 }
 
-func (r repositories) GetEmployeeById(id string) (rs *model.Employee, err error) {
+func (r repositories) GetEmployeeById(ctx context.Context, id string) (rs *model.Employee, err error) {
 	query := config.GetEmployeeById()
 	data := &model.Employee{}
 
-	err = r.DB.QueryRowContext(context.Background(), query, id).Scan(
-		&data.IdEmployee,
-		&data.FirstName,
-		&data.LastName,
-		&data.Email,
-		&data.Phone,
-		&data.HireDate,
-		&data.Salary,
-	)
+	err = scanEmployee(r.reader(ctx).QueryRowContext(ctx, query, id), data)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -111,9 +196,8 @@ func (r repositories) GetEmployeeById(id string) (rs *model.Employee, err error)
 	return data, nil
 }
 
-func (r repositories) InsertEmployee(employee *model.Employee) (rs string, err error) {
+func (r repositories) InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
 	queryInsert := config.InsertEmployee()
-	ctx := context.Background()
 	// check if the employee with same ID or email already exists
 	exists, err := r.employeeExists(ctx, &employee.IdEmployee, &employee.Email)
 	if err != nil {
@@ -123,11 +207,26 @@ func (r repositories) InsertEmployee(employee *model.Employee) (rs string, err e
 	if exists {
 		return "Employee already exists", errors.New("employee already exists")
 	}
+	if err = r.checkDepartment(ctx, employee.DepartmentId); err != nil {
+		return "", err
+	}
 
-	_, err = r.DB.ExecContext(
-		ctx, queryInsert,
-		employee.IdEmployee, employee.FirstName, employee.LastName,
-		employee.Email, employee.Phone, &employee.HireDate, &employee.Salary)
+	err = r.writeEmployee(ctx, model.EventEmployeeCreated, employee.IdEmployee, func(tx *sql.Tx, _ *model.Employee) error {
+		if err := checkManager(ctx, tx, employee.IdEmployee, employee.ManagerId, false); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(
+			ctx, queryInsert,
+			employee.IdEmployee, employee.FirstName, employee.LastName,
+			employee.Email, employee.Phone, &employee.HireDate, employee.Salary, employee.Currency, employee.DepartmentId, employee.ManagerId, employee.PositionId, employee.Status)
+		if err != nil {
+			return err
+		}
+		if err := insertCompensation(ctx, tx, employee.Compensation); err != nil {
+			return err
+		}
+		return insertSalaryOverride(ctx, tx, employee.SalaryOverride)
+	})
 	switch {
 	case errors.Is(err, sql.ErrConnDone):
 		logrus.Errorf("Error inserting employee: %v", err)
@@ -141,8 +240,7 @@ func (r repositories) InsertEmployee(employee *model.Employee) (rs string, err e
 	return "Successfully inserted a new employee", nil
 }
 
-func (r repositories) UpdateEmployee(employee *model.Employee) (rs string, err error) {
-	ctx := context.Background()
+func (r repositories) UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
 	exists, err := r.employeeExists(ctx, &employee.IdEmployee, &employee.Email)
 	if err != nil {
 		logrus.Errorf("Error checking employee existence: %v", err)
@@ -151,10 +249,28 @@ func (r repositories) UpdateEmployee(employee *model.Employee) (rs string, err e
 	if !exists {
 		return "Employee doesn't exists", errors.New("employee doesn't exists")
 	}
+	if err = r.checkDepartment(ctx, employee.DepartmentId); err != nil {
+		return "", err
+	}
 	query := config.EditEmployee()
-	_, err = r.DB.ExecContext(ctx, query,
-		employee.FirstName, employee.LastName, employee.Email,
-		employee.Phone, &employee.HireDate, &employee.Salary, employee.IdEmployee)
+	err = r.writeEmployee(ctx, model.EventEmployeeUpdated, employee.IdEmployee, func(tx *sql.Tx, before *model.Employee) error {
+		if err := checkManager(ctx, tx, employee.IdEmployee, employee.ManagerId, true); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, query,
+			employee.FirstName, employee.LastName, employee.Email,
+			employee.Phone, &employee.HireDate, employee.Salary, employee.Currency, employee.DepartmentId, employee.ManagerId, employee.PositionId, employee.IdEmployee)
+		if err != nil {
+			return err
+		}
+		// The salary is read from the history, so it only gets an entry when it changes.
+		if before.Salary.Cmp(employee.Salary.Decimal) != 0 || before.Currency != employee.Currency {
+			if err := insertCompensation(ctx, tx, employee.Compensation); err != nil {
+				return err
+			}
+		}
+		return insertSalaryOverride(ctx, tx, employee.SalaryOverride)
+	})
 	switch {
 	case err != nil:
 		logrus.Errorf("Error on database %v", err)
@@ -165,8 +281,10 @@ func (r repositories) UpdateEmployee(employee *model.Employee) (rs string, err e
 	return "Employee was edited", err
 }
 
-func (r repositories) DeleteEmployee(employeeId string) (rs string, err error) {
-	ctx := context.Background()
+// DeleteEmployee deletes the employee, cascading to their documents and
+// photo. Once committed, removed is given the rows that went with it so the
+// caller can remove their stored objects.
+func (r repositories) DeleteEmployee(ctx context.Context, employeeId string, removed func(documents []*model.Document, photo *model.Photo)) (rs string, err error) {
 	exists, err := r.employeeExists(ctx, &employeeId, nil)
 	if err != nil {
 		logrus.Errorf("Error checking employee existence: %v", err)
@@ -176,17 +294,47 @@ func (r repositories) DeleteEmployee(employeeId string) (rs string, err error) {
 		return "Employee doesn't exists", errors.New("employee doesn't exists")
 	}
 	query := config.DeleteEmployee()
-	_, err = r.DB.ExecContext(ctx, query, employeeId)
+	var documents []*model.Document
+	var photo *model.Photo
+	err = r.writeEmployee(ctx, model.EventEmployeeDeleted, employeeId, func(tx *sql.Tx, _ *model.Employee) error {
+		// the employee row is locked, so no document or photo can be added
+		// between reading them here and the delete cascading them away
+		var err error
+		if documents, err = employeeDocuments(ctx, tx, employeeId); err != nil {
+			return err
+		}
+		photo = &model.Photo{}
+		switch err = scanPhoto(tx.QueryRowContext(ctx, config.GetPhoto(), employeeId), photo); {
+		case errors.Is(err, sql.ErrNoRows):
+			photo = nil
+		case err != nil:
+			return err
+		}
+		_, err = tx.ExecContext(ctx, query, employeeId)
+		return err
+	})
 	switch {
+	case isManagerReference(err):
+		return "Employee still has reports", model.ErrEmployeeHasReports
 	case err != nil:
 		logrus.Errorf("Error on database %v", err)
 		return "", err
 	default:
 		logrus.Infof("Employee was deleted")
 	}
+	if removed != nil {
+		removed(documents, photo)
+	}
 	return "Employee was deleted", err
 }
 
+// isManagerReference reports whether err is the foreign key of another
+// employee's manager_id refusing to let their manager be deleted.
+func isManagerReference(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1451 && strings.Contains(mysqlErr.Message, "fk_employee_manager")
+}
+
 func (r repositories) employeeExists(ctx context.Context, idEmployee, email *string) (bool, error) {
 	var count int
 	query := config.CountEmployee()
@@ -196,3 +344,21 @@ func (r repositories) employeeExists(ctx context.Context, idEmployee, email *str
 	}
 	return count > 0, nil
 }
+
+// checkDepartment enforces that an employee only references an existing
+// department. An empty id leaves the employee unassigned.
+func (r repositories) checkDepartment(ctx context.Context, departmentId string) error {
+	if departmentId == "" {
+		return nil
+	}
+	var count int
+	err := r.DB.QueryRowContext(ctx, config.CountDepartment(), departmentId).Scan(&count)
+	if err != nil {
+		logrus.Errorf("Error checking department existence: %v", err)
+		return err
+	}
+	if count == 0 {
+		return model.ErrDepartmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/cache"
+	"employee-golang/config"
+	"employee-golang/model"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	employeeListCacheKey = "employee:list"
+	employeeIdCachePfx   = "employee:id:"
+
+	// employeeFetchTimeout bounds a shared database call, which outlives the
+	// request that started it.
+	employeeFetchTimeout = 10 * time.Second
+)
+
+// EmployeeCache holds cached employee reads together with the state that
+// keeps them consistent with writes: concurrent misses on the same key share
+// one database call through group, and generation counts invalidations, so
+// a fetch that overlapped one does not cache its result, which may predate
+// the write. Every service must decorate its repository with the same
+// EmployeeCache for a write through one to stop a stale fetch of another.
+type EmployeeCache struct {
+	store      cache.Store
+	group      singleflight.Group
+	generation atomic.Uint64
+}
+
+var (
+	employeeCache     *EmployeeCache
+	employeeCacheOnce sync.Once
+)
+
+func NewEmployeeCache(store cache.Store) *EmployeeCache {
+	return &EmployeeCache{store: store}
+}
+
+// SharedEmployeeCache is the cache of this process, over the store selected
+// by configuration, shared by every service that reads or writes employees.
+func SharedEmployeeCache() *EmployeeCache {
+	employeeCacheOnce.Do(func() {
+		switch config.GetCacheBackend() {
+		case "redis":
+			employeeCache = NewEmployeeCache(cache.NewRedisStore(config.GetRedisAddr(), config.GetRedisPassword(), config.GetRedisDB(), config.GetRedisPrefix()))
+		default:
+			employeeCache = NewEmployeeCache(cache.NewLRU(config.GetCacheSize()))
+		}
+	})
+	return employeeCache
+}
+
+// cachedRepositories decorates IEmployeeRepositories with a read-through
+// cache. Reads by id and the list query are cached for ttl, and every
+// successful write invalidates the entries it may have changed.
+type cachedRepositories struct {
+	next  IEmployeeRepositories
+	cache *EmployeeCache
+	ttl   time.Duration
+}
+
+func NewCachedEmployeeRepositories(next IEmployeeRepositories, cache *EmployeeCache, ttl time.Duration) IEmployeeRepositories {
+	return &cachedRepositories{
+		next:  next,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (r *cachedRepositories) GetEmployee(ctx context.Context) (rs []*model.Employee, err error) {
+	err = r.load(ctx, employeeListCacheKey, &rs, func(ctx context.Context) (interface{}, error) {
+		return r.next.GetEmployee(ctx)
+	})
+	return rs, err
+}
+
+func (r *cachedRepositories) GetEmployeeById(ctx context.Context, id string) (rs *model.Employee, err error) {
+	err = r.load(ctx, employeeIdCachePfx+id, &rs, func(ctx context.Context) (interface{}, error) {
+		return r.next.GetEmployeeById(ctx, id)
+	})
+	return rs, err
+}
+
+func (r *cachedRepositories) InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	rs, err = r.next.InsertEmployee(ctx, employee)
+	if err == nil {
+		r.invalidate(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	rs, err = r.next.UpdateEmployee(ctx, employee)
+	if err == nil {
+		r.invalidate(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) DeleteEmployee(ctx context.Context, id string, removed func(documents []*model.Document, photo *model.Photo)) (rs string, err error) {
+	rs, err = r.next.DeleteEmployee(ctx, id, removed)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) GetDirectReports(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.next.GetDirectReports(ctx, id)
+}
+
+func (r *cachedRepositories) GetReportingChain(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.next.GetReportingChain(ctx, id)
+}
+
+func (r *cachedRepositories) GetSubordinates(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.next.GetSubordinates(ctx, id)
+}
+
+func (r *cachedRepositories) GetEmployeesByStatus(ctx context.Context, statuses []string) (rs []*model.Employee, err error) {
+	return r.next.GetEmployeesByStatus(ctx, statuses)
+}
+
+func (r *cachedRepositories) GetEmployeesByIds(ctx context.Context, ids []string) (rs []*model.Employee, err error) {
+	return r.next.GetEmployeesByIds(ctx, ids)
+}
+
+func (r *cachedRepositories) GetEmployeesByManagers(ctx context.Context, managerIds []string) (rs []*model.Employee, err error) {
+	return r.next.GetEmployeesByManagers(ctx, managerIds)
+}
+
+func (r *cachedRepositories) ChangeEmployeeStatus(ctx context.Context, employee *model.Employee, change *model.StatusChange) (rs string, err error) {
+	rs, err = r.next.ChangeEmployeeStatus(ctx, employee, change)
+	if err == nil {
+		r.invalidate(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) ScheduleCompensation(ctx context.Context, compensation *model.Compensation, override *model.SalaryOverride) (rs string, err error) {
+	rs, err = r.next.ScheduleCompensation(ctx, compensation, override)
+	if err == nil {
+		r.invalidate(ctx, compensation.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) InsertStatusChange(ctx context.Context, change *model.StatusChange) error {
+	return r.next.InsertStatusChange(ctx, change)
+}
+
+func (r *cachedRepositories) GetStatusHistory(ctx context.Context, id string) (rs []*model.StatusChange, err error) {
+	return r.next.GetStatusHistory(ctx, id)
+}
+
+func (r *cachedRepositories) GetPhoto(ctx context.Context, id string) (rs *model.Photo, err error) {
+	return r.next.GetPhoto(ctx, id)
+}
+
+// SavePhoto and DeletePhoto invalidate the employee as its photoUrl changes.
+func (r *cachedRepositories) SavePhoto(ctx context.Context, photo *model.Photo) (rs string, err error) {
+	rs, err = r.next.SavePhoto(ctx, photo)
+	if err == nil {
+		r.invalidate(ctx, photo.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) DeletePhoto(ctx context.Context, id string) (rs string, err error) {
+	rs, err = r.next.DeletePhoto(ctx, id)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+	return rs, err
+}
+
+func (r *cachedRepositories) SearchEmployees(ctx context.Context, query string, limit int) (rs []*model.SearchResult, err error) {
+	return r.next.SearchEmployees(ctx, query, limit)
+}
+
+func (r *cachedRepositories) HasEmployeeFullText(ctx context.Context) (bool, error) {
+	return r.next.HasEmployeeFullText(ctx)
+}
+
+// load fills dst from the cache, or from fetch on a miss. Cache failures are
+// logged and fall through to the database rather than failing the read.
+// fetch runs detached from the caller's cancellation, as every concurrent
+// caller of the key waits on it; each caller still stops waiting when its
+// own context ends.
+func (r *cachedRepositories) load(ctx context.Context, key string, dst interface{}, fetch func(context.Context) (interface{}, error)) error {
+	b, ok, err := r.cache.store.Get(ctx, key)
+	if err != nil {
+		logrus.Warnf("cache get %s: %s", key, err)
+	}
+	if ok && json.Unmarshal(b, dst) == nil {
+		return nil
+	}
+
+	ch := r.cache.group.DoChan(key, func() (interface{}, error) {
+		generation := r.cache.generation.Load()
+		fetchCtx, cancel := context.WithTimeout(detachedContext{ctx}, employeeFetchTimeout)
+		defer cancel()
+		res, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(res)
+		if err != nil {
+			return nil, err
+		}
+		if r.cache.generation.Load() != generation {
+			return b, nil
+		}
+		if err := r.cache.store.Set(fetchCtx, key, b, r.ttl); err != nil {
+			logrus.Warnf("cache set %s: %s", key, err)
+		}
+		if r.cache.generation.Load() != generation {
+			// invalidated while storing; the delete may have run first
+			if err := r.cache.store.Delete(fetchCtx, key); err != nil {
+				logrus.Warnf("cache invalidate %s: %s", key, err)
+			}
+		}
+		return b, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return res.Err
+		}
+		return json.Unmarshal(res.Val.([]byte), dst)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *cachedRepositories) invalidate(ctx context.Context, id string) {
+	r.cache.generation.Add(1)
+	keys := []string{employeeListCacheKey, employeeIdCachePfx + id}
+	for _, key := range keys {
+		r.cache.group.Forget(key)
+	}
+	if err := r.cache.store.Delete(ctx, keys...); err != nil {
+		logrus.Warnf("cache invalidate %v: %s", keys, err)
+	}
+}
+
+// detachedContext keeps the values of a context, such as the client replica
+// reads are pinned for, without its deadline and cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
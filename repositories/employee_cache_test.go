@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/cache"
+	"employee-golang/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingRepositories struct {
+	IEmployeeRepositories
+	gets  int32
+	delay time.Duration
+}
+
+func (r *countingRepositories) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	atomic.AddInt32(&r.gets, 1)
+	time.Sleep(r.delay)
+	return &model.Employee{IdEmployee: id, FirstName: "John"}, nil
+}
+
+func (r *countingRepositories) UpdateEmployee(_ context.Context, _ *model.Employee) (string, error) {
+	return "Employee was edited", nil
+}
+
+func Test_cachedRepositories_GetEmployeeById(t *testing.T) {
+	next := &countingRepositories{delay: 20 * time.Millisecond}
+	repo := NewCachedEmployeeRepositories(next, NewEmployeeCache(cache.NewLRU(10)), time.Minute)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rs, err := repo.GetEmployeeById(ctx, "1"); err != nil || rs.FirstName != "John" {
+				t.Errorf("GetEmployeeById() = %v, %v", rs, err)
+			}
+		}()
+	}
+	wg.Wait()
+	_, _ = repo.GetEmployeeById(ctx, "1")
+	if got := atomic.LoadInt32(&next.gets); got != 1 {
+		t.Errorf("repository called %d times, want 1", got)
+	}
+
+	if _, err := repo.UpdateEmployee(ctx, &model.Employee{IdEmployee: "1"}); err != nil {
+		t.Fatalf("UpdateEmployee() error = %v", err)
+	}
+	_, _ = repo.GetEmployeeById(ctx, "1")
+	if atomic.LoadInt32(&next.gets) != 2 {
+		t.Errorf("update did not invalidate the cached employee")
+	}
+}
+
+// gatedRepositories answers GetEmployeeById once release is closed, with the
+// name current at that time.
+type gatedRepositories struct {
+	IEmployeeRepositories
+	started chan struct{}
+	release chan struct{}
+	name    atomic.Value
+}
+
+func (r *gatedRepositories) GetEmployeeById(ctx context.Context, id string) (*model.Employee, error) {
+	name := r.name.Load().(string)
+	r.started <- struct{}{}
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &model.Employee{IdEmployee: id, FirstName: name}, nil
+}
+
+func (r *gatedRepositories) UpdateEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	r.name.Store(employee.FirstName)
+	return "Employee was edited", nil
+}
+
+func Test_cachedRepositories_load_detached(t *testing.T) {
+	next := &gatedRepositories{started: make(chan struct{}, 2), release: make(chan struct{})}
+	next.name.Store("John")
+	repo := NewCachedEmployeeRepositories(next, NewEmployeeCache(cache.NewLRU(10)), time.Minute)
+
+	first, cancel := context.WithCancel(context.Background())
+	errFirst := make(chan error)
+	go func() {
+		_, err := repo.GetEmployeeById(first, "1")
+		errFirst <- err
+	}()
+	<-next.started
+	waiter := make(chan *model.Employee)
+	go func() {
+		rs, _ := repo.GetEmployeeById(context.Background(), "1")
+		waiter <- rs
+	}()
+	time.Sleep(20 * time.Millisecond) // let the waiter join the fetch
+	cancel()
+	if err := <-errFirst; err != context.Canceled {
+		t.Errorf("cancelled caller error = %v", err)
+	}
+
+	// a write lands while the fetch that read the old row is in flight
+	if _, err := repo.UpdateEmployee(context.Background(), &model.Employee{IdEmployee: "1", FirstName: "Jack"}); err != nil {
+		t.Fatal(err)
+	}
+	close(next.release)
+	if rs := <-waiter; rs == nil || rs.FirstName != "John" {
+		t.Fatalf("waiter got %v, want the shared fetch to survive the first caller", rs)
+	}
+	if rs, err := repo.GetEmployeeById(context.Background(), "1"); err != nil || rs.FirstName != "Jack" {
+		t.Errorf("GetEmployeeById() = %v, %v, want the row written after the stale fetch", rs, err)
+	}
+}
+
+func Test_cachedRepositories_load_sharedCache(t *testing.T) {
+	next := &gatedRepositories{started: make(chan struct{}, 1), release: make(chan struct{})}
+	next.name.Store("John")
+	shared := NewEmployeeCache(cache.NewLRU(10))
+	reader := NewCachedEmployeeRepositories(next, shared, time.Minute)
+	writer := NewCachedEmployeeRepositories(next, shared, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = reader.GetEmployeeById(context.Background(), "1")
+		close(done)
+	}()
+	<-next.started
+
+	// a write through another service lands while the reader's fetch of
+	// the old row is in flight
+	if _, err := writer.UpdateEmployee(context.Background(), &model.Employee{IdEmployee: "1", FirstName: "Jack"}); err != nil {
+		t.Fatal(err)
+	}
+	close(next.release)
+	<-done
+	go func() { <-next.started }()
+	if rs, err := reader.GetEmployeeById(context.Background(), "1"); err != nil || rs.FirstName != "Jack" {
+		t.Errorf("GetEmployeeById() = %v, %v, want the row written after the stale fetch", rs, err)
+	}
+}
@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/search"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// SearchEmployees uses the employee FULLTEXT index.
+func (r repositories) SearchEmployees(ctx context.Context, query string, limit int) (rs []*model.SearchResult, err error) {
+	res := make([]*model.SearchResult, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.SearchEmployees(), query, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := &model.SearchResult{Employee: new(model.Employee)}
+		if err := scanEmployee(scoredRow{rows, &data.Score}, data.Employee); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+// HasEmployeeFullText reports whether the employee table has a FULLTEXT
+// index to search with.
+func (r repositories) HasEmployeeFullText(ctx context.Context) (bool, error) {
+	var count int
+	if err := r.DB.QueryRowContext(ctx, config.CountEmployeeFullText()).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// scoredRow reads a relevance column selected after the employee columns.
+type scoredRow struct {
+	scanner
+	score *float64
+}
+
+func (r scoredRow) Scan(dest ...interface{}) error {
+	return r.scanner.Scan(append(dest, r.score)...)
+}
+
+// EmployeeSearchFields is the searchable text of an employee. Names weigh
+// more than contact details so a name match outranks an email match.
+func EmployeeSearchFields(e *model.Employee) []search.Field {
+	return []search.Field{
+		{Name: "firstName", Value: e.FirstName, Weight: 1.5},
+		{Name: "lastName", Value: e.LastName, Weight: 1.5},
+		{Name: "email", Value: e.Email, Weight: 1},
+		{Name: "phone", Value: e.Phone, Weight: 1, Digits: true},
+	}
+}
+
+// EmployeeIndex is the in-process search index over employees, used when
+// the database has no FULLTEXT index. Writes through
+// NewIndexedEmployeeRepositories keep it current; Refresh reloads it
+// periodically to pick up writes made elsewhere.
+type EmployeeIndex struct {
+	mu        sync.RWMutex
+	index     *search.Index
+	employees map[string]*model.Employee
+	loadedAt  time.Time
+	loading   sync.Mutex
+}
+
+var (
+	employeeIndex     *EmployeeIndex
+	employeeIndexOnce sync.Once
+)
+
+func NewEmployeeIndex() *EmployeeIndex {
+	return &EmployeeIndex{
+		index:     search.NewIndex(),
+		employees: make(map[string]*model.Employee),
+	}
+}
+
+// SharedEmployeeIndex is the index of this process, shared by every
+// service that searches or writes employees.
+func SharedEmployeeIndex() *EmployeeIndex {
+	employeeIndexOnce.Do(func() {
+		employeeIndex = NewEmployeeIndex()
+	})
+	return employeeIndex
+}
+
+// Refresh replaces the index with the employees returned by load when it
+// was never loaded or was loaded more than maxAge ago. Concurrent callers
+// share one load.
+func (x *EmployeeIndex) Refresh(maxAge time.Duration, load func() ([]*model.Employee, error)) error {
+	if x.fresh(maxAge) {
+		return nil
+	}
+	x.loading.Lock()
+	defer x.loading.Unlock()
+	if x.fresh(maxAge) {
+		return nil
+	}
+
+	employees, err := load()
+	if err != nil {
+		return err
+	}
+	index := search.NewIndex()
+	byId := make(map[string]*model.Employee, len(employees))
+	for _, e := range employees {
+		index.Put(search.Document{ID: e.IdEmployee, Fields: EmployeeSearchFields(e)})
+		byId[e.IdEmployee] = e
+	}
+	x.mu.Lock()
+	x.index, x.employees, x.loadedAt = index, byId, time.Now()
+	x.mu.Unlock()
+	return nil
+}
+
+func (x *EmployeeIndex) fresh(maxAge time.Duration) bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return !x.loadedAt.IsZero() && time.Since(x.loadedAt) < maxAge
+}
+
+// Put indexes e, replacing its previous version. Before the first load it
+// does nothing, as the load will include e.
+func (x *EmployeeIndex) Put(e *model.Employee) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.loadedAt.IsZero() {
+		return
+	}
+	x.index.Put(search.Document{ID: e.IdEmployee, Fields: EmployeeSearchFields(e)})
+	x.employees[e.IdEmployee] = e
+}
+
+func (x *EmployeeIndex) Delete(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.index.Delete(id)
+	delete(x.employees, id)
+}
+
+func (x *EmployeeIndex) Search(query string, limit int) []*model.SearchResult {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	res := make([]*model.SearchResult, 0)
+	for _, hit := range x.index.Search(query, limit) {
+		res = append(res, &model.SearchResult{Employee: x.employees[hit.ID], Score: hit.Score})
+	}
+	return res
+}
+
+// indexedRepositories decorates IEmployeeRepositories, updating the
+// in-process index after every successful write that changes an employee.
+type indexedRepositories struct {
+	IEmployeeRepositories
+	index *EmployeeIndex
+}
+
+func NewIndexedEmployeeRepositories(next IEmployeeRepositories, index *EmployeeIndex) IEmployeeRepositories {
+	return &indexedRepositories{
+		IEmployeeRepositories: next,
+		index:                 index,
+	}
+}
+
+func (r *indexedRepositories) InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.InsertEmployee(ctx, employee)
+	if err == nil {
+		r.reindex(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.UpdateEmployee(ctx, employee)
+	if err == nil {
+		r.reindex(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) DeleteEmployee(ctx context.Context, id string, removed func(documents []*model.Document, photo *model.Photo)) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.DeleteEmployee(ctx, id, removed)
+	if err == nil {
+		r.index.Delete(id)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) ChangeEmployeeStatus(ctx context.Context, employee *model.Employee, change *model.StatusChange) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.ChangeEmployeeStatus(ctx, employee, change)
+	if err == nil {
+		r.reindex(ctx, employee.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) ScheduleCompensation(ctx context.Context, compensation *model.Compensation, override *model.SalaryOverride) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.ScheduleCompensation(ctx, compensation, override)
+	if err == nil {
+		r.reindex(ctx, compensation.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) SavePhoto(ctx context.Context, photo *model.Photo) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.SavePhoto(ctx, photo)
+	if err == nil {
+		r.reindex(ctx, photo.IdEmployee)
+	}
+	return rs, err
+}
+
+func (r *indexedRepositories) DeletePhoto(ctx context.Context, id string) (rs string, err error) {
+	rs, err = r.IEmployeeRepositories.DeletePhoto(ctx, id)
+	if err == nil {
+		r.reindex(ctx, id)
+	}
+	return rs, err
+}
+
+// reindex reads the stored employee back, so the index holds what the
+// database derived, such as the current salary and photo URL. A failure
+// leaves the old entry until the next refresh.
+func (r *indexedRepositories) reindex(ctx context.Context, id string) {
+	employee, err := r.IEmployeeRepositories.GetEmployeeById(ctx, id)
+	if err != nil {
+		logrus.Warnf("search index update for employee %s: %s", id, err)
+		return
+	}
+	r.index.Put(employee)
+}
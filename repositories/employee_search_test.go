@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test_repositories_SearchEmployees(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.SearchEmployees()).
+		WithArgs("jon smith", "jon smith", 5).
+		WillReturnRows(sqlmock.NewRows(append(employeeColumnNames, "score")).
+			AddRow("1", "John", "Smith", "john@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", "", 3.25))
+
+	r := repositories{DB: db}
+	rs, err := r.SearchEmployees(context.Background(), "jon smith", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].Employee.LastName != "Smith" || rs[0].Score != 3.25 {
+		t.Errorf("SearchEmployees() = %+v", rs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
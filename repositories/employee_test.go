@@ -3,11 +3,16 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"employee-golang/config"
 	"employee-golang/model"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -33,7 +38,9 @@ func Test_repositories_GetEmployee(t *testing.T) {
 					Email:      "john.doe@example.com",
 					Phone:      "123456789",
 					HireDate:   "2023-01-01",
-					Salary:     50000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("50000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 				&model.Employee{
 					IdEmployee: "2",
@@ -42,7 +49,10 @@ func Test_repositories_GetEmployee(t *testing.T) {
 					Email:      "jane.doe@example.com",
 					Phone:      "987654321",
 					HireDate:   "2023-01-02",
-					Salary:     60000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("60000")),
+					Currency:   "USD",
+					Status:     "active",
+					PhotoUrl:   "/api/v1/employees/2/photo?v=9f86d081884c7d65",
 				},
 			},
 			wantErr: false,
@@ -67,18 +77,18 @@ func Test_repositories_GetEmployee(t *testing.T) {
 	defer db.Close()
 
 	mock.
-		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee").
+		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce((select c.salary from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), salary, 0.0), coalesce((select c.currency from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), currency, ''), coalesce(department_id, ''), coalesce(manager_id, ''), coalesce(position_id, ''), status, coalesce(termination_date, ''), coalesce(termination_reason, ''), coalesce((select ph.checksum from employee_photo ph where ph.employee_id = employee.employee_id), '') from employee").
 		WillReturnRows(
-			sqlmock.NewRows([]string{"employee_id", "first_name", "last_name", "email", "phone", "hire_date", "salary"}).
-				AddRow(1, `John`, `Doe`, `john.doe@example.com`, `123456789`, `2023-01-01`, 50000.0).
-				AddRow(2, `Jane`, `Doe`, `jane.doe@example.com`, `987654321`, `2023-01-02`, 60000.0))
+			sqlmock.NewRows([]string{"employee_id", "first_name", "last_name", "email", "phone", "hire_date", "salary", "currency", "department_id", "manager_id", "position_id", "status", "termination_date", "termination_reason", "photo_checksum"}).
+				AddRow(1, `John`, `Doe`, `john.doe@example.com`, `123456789`, `2023-01-01`, `50000`, `USD`, ``, ``, ``, `active`, ``, ``, ``).
+				AddRow(2, `Jane`, `Doe`, `jane.doe@example.com`, `987654321`, `2023-01-02`, `60000`, `USD`, ``, ``, ``, `active`, ``, ``, `9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08`))
 
 	mock.
-		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee").
+		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce((select c.salary from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), salary, 0.0), coalesce((select c.currency from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), currency, ''), coalesce(department_id, ''), coalesce(manager_id, ''), coalesce(position_id, ''), status, coalesce(termination_date, ''), coalesce(termination_reason, ''), coalesce((select ph.checksum from employee_photo ph where ph.employee_id = employee.employee_id), '') from employee").
 		WillReturnError(tests[1].expectedErr)
 
 	mock.
-		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee").
+		ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce((select c.salary from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), salary, 0.0), coalesce((select c.currency from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), currency, ''), coalesce(department_id, ''), coalesce(manager_id, ''), coalesce(position_id, ''), status, coalesce(termination_date, ''), coalesce(termination_reason, ''), coalesce((select ph.checksum from employee_photo ph where ph.employee_id = employee.employee_id), '') from employee").
 		WillReturnError(tests[2].expectedErr)
 
 	for _, tt := range tests {
@@ -87,7 +97,7 @@ func Test_repositories_GetEmployee(t *testing.T) {
 			c := repositories{
 				DB: tt.fields.DB,
 			}
-			gotRs, err := c.GetEmployee()
+			gotRs, err := c.GetEmployee(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetEmployee() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -126,7 +136,9 @@ func Test_repositories_GetEmployeeById(t *testing.T) {
 				Email:      "john.doe@example.com",
 				Phone:      "123456789",
 				HireDate:   "2023-01-01",
-				Salary:     50000.0,
+				Salary:     model.NewMoney(decimal.RequireFromString("50000")),
+				Currency:   "USD",
+				Status:     "active",
 			},
 			wantErr: false,
 		},
@@ -159,14 +171,14 @@ func Test_repositories_GetEmployeeById(t *testing.T) {
 
 	for _, tt := range tests {
 		if !tt.wantErr {
-			rows := sqlmock.NewRows([]string{"employee_id", "first_name", "last_name", "email", "phone", "hire_date", "salary"}).
-				AddRow(tt.wantRs.IdEmployee, tt.wantRs.FirstName, tt.wantRs.LastName, tt.wantRs.Email, tt.wantRs.Phone, tt.wantRs.HireDate, tt.wantRs.Salary)
+			rows := sqlmock.NewRows([]string{"employee_id", "first_name", "last_name", "email", "phone", "hire_date", "salary", "currency", "department_id", "manager_id", "position_id", "status", "termination_date", "termination_reason", "photo_checksum"}).
+				AddRow(tt.wantRs.IdEmployee, tt.wantRs.FirstName, tt.wantRs.LastName, tt.wantRs.Email, tt.wantRs.Phone, tt.wantRs.HireDate, tt.wantRs.Salary.String(), tt.wantRs.Currency, tt.wantRs.DepartmentId, tt.wantRs.ManagerId, tt.wantRs.PositionId, tt.wantRs.Status, tt.wantRs.TerminationDate, tt.wantRs.TerminationReason, "")
 			mock.
-				ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee where employee_id = ?").
+				ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce((select c.salary from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), salary, 0.0), coalesce((select c.currency from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), currency, ''), coalesce(department_id, ''), coalesce(manager_id, ''), coalesce(position_id, ''), status, coalesce(termination_date, ''), coalesce(termination_reason, ''), coalesce((select ph.checksum from employee_photo ph where ph.employee_id = employee.employee_id), '') from employee where employee_id = ?").
 				WithArgs(tt.args.id).
 				WillReturnRows(rows)
 		} else {
-			mock.ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce(salary, 0.0) from employee where employee_id = ?").
+			mock.ExpectQuery("select employee_id, first_name, last_name, email, phone, coalesce(hire_date, ''), coalesce((select c.salary from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), salary, 0.0), coalesce((select c.currency from compensation c where c.employee_id = employee.employee_id and c.effective_date <= current_date order by c.effective_date desc, c.id desc limit 1), currency, ''), coalesce(department_id, ''), coalesce(manager_id, ''), coalesce(position_id, ''), status, coalesce(termination_date, ''), coalesce(termination_reason, ''), coalesce((select ph.checksum from employee_photo ph where ph.employee_id = employee.employee_id), '') from employee where employee_id = ?").
 				WithArgs(tt.args.id).
 				WillReturnError(tt.expErr)
 		}
@@ -178,7 +190,7 @@ func Test_repositories_GetEmployeeById(t *testing.T) {
 			c := repositories{
 				DB: tt.fields.DB,
 			}
-			gotRs, err := c.GetEmployeeById(tt.args.id)
+			gotRs, err := c.GetEmployeeById(context.Background(), tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetEmployeeById() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -236,7 +248,9 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantRs:  "Successfully inserted a new employee",
@@ -252,7 +266,9 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantRs:      "Employee already exists",
@@ -269,7 +285,9 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 					Email:      "syahrido3@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantRs:      "",
@@ -286,7 +304,9 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 					Email:      "syahrido1@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantRs:      "",
@@ -303,7 +323,9 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantRs:      "",
@@ -318,51 +340,39 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 	}
 	defer db.Close()
 
+	insertQuery := "insert into employee (employee_id, first_name, last_name, email, phone, hire_date, salary, currency, department_id, manager_id, position_id, status) value (?, ?, ?, ?, ?, nullif(?,''), ?, ?, nullif(?, ''), nullif(?, ''), nullif(?, ''), ?)"
 	for _, tt := range tests {
-		if !tt.wantErr {
-			mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
-				WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
-				WillReturnRows(sqlmock.NewRows([]string{" count(*)"}).
-					AddRow(0))
-			mock.ExpectExec("insert into employee (employee_id, first_name, last_name, email, phone, hire_date, salary) value (?, ?, ?, ?, ?, nullif(?,''), nullif(?, ''))").
-				WithArgs(
-					tt.args.employee.IdEmployee,
-					tt.args.employee.FirstName,
-					tt.args.employee.LastName,
-					tt.args.employee.Email,
-					tt.args.employee.Phone,
-					tt.args.employee.HireDate,
-					tt.args.employee.Salary).
-				WillReturnResult(sqlmock.NewResult(1, 1))
-		} else {
-			if tt.wantRs == "Employee already exists" {
-				mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
-					WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
-					WillReturnRows(sqlmock.NewRows([]string{" count(*)"}).
-						AddRow(1))
-			} else {
-				if tt.expectedErr.Error() == "error checking employee existence" {
-					// Simulate an error during the employee existence check
-					mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
-						WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
-						WillReturnError(errors.New("error checking employee existence"))
-				}
-				mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
-					WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
-					WillReturnRows(sqlmock.NewRows([]string{" count(*)"}).
-						AddRow(0))
-				mock.ExpectExec("insert into employee (employee_id, first_name, last_name, email, phone, hire_date, salary) value (?, ?, ?, ?, ?, nullif(?,''), nullif(?, ''))").
-					WithArgs(
-						tt.args.employee.IdEmployee,
-						tt.args.employee.FirstName,
-						tt.args.employee.LastName,
-						tt.args.employee.Email,
-						tt.args.employee.Phone,
-						tt.args.employee.HireDate,
-						tt.args.employee.Salary).
-					WillReturnError(tt.expectedErr)
-			}
+		e := tt.args.employee
+		countQuery := mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
+			WithArgs(e.IdEmployee, e.Email)
+		switch {
+		case tt.expectedErr != nil && tt.expectedErr.Error() == "error checking employee existence":
+			// Simulate an error during the employee existence check
+			countQuery.WillReturnError(tt.expectedErr)
+			continue
+		case tt.wantRs == "Employee already exists":
+			countQuery.WillReturnRows(sqlmock.NewRows([]string{" count(*)"}).AddRow(1))
+			continue
 		}
+		countQuery.WillReturnRows(sqlmock.NewRows([]string{" count(*)"}).AddRow(0))
+
+		// the insert and its EmployeeCreated event share a transaction
+		mock.ExpectBegin()
+		insert := mock.ExpectExec(insertQuery).
+			WithArgs(e.IdEmployee, e.FirstName, e.LastName, e.Email, e.Phone, e.HireDate, e.Salary, e.Currency, e.DepartmentId, e.ManagerId, e.PositionId, e.Status)
+		if tt.wantErr {
+			insert.WillReturnError(tt.expectedErr)
+			mock.ExpectRollback()
+			continue
+		}
+		insert.WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(config.GetEmployeeById()).WithArgs(e.IdEmployee).
+			WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+				AddRow(e.IdEmployee, e.FirstName, e.LastName, e.Email, e.Phone, e.HireDate, e.Salary.String(), e.Currency, "", "", "", e.Status, "", "", ""))
+		mock.ExpectExec(config.InsertOutboxEvent()).
+			WithArgs(sqlmock.AnyArg(), model.EventEmployeeCreated, e.IdEmployee, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 	}
 
 	for _, tt := range tests {
@@ -371,7 +381,7 @@ func Test_repositories_InsertEmployee(t *testing.T) {
 			c := repositories{
 				DB: tt.fields.DB,
 			}
-			gotRs, err := c.InsertEmployee(tt.args.employee)
+			gotRs, err := c.InsertEmployee(context.Background(), tt.args.employee)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("InsertEmployee() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -410,7 +420,9 @@ func Test_repositories_EditEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantErr: false,
@@ -426,7 +438,9 @@ func Test_repositories_EditEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantErr:   true,
@@ -443,7 +457,9 @@ func Test_repositories_EditEmployee(t *testing.T) {
 					Email:      "syahrido@gmail.com",
 					Phone:      "3424235",
 					HireDate:   time.Now().String(),
-					Salary:     120000.0,
+					Salary:     model.NewMoney(decimal.RequireFromString("120000")),
+					Currency:   "USD",
+					Status:     "active",
 				},
 			},
 			wantErr:   true,
@@ -459,11 +475,18 @@ func Test_repositories_EditEmployee(t *testing.T) {
 
 	for _, tt := range tests {
 		if !tt.wantErr {
+			e := tt.args.employee
 			mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
 				WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
 				WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
 					AddRow(1))
-			mock.ExpectExec("update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = nullif(?, 0) where employee_id = ?").
+			// the update is recorded as an EmployeeUpdated event with the
+			// row before and after it
+			mock.ExpectBegin()
+			mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs(e.IdEmployee).
+				WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+					AddRow(e.IdEmployee, "Previous", e.LastName, e.Email, e.Phone, e.HireDate, e.Salary.String(), e.Currency, "", "", "", e.Status, "", "", ""))
+			mock.ExpectExec("update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = ?, currency = ?, department_id = nullif(?, ''), manager_id = nullif(?, ''), position_id = nullif(?, '') where employee_id = ?").
 				WithArgs(
 					tt.args.employee.FirstName,
 					tt.args.employee.LastName,
@@ -471,14 +494,25 @@ func Test_repositories_EditEmployee(t *testing.T) {
 					tt.args.employee.Phone,
 					tt.args.employee.HireDate,
 					tt.args.employee.Salary,
+					tt.args.employee.Currency,
+					tt.args.employee.DepartmentId,
+					tt.args.employee.ManagerId,
+					tt.args.employee.PositionId,
 					tt.args.employee.IdEmployee).
 				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(config.GetEmployeeById()).WithArgs(e.IdEmployee).
+				WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+					AddRow(e.IdEmployee, e.FirstName, e.LastName, e.Email, e.Phone, e.HireDate, e.Salary.String(), e.Currency, "", "", "", e.Status, "", "", ""))
+			mock.ExpectExec(config.InsertOutboxEvent()).
+				WithArgs(sqlmock.AnyArg(), model.EventEmployeeUpdated, e.IdEmployee, sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
 		} else {
 			mock.ExpectQuery("select count(*) from employee where employee_id = ? or email = nullif(?, '')").
 				WithArgs(tt.args.employee.IdEmployee, tt.args.employee.Email).
 				WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
 					AddRow(0))
-			mock.ExpectExec("update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = nullif(?, 0) where employee_id = ?").
+			mock.ExpectExec("update employee set first_name = ?, last_name = ?, email = ?, phone = ?, hire_date = nullif(?, ''), salary = ?, currency = ?, department_id = nullif(?, ''), manager_id = nullif(?, ''), position_id = nullif(?, '') where employee_id = ?").
 				WithArgs(
 					tt.args.employee.FirstName,
 					tt.args.employee.LastName,
@@ -486,6 +520,10 @@ func Test_repositories_EditEmployee(t *testing.T) {
 					tt.args.employee.Phone,
 					tt.args.employee.HireDate,
 					tt.args.employee.Salary,
+					tt.args.employee.Currency,
+					tt.args.employee.DepartmentId,
+					tt.args.employee.ManagerId,
+					tt.args.employee.PositionId,
 					tt.args.employee.IdEmployee).
 				WillReturnError(tt.expectErr)
 		}
@@ -497,7 +535,7 @@ func Test_repositories_EditEmployee(t *testing.T) {
 			r := repositories{
 				DB: tt.fields.DB,
 			}
-			gotRs, err := r.UpdateEmployee(tt.args.employee)
+			gotRs, err := r.UpdateEmployee(context.Background(), tt.args.employee)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EditEmployee() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -509,6 +547,151 @@ func Test_repositories_EditEmployee(t *testing.T) {
 	}
 }
 
+func Test_repositories_UpdateEmployee_salaryOverride(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	e := &model.Employee{IdEmployee: "7", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "1",
+		Salary: model.NewMoney(decimal.NewFromInt(90000)), Currency: "USD", PositionId: "P1"}
+	e.SalaryOverride = &model.SalaryOverride{IdEmployee: "7", IdPosition: "P1", Salary: e.Salary, Currency: "USD",
+		MinSalary: model.NewMoney(decimal.NewFromInt(50000)), MaxSalary: model.NewMoney(decimal.NewFromInt(80000)), BandCurrency: "USD", Reason: "retention offer"}
+	mock.ExpectQuery(config.CountEmployee()).WithArgs(e.IdEmployee, e.Email).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs(e.IdEmployee).
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("7", "Jane", "Doe", "jane@example.com", "1", "", "60000", "USD", "", "", "P1", "active", "", "", ""))
+	mock.ExpectExec(config.EditEmployee()).
+		WithArgs("Jane", "Doe", "jane@example.com", "1", "", e.Salary, "USD", "", "", "P1", "7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(config.InsertSalaryOverride()).
+		WithArgs("7", "P1", e.Salary, "USD", decimal.NewFromInt(50000), decimal.NewFromInt(80000), "USD", "retention offer").
+		WillReturnError(errors.New("override insert failed"))
+	mock.ExpectRollback()
+
+	// the salary is not written without the override accepting it
+	r := repositories{DB: db}
+	if _, err := r.UpdateEmployee(context.Background(), e); err == nil {
+		t.Error("UpdateEmployee() error = nil, want the override error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_repositories_UpdateEmployee_compensation(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	e := &model.Employee{IdEmployee: "7", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "1",
+		Salary: model.NewMoney(decimal.NewFromInt(65000)), Currency: "USD"}
+	e.Compensation = &model.Compensation{IdEmployee: "7", Salary: e.Salary, Currency: "USD", EffectiveDate: "2024-03-15", Reason: "salary updated"}
+	mock.ExpectQuery(config.CountEmployee()).WithArgs(e.IdEmployee, e.Email).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs(e.IdEmployee).
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("7", "Jane", "Doe", "jane@example.com", "1", "", "60000", "USD", "", "", "", "active", "", "", ""))
+	mock.ExpectExec(config.EditEmployee()).
+		WithArgs("Jane", "Doe", "jane@example.com", "1", "", e.Salary, "USD", "", "", "", "7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(config.InsertCompensation()).
+		WithArgs("7", e.Salary, "USD", "2024-03-15", "salary updated").
+		WillReturnError(errors.New("compensation insert failed"))
+	mock.ExpectRollback()
+
+	// reads take the salary from the history, so the edit is not kept without it
+	r := repositories{DB: db}
+	if _, err := r.UpdateEmployee(context.Background(), e); err == nil {
+		t.Error("UpdateEmployee() error = nil, want the compensation error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_repositories_DeleteEmployee_hasReports(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.CountEmployee()).WithArgs("1", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("1", "John", "Doe", "john@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", ""))
+	mock.ExpectQuery(config.GetDocuments()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(documentColumnNames))
+	mock.ExpectQuery(config.GetPhoto()).WithArgs("1").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(config.DeleteEmployee()).WithArgs("1").
+		WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails " +
+			"(`employee`.`employee`, CONSTRAINT `fk_employee_manager` FOREIGN KEY (`manager_id`) REFERENCES `employee` (`employee_id`))"})
+	mock.ExpectRollback()
+
+	r := repositories{DB: db}
+	removed := func([]*model.Document, *model.Photo) {
+		t.Error("removed should not be called when the delete fails")
+	}
+	if _, err := r.DeleteEmployee(context.Background(), "1", removed); !errors.Is(err, model.ErrEmployeeHasReports) {
+		t.Errorf("DeleteEmployee() error = %v, want %v", err, model.ErrEmployeeHasReports)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+var documentColumnNames = []string{"id", "employee_id", "file_name", "content_type", "size", "checksum", "storage_key", "created_at"}
+
+func Test_repositories_DeleteEmployee_removed(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.CountEmployee()).WithArgs("1", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("1", "John", "Doe", "john@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", ""))
+	mock.ExpectQuery(config.GetDocuments()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(documentColumnNames).
+			AddRow(4, "1", "contract.pdf", "application/pdf", 8, "abc", "employees/1/4f2a", "2024-01-01 00:00:00"))
+	mock.ExpectQuery(config.GetPhoto()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"employee_id", "content_type", "width", "height", "size", "checksum", "updated_at"}).
+			AddRow("1", "image/png", 64, 64, 100, "def", "2024-01-01 00:00:00"))
+	mock.ExpectExec(config.DeleteEmployee()).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(config.InsertOutboxEvent()).
+		WithArgs(sqlmock.AnyArg(), model.EventEmployeeDeleted, "1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var documents []*model.Document
+	var photo *model.Photo
+	r := repositories{DB: db}
+	if _, err := r.DeleteEmployee(context.Background(), "1", func(d []*model.Document, p *model.Photo) {
+		documents, photo = d, p
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(documents) != 1 || documents[0].StorageKey != "employees/1/4f2a" || photo == nil || photo.Checksum != "def" {
+		t.Errorf("removed got documents %v and photo %v, want the employee's contract and photo", documents, photo)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
 func Test_repositories_employeeExists(t *testing.T) {
 	type fields struct {
 		DB *sql.DB
@@ -567,3 +750,21 @@ func Test_repositories_employeeExists(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForConnection_InvalidSettings(t *testing.T) {
+	viper.Set("datasource.employee.host", "db.internal")
+	viper.Set("datasource.employee.tls.mode", "required")
+	t.Cleanup(func() {
+		viper.Reset()
+		EmployeeDB, EmployeeRouter, errConfiguration = nil, nil, nil
+	})
+	EmployeeDB = nil
+
+	err := WaitForConnection()
+	if err == nil || !strings.Contains(err.Error(), `unsupported datasource tls mode "required"`) {
+		t.Errorf("WaitForConnection() error = %v, want the invalid tls mode", err)
+	}
+	if EmployeeDB != nil {
+		t.Error("opened a connection pool despite the invalid settings")
+	}
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxHierarchyDepth bounds the non-CTE walks the same way the recursive
+// queries are bounded.
+const maxHierarchyDepth = 100
+
+func (r repositories) GetDirectReports(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.queryEmployees(ctx, r.reader(ctx), config.GetDirectReports(), id)
+}
+
+func (r repositories) GetReportingChain(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	return r.reportingChain(ctx, r.reader(ctx), id)
+}
+
+func (r repositories) GetSubordinates(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	db := r.reader(ctx)
+	if config.IsRecursiveCTESupported() {
+		return r.queryEmployees(ctx, db, config.GetSubordinates(), id)
+	}
+
+	res := make([]*model.Employee, 0)
+	level := []string{id}
+	for depth := 0; len(level) > 0 && depth < maxHierarchyDepth; depth++ {
+		var next []string
+		for _, managerId := range level {
+			reports, err := r.queryEmployees(ctx, db, config.GetDirectReports(), managerId)
+			if err != nil {
+				return nil, err
+			}
+			for _, report := range reports {
+				res = append(res, report)
+				next = append(next, report.IdEmployee)
+			}
+		}
+		level = next
+	}
+	return res, nil
+}
+
+// reportingChain returns the managers above id, nearest first.
+func (r repositories) reportingChain(ctx context.Context, db *sql.DB, id string) ([]*model.Employee, error) {
+	if config.IsRecursiveCTESupported() {
+		return r.queryEmployees(ctx, db, config.GetReportingChain(), id)
+	}
+
+	res := make([]*model.Employee, 0)
+	current := &model.Employee{}
+	err := scanEmployee(db.QueryRowContext(ctx, config.GetEmployeeById(), id), current)
+	for depth := 0; err == nil && current.ManagerId != "" && depth < maxHierarchyDepth; depth++ {
+		manager := &model.Employee{}
+		err = scanEmployee(db.QueryRowContext(ctx, config.GetEmployeeById(), current.ManagerId), manager)
+		if err == nil {
+			res = append(res, manager)
+			current = manager
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logrus.Errorf("Error retrieving reporting chain: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkManager rejects a manager that does not exist, is the employee
+// themselves or, when the employee may already have reports, sits below the
+// employee in the hierarchy or has a chain too deep to tell. It runs in the transaction writing the employee
+// and locks the manager's reporting chain, so two concurrent moves cannot
+// each pass the check and form a cycle together.
+func checkManager(ctx context.Context, tx *sql.Tx, id, managerId string, existing bool) error {
+	if managerId == "" {
+		return nil
+	}
+	if managerId == id {
+		return model.ErrManagerCycle
+	}
+	current := managerId
+	for depth := 0; current != "" && depth < maxHierarchyDepth; depth++ {
+		manager := &model.Employee{}
+		err := scanEmployee(tx.QueryRowContext(ctx, config.GetEmployeeByIdForUpdate(), current), manager)
+		switch {
+		case errors.Is(err, sql.ErrNoRows) && current == managerId:
+			return model.ErrManagerNotFound
+		case errors.Is(err, sql.ErrNoRows):
+			return nil
+		case err != nil:
+			logrus.Errorf("Error checking manager: %v", err)
+			return err
+		}
+		if !existing {
+			return nil
+		}
+		if manager.ManagerId == id {
+			return model.ErrManagerCycle
+		}
+		current = manager.ManagerId
+	}
+	if current != "" {
+		return model.ErrHierarchyTooDeep
+	}
+	return nil
+}
+
+func (r repositories) queryEmployees(ctx context.Context, db *sql.DB, query string, args ...any) ([]*model.Employee, error) {
+	res := make([]*model.Employee, 0)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Employee)
+		if err := scanEmployee(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"testing"
+)
+
+var employeeColumnNames = []string{"employee_id", "first_name", "last_name", "email", "phone", "hire_date", "salary", "currency", "department_id", "manager_id", "position_id", "status", "termination_date", "termination_reason", "photo_checksum"}
+
+func Test_checkManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		managerId string
+		chain     [][]driver.Value
+		wantErr   error
+	}{
+		{
+			name:      "self management",
+			id:        "1",
+			managerId: "1",
+			wantErr:   model.ErrManagerCycle,
+		},
+		{
+			name:      "unknown manager",
+			id:        "1",
+			managerId: "9",
+			wantErr:   model.ErrManagerNotFound,
+		},
+		{
+			name:      "manager reports to employee",
+			id:        "1",
+			managerId: "3",
+			chain: [][]driver.Value{
+				{"3", "Jim", "Doe", "jim@example.com", "1", "", "0", "USD", "", "2", "", "active", "", "", ""},
+				{"2", "Jane", "Doe", "jane@example.com", "1", "", "0", "USD", "", "1", "", "active", "", "", ""},
+			},
+			wantErr: model.ErrManagerCycle,
+		},
+		{
+			name:      "chain deeper than the walk",
+			id:        "1",
+			managerId: "m0",
+			chain:     managerChain(maxHierarchyDepth),
+			wantErr:   model.ErrHierarchyTooDeep,
+		},
+		{
+			name:      "valid manager",
+			id:        "3",
+			managerId: "2",
+			chain: [][]driver.Value{
+				{"2", "Jane", "Doe", "jane@example.com", "1", "", "0", "USD", "", "1", "", "active", "", "", ""},
+				{"1", "John", "Doe", "john@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				logrus.Fatal("error creating mock")
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			if tt.id != tt.managerId {
+				// every manager up the chain is read with a lock
+				id := tt.managerId
+				for _, row := range tt.chain {
+					mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs(id).
+						WillReturnRows(sqlmock.NewRows(employeeColumnNames).AddRow(row...))
+					id = row[9].(string)
+				}
+				if len(tt.chain) == 0 {
+					mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs(id).
+						WillReturnRows(sqlmock.NewRows(employeeColumnNames))
+				}
+			}
+
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkManager(context.Background(), tx, tt.id, tt.managerId, true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+// managerChain is n employees m0 to m(n-1), each managed by the next.
+func managerChain(n int) [][]driver.Value {
+	chain := make([][]driver.Value, n)
+	for i := range chain {
+		id, managerId := "m"+strconv.Itoa(i), "m"+strconv.Itoa(i+1)
+		chain[i] = []driver.Value{id, "Jim", "Doe", id + "@example.com", "1", "", "0", "USD", "", managerId, "", "active", "", "", ""}
+	}
+	return chain
+}
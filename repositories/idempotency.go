@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// IIdempotencyStore keeps the first response produced for each idempotency
+// key. Begin claims a key atomically: it either records a new in-progress
+// entry (created is true) that expires after lease, or returns the
+// unexpired entry already stored. Complete stores the response, kept for
+// ttl.
+type IIdempotencyStore interface {
+	Begin(ctx context.Context, key, requestHash string, lease time.Duration) (rs *model.IdempotencyRecord, created bool, err error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+// NewIdempotencyStore builds the store selected by configuration.
+func NewIdempotencyStore() IIdempotencyStore {
+	switch config.GetIdempotencyBackend() {
+	case "database":
+		return &idempotencyRepositories{DB: InitConfiguration().DB}
+	default:
+		return NewMemoryIdempotencyStore()
+	}
+}
+
+type idempotencyRepositories struct {
+	DB  *sql.DB
+	now func() time.Time
+}
+
+func (r idempotencyRepositories) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r idempotencyRepositories) Begin(ctx context.Context, key, requestHash string, lease time.Duration) (rs *model.IdempotencyRecord, created bool, err error) {
+	now := r.clock()
+	_, err = r.DB.ExecContext(ctx, config.DeleteExpiredIdempotencyKey(), key, now.UnixMilli())
+	if err != nil {
+		logrus.Errorf("Error removing expired idempotency key: %v", err)
+		return nil, false, err
+	}
+	expiresAt := now.Add(lease)
+	res, err := r.DB.ExecContext(ctx, config.InsertIdempotencyKey(), key, requestHash, expiresAt.UnixMilli())
+	if err != nil {
+		logrus.Errorf("Error inserting idempotency key: %v", err)
+		return nil, false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+	if affected == 1 {
+		return &model.IdempotencyRecord{Key: key, RequestHash: requestHash, ExpiresAt: expiresAt}, true, nil
+	}
+
+	data := &model.IdempotencyRecord{}
+	var expiresAtMilli int64
+	err = r.DB.QueryRowContext(ctx, config.GetIdempotencyKey(), key).Scan(
+		&data.Key,
+		&data.RequestHash,
+		&data.StatusCode,
+		&data.ContentType,
+		&data.Body,
+		&data.Completed,
+		&expiresAtMilli,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// released between our insert and select; let the caller retry
+		return nil, false, err
+	case err != nil:
+		logrus.Errorf("Error retrieving idempotency key: %v", err)
+		return nil, false, err
+	}
+	data.ExpiresAt = time.UnixMilli(expiresAtMilli)
+	return data, false, nil
+}
+
+func (r idempotencyRepositories) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error {
+	_, err := r.DB.ExecContext(ctx, config.CompleteIdempotencyKey(), statusCode, contentType, body, r.clock().Add(ttl).UnixMilli(), key)
+	if err != nil {
+		logrus.Errorf("Error completing idempotency key: %v", err)
+	}
+	return err
+}
+
+func (r idempotencyRepositories) Release(ctx context.Context, key string) error {
+	_, err := r.DB.ExecContext(ctx, config.DeleteIdempotencyKey(), key)
+	if err != nil {
+		logrus.Errorf("Error releasing idempotency key: %v", err)
+	}
+	return err
+}
+
+// memoryIdempotencyStore is an IIdempotencyStore for single-instance
+// deployments and tests. Expired entries are swept once a minute.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*model.IdempotencyRecord
+	now     func() time.Time
+}
+
+func NewMemoryIdempotencyStore() IIdempotencyStore {
+	s := &memoryIdempotencyStore{
+		records: map[string]*model.IdempotencyRecord{},
+		now:     time.Now,
+	}
+	go func() {
+		for range time.Tick(time.Minute) {
+			s.sweep()
+		}
+	}()
+	return s
+}
+
+func (s *memoryIdempotencyStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, rec := range s.records {
+		if now.After(rec.ExpiresAt) {
+			delete(s.records, k)
+		}
+	}
+}
+
+func (s *memoryIdempotencyStore) Begin(_ context.Context, key, requestHash string, lease time.Duration) (*model.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if rec, ok := s.records[key]; ok && !now.After(rec.ExpiresAt) {
+		cp := *rec
+		return &cp, false, nil
+	}
+	rec := &model.IdempotencyRecord{Key: key, RequestHash: requestHash, ExpiresAt: now.Add(lease)}
+	s.records[key] = rec
+	cp := *rec
+	return &cp, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	rec.StatusCode, rec.ContentType, rec.Body, rec.Completed = statusCode, contentType, body, true
+	rec.ExpiresAt = s.now().Add(ttl)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.records, key)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+	"time"
+)
+
+func Test_idempotencyRepositories_Begin(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	now := time.UnixMilli(1700000000000)
+	r := idempotencyRepositories{DB: db, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	mock.ExpectExec("delete from idempotency_key where idempotency_key = ? and expires_at < ?").
+		WithArgs("key", now.UnixMilli()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("insert ignore into idempotency_key (idempotency_key, request_hash, completed, expires_at) value (?, ?, false, ?)").
+		WithArgs("key", "hash", now.Add(time.Hour).UnixMilli()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rec, created, err := r.Begin(ctx, "key", "hash", time.Hour)
+	if err != nil || !created || rec.RequestHash != "hash" {
+		t.Fatalf("Begin() = %+v, %v, %v", rec, created, err)
+	}
+
+	mock.ExpectExec("delete from idempotency_key where idempotency_key = ? and expires_at < ?").
+		WithArgs("key", now.UnixMilli()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("insert ignore into idempotency_key (idempotency_key, request_hash, completed, expires_at) value (?, ?, false, ?)").
+		WithArgs("key", "hash", now.Add(time.Hour).UnixMilli()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select idempotency_key, request_hash, coalesce(status_code, 0), coalesce(content_type, ''), response_body, completed, expires_at from idempotency_key where idempotency_key = ?").
+		WithArgs("key").
+		WillReturnRows(sqlmock.NewRows([]string{"idempotency_key", "request_hash", "status_code", "content_type", "response_body", "completed", "expires_at"}).
+			AddRow("key", "hash", 200, "application/json", []byte(`{"code":200}`), true, now.Add(time.Hour).UnixMilli()))
+
+	rec, created, err = r.Begin(ctx, "key", "hash", time.Hour)
+	if err != nil || created || !rec.Completed || rec.StatusCode != 200 || string(rec.Body) != `{"code":200}` {
+		t.Errorf("Begin() on existing key = %+v, %v, %v", rec, created, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_memoryIdempotencyStore_lease(t *testing.T) {
+	now := time.Now()
+	s := &memoryIdempotencyStore{records: map[string]*model.IdempotencyRecord{}, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	if _, created, _ := s.Begin(ctx, "key", "hash", time.Minute); !created {
+		t.Fatal("Begin() did not claim a new key")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, created, _ := s.Begin(ctx, "key", "hash", time.Minute); !created {
+		t.Fatal("an abandoned key should be claimable once its lease ran out")
+	}
+	_ = s.Complete(ctx, "key", 200, "application/json", []byte(`{}`), time.Hour)
+	now = now.Add(30 * time.Minute)
+	if rec, created, _ := s.Begin(ctx, "key", "hash", time.Minute); created || !rec.Completed {
+		t.Errorf("Begin() = %+v, %v, want the stored response kept for the ttl", rec, created)
+	}
+
+	now = now.Add(time.Hour)
+	s.sweep()
+	if len(s.records) != 0 {
+		t.Errorf("sweep kept %d expired records", len(s.records))
+	}
+}
@@ -0,0 +1,292 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+type leaveRepositories struct {
+	repositories
+}
+
+func NewLeaveRepositories() ILeaveRepositories {
+	return &leaveRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type ILeaveRepositories interface {
+	GetLeaveTypes(ctx context.Context) (rs []*model.LeaveType, err error)
+	GetLeaveTypeById(ctx context.Context, id string) (rs *model.LeaveType, err error)
+	InsertLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error)
+	UpdateLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error)
+	DeleteLeaveType(ctx context.Context, id string) (rs string, err error)
+	// InsertLeaveRequests inserts requests, consecutive parts of one leave
+	// of an employee, once check accepts the employee, the count of their
+	// pending or approved requests overlapping it and their usage of its
+	// leave type. The employee row stays locked from those reads until the
+	// requests are inserted, so concurrent requests are checked one after
+	// the other.
+	InsertLeaveRequests(ctx context.Context, requests []*model.LeaveRequest, check func(employee *model.Employee, overlapping int, usage []*model.LeaveUsage) error) (rs string, err error)
+	GetLeaveRequestById(ctx context.Context, id int64) (rs *model.LeaveRequest, err error)
+	GetLeaveRequests(ctx context.Context, employeeId string) (rs []*model.LeaveRequest, err error)
+	UpdateLeaveStatus(ctx context.Context, request *model.LeaveRequest, fromStatus string) (rs string, err error)
+	GetLeaveUsage(ctx context.Context, employeeId, leaveTypeId string) (rs []*model.LeaveUsage, err error)
+}
+
+func (r leaveRepositories) GetLeaveTypes(ctx context.Context) (rs []*model.LeaveType, err error) {
+	res := make([]*model.LeaveType, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetLeaveTypes())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.LeaveType)
+		if err := scanLeaveType(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r leaveRepositories) GetLeaveTypeById(ctx context.Context, id string) (rs *model.LeaveType, err error) {
+	data := &model.LeaveType{}
+	err = scanLeaveType(r.reader(ctx).QueryRowContext(ctx, config.GetLeaveTypeById(), id), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Leave type %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving leave type: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r leaveRepositories) InsertLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error) {
+	exists, err := r.leaveTypeExists(ctx, leaveType.IdLeaveType)
+	if err != nil {
+		logrus.Errorf("Error checking leave type existence: %v", err)
+		return "", err
+	}
+	if exists {
+		return "Leave type already exists", model.ErrLeaveTypeExists
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.InsertLeaveType(),
+		leaveType.IdLeaveType, leaveType.Name, leaveType.AnnualDays, leaveType.Accrual, leaveType.CarryOverDays)
+	if err != nil {
+		logrus.Errorf("Error inserting leave type: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Successfully inserted a new leave type", nil
+}
+
+func (r leaveRepositories) UpdateLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error) {
+	exists, err := r.leaveTypeExists(ctx, leaveType.IdLeaveType)
+	if err != nil {
+		logrus.Errorf("Error checking leave type existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Leave type doesn't exists", model.ErrLeaveTypeNotFound
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.EditLeaveType(),
+		leaveType.Name, leaveType.AnnualDays, leaveType.Accrual, leaveType.CarryOverDays, leaveType.IdLeaveType)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Leave type was edited", nil
+}
+
+func (r leaveRepositories) DeleteLeaveType(ctx context.Context, id string) (rs string, err error) {
+	exists, err := r.leaveTypeExists(ctx, id)
+	if err != nil {
+		logrus.Errorf("Error checking leave type existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Leave type doesn't exists", model.ErrLeaveTypeNotFound
+	}
+
+	var requests int
+	err = r.DB.QueryRowContext(ctx, config.CountLeaveRequestByType(), id).Scan(&requests)
+	if err != nil {
+		logrus.Errorf("Error counting leave requests: %v", err)
+		return "", err
+	}
+	if requests > 0 {
+		return "Leave type has leave requests", model.ErrLeaveTypeInUse
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.DeleteLeaveType(), id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Leave type was deleted", nil
+}
+
+func (r leaveRepositories) InsertLeaveRequests(ctx context.Context, requests []*model.LeaveRequest, check func(employee *model.Employee, overlapping int, usage []*model.LeaveUsage) error) (rs string, err error) {
+	first, last := requests[0], requests[len(requests)-1]
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	employee := &model.Employee{}
+	if err = scanEmployee(tx.QueryRowContext(ctx, config.GetEmployeeByIdForUpdate(), first.IdEmployee), employee); err != nil {
+		return "", err
+	}
+	var overlapping int
+	err = tx.QueryRowContext(ctx, config.CountOverlappingLeaveRequest(), first.IdEmployee, last.EndDate, first.StartDate).Scan(&overlapping)
+	if err != nil {
+		return "", err
+	}
+	usage, err := leaveUsage(ctx, tx, first.IdEmployee, first.IdLeaveType)
+	if err != nil {
+		return "", err
+	}
+	if err = check(employee, overlapping, usage); err != nil {
+		return "", err
+	}
+
+	for _, request := range requests {
+		res, err := tx.ExecContext(ctx, config.InsertLeaveRequest(),
+			request.IdEmployee, request.IdLeaveType, request.StartDate, request.EndDate, request.Days, request.Reason, request.Status)
+		if err != nil {
+			logrus.Errorf("Error inserting leave request: %v", err)
+			return "", err
+		}
+		if id, err := res.LastInsertId(); err == nil {
+			request.IdLeaveRequest = id
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Leave request was submitted", nil
+}
+
+func (r leaveRepositories) GetLeaveRequestById(ctx context.Context, id int64) (rs *model.LeaveRequest, err error) {
+	data := &model.LeaveRequest{}
+	err = scanLeaveRequest(r.reader(ctx).QueryRowContext(ctx, config.GetLeaveRequestById(), id), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Leave request %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving leave request: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r leaveRepositories) GetLeaveRequests(ctx context.Context, employeeId string) (rs []*model.LeaveRequest, err error) {
+	res := make([]*model.LeaveRequest, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetLeaveRequestsByEmployee(), employeeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.LeaveRequest)
+		if err := scanLeaveRequest(rows, data); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+// UpdateLeaveStatus moves request to its Status only if it is still in
+// fromStatus, so two concurrent decisions cannot both succeed.
+func (r leaveRepositories) UpdateLeaveStatus(ctx context.Context, request *model.LeaveRequest, fromStatus string) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.EditLeaveRequestStatus(),
+		request.Status, request.ApproverId, request.DecisionNote, request.IdLeaveRequest, fromStatus)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return "", model.ErrLeaveNotPending
+	}
+	r.markWrite(ctx)
+	return "Leave request was " + request.Status, nil
+}
+
+func (r leaveRepositories) GetLeaveUsage(ctx context.Context, employeeId, leaveTypeId string) (rs []*model.LeaveUsage, err error) {
+	return leaveUsage(ctx, r.DB, employeeId, leaveTypeId)
+}
+
+func leaveUsage(ctx context.Context, db queryer, employeeId, leaveTypeId string) ([]*model.LeaveUsage, error) {
+	res := make([]*model.LeaveUsage, 0)
+	rows, err := db.QueryContext(ctx, config.GetLeaveUsage(), employeeId, leaveTypeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.LeaveUsage)
+		if err := rows.Scan(&data.Year, &data.Status, &data.Days); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r leaveRepositories) leaveTypeExists(ctx context.Context, id string) (bool, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, config.CountLeaveType(), id).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func scanLeaveType(row scanner, data *model.LeaveType) error {
+	return row.Scan(
+		&data.IdLeaveType,
+		&data.Name,
+		&data.AnnualDays,
+		&data.Accrual,
+		&data.CarryOverDays,
+	)
+}
+
+func scanLeaveRequest(row scanner, data *model.LeaveRequest) error {
+	return row.Scan(
+		&data.IdLeaveRequest,
+		&data.IdEmployee,
+		&data.IdLeaveType,
+		&data.StartDate,
+		&data.EndDate,
+		&data.Days,
+		&data.Reason,
+		&data.Status,
+		&data.ApproverId,
+		&data.DecisionNote,
+		&data.DecidedAt,
+		&data.CreatedAt,
+	)
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test_leaveRepositories_UpdateLeaveStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "still pending", affected: 1},
+		{name: "decided concurrently", affected: 0, wantErr: model.ErrLeaveNotPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				logrus.Fatal("error creating mock")
+			}
+			defer db.Close()
+
+			mock.ExpectExec(config.EditLeaveRequestStatus()).
+				WithArgs(model.LeaveApproved, "M1", "enjoy", int64(7), model.LeavePending).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			r := leaveRepositories{repositories: repositories{DB: db}}
+			_, err = r.UpdateLeaveStatus(context.Background(), &model.LeaveRequest{
+				IdLeaveRequest: 7,
+				Status:         model.LeaveApproved,
+				ApproverId:     "M1",
+				DecisionNote:   "enjoy",
+			}, model.LeavePending)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateLeaveStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_leaveRepositories_InsertLeaveRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkErr error
+	}{
+		{name: "accepted"},
+		{name: "rejected by the check", checkErr: model.ErrLeaveBalance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				logrus.Fatal("error creating mock")
+			}
+			defer db.Close()
+
+			requests := []*model.LeaveRequest{
+				{IdEmployee: "1", IdLeaveType: "AL", StartDate: "2024-12-30", EndDate: "2024-12-31", Days: model.NewQuantity(decimal.NewFromInt(2)), Status: model.LeavePending},
+				{IdEmployee: "1", IdLeaveType: "AL", StartDate: "2025-01-01", EndDate: "2025-01-02", Days: model.NewQuantity(decimal.NewFromInt(2)), Status: model.LeavePending},
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("1").
+				WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+					AddRow("1", "Jane", "Doe", "jane@example.com", "1", "2020-01-06", "0", "USD", "", "", "", "active", "", "", ""))
+			// a range overlaps when it starts before the other ends and ends after it starts
+			mock.ExpectQuery(config.CountOverlappingLeaveRequest()).WithArgs("1", "2025-01-02", "2024-12-30").
+				WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+			mock.ExpectQuery(config.GetLeaveUsage()).WithArgs("1", "AL").
+				WillReturnRows(sqlmock.NewRows([]string{"year", "status", "days"}).AddRow(2024, model.LeaveApproved, "20"))
+			if tt.checkErr != nil {
+				mock.ExpectRollback()
+			} else {
+				for i, request := range requests {
+					mock.ExpectExec(config.InsertLeaveRequest()).
+						WithArgs("1", "AL", request.StartDate, request.EndDate, request.Days, "", model.LeavePending).
+						WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+				}
+				mock.ExpectCommit()
+			}
+
+			var checked []*model.LeaveUsage
+			r := leaveRepositories{repositories: repositories{DB: db}}
+			_, err = r.InsertLeaveRequests(context.Background(), requests, func(employee *model.Employee, overlapping int, usage []*model.LeaveUsage) error {
+				checked = usage
+				return tt.checkErr
+			})
+			if !errors.Is(err, tt.checkErr) {
+				t.Errorf("InsertLeaveRequests() error = %v, wantErr %v", err, tt.checkErr)
+			}
+			if len(checked) != 1 || checked[0].Year != 2024 || !checked[0].Days.Equal(decimal.NewFromInt(20)) {
+				t.Errorf("check got usage %+v", checked)
+			}
+			if tt.checkErr == nil && (requests[0].IdLeaveRequest != 1 || requests[1].IdLeaveRequest != 2) {
+				t.Errorf("InsertLeaveRequests() ids = %d, %d", requests[0].IdLeaveRequest, requests[1].IdLeaveRequest)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
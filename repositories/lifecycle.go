@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+func (r repositories) GetEmployeesByStatus(ctx context.Context, statuses []string) (rs []*model.Employee, err error) {
+	return r.queryEmployees(ctx, r.reader(ctx), config.GetEmployeesByStatus(), strings.Join(statuses, ","))
+}
+
+// ChangeEmployeeStatus writes the lifecycle fields of employee and appends
+// change to its status history in one transaction, recorded as an
+// EmployeeUpdated event. The transition was checked from change.FromStatus,
+// so it is refused if the locked row has moved on from that status since.
+func (r repositories) ChangeEmployeeStatus(ctx context.Context, employee *model.Employee, change *model.StatusChange) (rs string, err error) {
+	err = r.writeEmployee(ctx, model.EventEmployeeUpdated, employee.IdEmployee, func(tx *sql.Tx, before *model.Employee) error {
+		if before.Status != change.FromStatus {
+			return fmt.Errorf("%w: %s to %s, the employee is now %s", model.ErrStatusTransition, change.FromStatus, change.Status, before.Status)
+		}
+		_, err := tx.ExecContext(ctx, config.EditEmployeeStatus(),
+			employee.Status, employee.HireDate, employee.TerminationDate, employee.TerminationReason, employee.IdEmployee)
+		if err != nil {
+			logrus.Errorf("Error updating employee status: %v", err)
+			return err
+		}
+		_, err = tx.ExecContext(ctx, config.InsertStatusChange(),
+			change.IdEmployee, change.FromStatus, change.Status, change.EffectiveDate, change.Reason)
+		if err != nil {
+			logrus.Errorf("Error recording status change: %v", err)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return "Employee status was changed", nil
+}
+
+func (r repositories) InsertStatusChange(ctx context.Context, change *model.StatusChange) error {
+	_, err := r.DB.ExecContext(ctx, config.InsertStatusChange(),
+		change.IdEmployee, change.FromStatus, change.Status, change.EffectiveDate, change.Reason)
+	if err != nil {
+		logrus.Errorf("Error recording status change: %v", err)
+		return err
+	}
+	r.markWrite(ctx)
+	return nil
+}
+
+func (r repositories) GetStatusHistory(ctx context.Context, id string) (rs []*model.StatusChange, err error) {
+	res := make([]*model.StatusChange, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetStatusHistory(), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.StatusChange)
+		err := rows.Scan(
+			&data.IdEmployee,
+			&data.FromStatus,
+			&data.Status,
+			&data.EffectiveDate,
+			&data.Reason,
+			&data.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func Test_repositories_ChangeEmployeeStatus_movedOn(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	// the employee was terminated after the service read them as active
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("1", "John", "Doe", "john@example.com", "1", "", "0", "USD", "", "", "", model.StatusTerminated, "2024-03-14", "resigned", ""))
+	mock.ExpectRollback()
+
+	r := repositories{DB: db}
+	employee := &model.Employee{IdEmployee: "1", Status: model.StatusOnLeave}
+	change := &model.StatusChange{IdEmployee: "1", FromStatus: model.StatusActive, Status: model.StatusOnLeave, EffectiveDate: "2024-03-15"}
+	if _, err := r.ChangeEmployeeStatus(context.Background(), employee, change); !errors.Is(err, model.ErrStatusTransition) {
+		t.Errorf("ChangeEmployeeStatus() error = %v, want %v", err, model.ErrStatusTransition)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/migrations"
+	"employee-golang/model"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// migrationLockWait is how long a run waits for another to finish.
+const migrationLockWait = 10
+
+// IMigrationRepositories applies the embedded schema migrations and records
+// them in schema_migration with the checksum of their statements. Migrate
+// and Baseline stop at version to, or go through the latest migration when
+// to is zero. All three fail with ErrMigrationChecksum when an applied
+// migration's file has changed since it ran.
+type IMigrationRepositories interface {
+	GetMigrationStatus(ctx context.Context) (rs []*model.MigrationStatus, err error)
+	// Migrate applies the pending migrations and returns them. MySQL commits
+	// DDL as it goes, so a migration that fails halfway is left partly
+	// applied and unrecorded, and must be completed by hand.
+	Migrate(ctx context.Context, to int) (rs []*model.MigrationStatus, err error)
+	// Baseline records the pending migrations as applied without running
+	// them, for databases whose schema was set up before migrations were
+	// tracked.
+	Baseline(ctx context.Context, to int) (rs []*model.MigrationStatus, err error)
+}
+
+type migrationRepositories struct {
+	DB         *sql.DB
+	migrations []migrations.Migration
+	now        func() time.Time
+}
+
+func NewMigrationRepositories() (IMigrationRepositories, error) {
+	rs, err := migrations.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &migrationRepositories{DB: InitConfiguration().DB, migrations: rs}, nil
+}
+
+func (r migrationRepositories) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r migrationRepositories) GetMigrationStatus(ctx context.Context) (rs []*model.MigrationStatus, err error) {
+	if err = createSchemaMigrationTable(ctx, r.DB); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range r.migrations {
+		status := &model.MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			status.AppliedAt = a.AppliedAt
+		}
+		rs = append(rs, status)
+	}
+	return rs, r.verify(applied)
+}
+
+func (r migrationRepositories) Migrate(ctx context.Context, to int) (rs []*model.MigrationStatus, err error) {
+	return r.run(ctx, to, true)
+}
+
+func (r migrationRepositories) Baseline(ctx context.Context, to int) (rs []*model.MigrationStatus, err error) {
+	return r.run(ctx, to, false)
+}
+
+// run records the pending migrations up to version to, executing them
+// first when execute is set. It holds a named lock on one connection for
+// the whole run.
+func (r migrationRepositories) run(ctx context.Context, to int, execute bool) (rs []*model.MigrationStatus, err error) {
+	if to != 0 && !r.known(to) {
+		return nil, fmt.Errorf("%w: %d", model.ErrMigrationVersion, to)
+	}
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err = conn.QueryRowContext(ctx, config.LockSchemaMigrations(), migrationLockWait).Scan(&locked); err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return nil, err
+	}
+	if locked.Int64 != 1 {
+		return nil, model.ErrMigrationLocked
+	}
+	defer func() {
+		var released sql.NullInt64
+		if errUnlock := conn.QueryRowContext(context.Background(), config.UnlockSchemaMigrations()).Scan(&released); errUnlock != nil {
+			logrus.Errorf("Error on database %v", errUnlock)
+		}
+	}()
+
+	if err = createSchemaMigrationTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.verify(applied); err != nil {
+		return nil, err
+	}
+	// migrations recorded before checksums were kept take the checksum of
+	// their file as it is now
+	for _, m := range r.migrations {
+		if a, ok := applied[m.Version]; ok && a.Checksum == "" {
+			if _, err = conn.ExecContext(ctx, config.UpdateSchemaMigrationChecksum(), m.Checksum, m.Version); err != nil {
+				logrus.Errorf("Error on database %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	rs = make([]*model.MigrationStatus, 0)
+	for _, m := range r.migrations {
+		if to != 0 && m.Version > to {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if execute {
+			for _, statement := range m.Statements {
+				if _, err = conn.ExecContext(ctx, statement); err != nil {
+					return rs, fmt.Errorf("migration %06d_%s: %w", m.Version, m.Name, err)
+				}
+			}
+		}
+		appliedAt := r.clock().UTC()
+		if _, err = conn.ExecContext(ctx, config.InsertSchemaMigration(), m.Version, m.Name, appliedAt.Format(outboxTimeLayout), m.Checksum); err != nil {
+			logrus.Errorf("Error on database %v", err)
+			return rs, err
+		}
+		rs = append(rs, &model.MigrationStatus{Version: m.Version, Name: m.Name, AppliedAt: appliedAt.Format(time.RFC3339Nano)})
+	}
+	return rs, nil
+}
+
+// verify returns ErrMigrationChecksum for the first applied migration whose
+// statements no longer match the checksum recorded when it ran.
+func (r migrationRepositories) verify(applied map[int]*model.MigrationStatus) error {
+	for _, m := range r.migrations {
+		if a, ok := applied[m.Version]; ok && a.Checksum != "" && a.Checksum != m.Checksum {
+			return fmt.Errorf("%w: %06d_%s", model.ErrMigrationChecksum, m.Version, m.Name)
+		}
+	}
+	return nil
+}
+
+func (r migrationRepositories) known(version int) bool {
+	for _, m := range r.migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// migrationDB is the pool or the connection a migration run holds.
+type migrationDB interface {
+	queryer
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// createSchemaMigrationTable creates schema_migration, adding the checksum
+// column to a table created before it existed.
+func createSchemaMigrationTable(ctx context.Context, db migrationDB) error {
+	if _, err := db.ExecContext(ctx, config.CreateSchemaMigrationTable()); err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return err
+	}
+	var columns int
+	if err := db.QueryRowContext(ctx, config.HasSchemaMigrationChecksum()).Scan(&columns); err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return err
+	}
+	if columns > 0 {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, config.AddSchemaMigrationChecksum()); err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return err
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, db queryer) (map[int]*model.MigrationStatus, error) {
+	rows, err := db.QueryContext(ctx, config.GetSchemaMigrations())
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[int]*model.MigrationStatus)
+	for rows.Next() {
+		data := new(model.MigrationStatus)
+		if err := rows.Scan(&data.Version, &data.Name, &data.AppliedAt, &data.Checksum); err != nil {
+			return nil, err
+		}
+		data.AppliedAt = readTime(data.AppliedAt)
+		res[data.Version] = data
+	}
+	return res, rows.Err()
+}
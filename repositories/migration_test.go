@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/migrations"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"testing"
+	"time"
+)
+
+var testMigrations = []migrations.Migration{
+	{Version: 1, Name: "create_employee", Statements: []string{"create table employee (id int)"}, Checksum: "c1"},
+	{Version: 2, Name: "add_status", Statements: []string{"alter table employee add column status int", "update employee set status = 1"}, Checksum: "c2"},
+	{Version: 3, Name: "add_email", Statements: []string{"alter table employee add column email int"}, Checksum: "c3"},
+}
+
+func Test_migrationRepositories_Migrate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.LockSchemaMigrations()).WithArgs(migrationLockWait).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+	mock.ExpectExec(config.CreateSchemaMigrationTable()).WillReturnResult(sqlmock.NewResult(0, 0))
+	// a table from before checksums gains the column, and its rows the
+	// checksum of their file
+	mock.ExpectQuery(config.HasSchemaMigrationChecksum()).WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+	mock.ExpectExec(config.AddSchemaMigrationChecksum()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(config.GetSchemaMigrations()).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at", "checksum"}).AddRow(1, "create_employee", "2024-05-01 10:00:00", ""))
+	mock.ExpectExec(config.UpdateSchemaMigrationChecksum()).WithArgs("c1", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("alter table employee add column status int").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("update employee set status = 1").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(config.InsertSchemaMigration()).WithArgs(2, "add_status", "2024-05-06 07:08:09", "c2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(config.UnlockSchemaMigrations()).WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(1))
+
+	r := migrationRepositories{DB: db, migrations: testMigrations, now: func() time.Time { return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC) }}
+	rs, err := r.Migrate(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].Version != 2 || rs[0].AppliedAt != "2024-05-06T07:08:09Z" {
+		t.Errorf("unexpected migrations applied %v", rs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_migrationRepositories_MigrateChanged(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.LockSchemaMigrations()).WithArgs(migrationLockWait).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+	mock.ExpectExec(config.CreateSchemaMigrationTable()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(config.HasSchemaMigrationChecksum()).WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectQuery(config.GetSchemaMigrations()).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at", "checksum"}).
+			AddRow(1, "create_employee", "2024-05-01 10:00:00", "c1").
+			AddRow(2, "add_status", "2024-05-01 10:00:00", "edited"))
+	mock.ExpectQuery(config.UnlockSchemaMigrations()).WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(1))
+
+	// nothing is applied once an applied migration no longer matches
+	r := migrationRepositories{DB: db, migrations: testMigrations}
+	if _, err := r.Migrate(context.Background(), 0); !errors.Is(err, model.ErrMigrationChecksum) || err.Error() != model.ErrMigrationChecksum.Error()+": 000002_add_status" {
+		t.Errorf("Migrate() error = %v, want %v", err, model.ErrMigrationChecksum)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_migrationRepositories_MigrateLocked(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.LockSchemaMigrations()).WithArgs(migrationLockWait).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(0))
+
+	r := migrationRepositories{DB: db, migrations: testMigrations}
+	if _, err := r.Migrate(context.Background(), 0); !errors.Is(err, model.ErrMigrationLocked) {
+		t.Errorf("Migrate() error = %v, want %v", err, model.ErrMigrationLocked)
+	}
+	if _, err := r.Baseline(context.Background(), 7); !errors.Is(err, model.ErrMigrationVersion) {
+		t.Errorf("Baseline() error = %v, want %v", err, model.ErrMigrationVersion)
+	}
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"time"
+)
+
+const outboxTimeLayout = "2006-01-02 15:04:05.999999"
+
+type outboxRepositories struct {
+	repositories
+	publishTimeout time.Duration
+	now            func() time.Time
+}
+
+func NewOutboxRepositories() IOutboxRepositories {
+	return &outboxRepositories{
+		repositories:   *InitConfiguration(),
+		publishTimeout: config.GetEventSinkTimeout(),
+		now:            time.Now,
+	}
+}
+
+type IOutboxRepositories interface {
+	// PublishPending hands up to limit unpublished events to publish in
+	// order, stopping at the first failure, and records the outcome. It
+	// returns how many events were published.
+	PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, event *model.Event) error) (n int, err error)
+	DeletePublishedEvents(ctx context.Context, before time.Time) (n int64, err error)
+}
+
+// PublishPending publishes the events one at a time, each in its own
+// transaction, so an event stays locked only while its sink is called and
+// for no longer than publishTimeout. Relays in other instances wait on the
+// lock rather than publish later events first. An event is marked published
+// only after its sink accepted it; a crash in between publishes it again,
+// which is the at-least-once guarantee.
+func (r outboxRepositories) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, event *model.Event) error) (n int, err error) {
+	for n < limit {
+		published, err := r.publishNext(ctx, publish)
+		if err != nil || !published {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// publishNext publishes the oldest pending event and commits the outcome,
+// reporting false when there is none.
+func (r outboxRepositories) publishNext(ctx context.Context, publish func(ctx context.Context, event *model.Event) error) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	events, err := pendingEvents(ctx, tx, 1)
+	if err != nil || len(events) == 0 {
+		return false, err
+	}
+	event := events[0]
+	publishCtx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+	errPublish := publish(publishCtx, event)
+	cancel()
+	if errPublish != nil {
+		_, err = tx.ExecContext(ctx, config.RecordOutboxEventFailure(), errPublish.Error(), event.Sequence)
+	} else {
+		_, err = tx.ExecContext(ctx, config.MarkOutboxEventPublished(), r.now().UTC().Format(outboxTimeLayout), event.Sequence)
+	}
+	if err != nil {
+		logrus.Errorf("Error recording outbox event outcome: %v", err)
+		return false, err
+	}
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	if errPublish != nil {
+		return false, fmt.Errorf("publishing event %d: %w", event.Sequence, errPublish)
+	}
+	return true, nil
+}
+
+func pendingEvents(ctx context.Context, tx *sql.Tx, limit int) ([]*model.Event, error) {
+	res := make([]*model.Event, 0)
+	rows, err := tx.QueryContext(ctx, config.GetPendingOutboxEvents(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Event)
+		var payload []byte
+		var occurredAt string
+		if err := rows.Scan(&data.Sequence, &data.IdEvent, &data.Type, &data.AggregateId, &payload, &occurredAt, &data.Attempts); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		data.Payload = payload
+		data.OccurredAt = readTime(occurredAt)
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+// readTime turns a datetime read as char into RFC 3339, leaving values in
+// other layouts as they are.
+func readTime(v string) string {
+	if t, err := time.Parse(outboxTimeLayout, v); err == nil {
+		return t.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func (r outboxRepositories) DeletePublishedEvents(ctx context.Context, before time.Time) (n int64, err error) {
+	res, err := r.DB.ExecContext(ctx, config.DeletePublishedOutboxEvents(), before.UTC().Format(outboxTimeLayout))
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// writeEmployee runs write in a transaction together with the outbox event
+// describing its effect on employee id, so the event is published exactly
+// when the change is committed. The before image is read with a lock and
+// handed to write, nil for a new employee, and the after image is read from
+// within the transaction; an update that changes nothing records no event.
+func (r repositories) writeEmployee(ctx context.Context, eventType, id string, write func(tx *sql.Tx, before *model.Employee) error) error {
+	return r.writeEmployeeEvent(ctx, eventType, id, false, write)
+}
+
+// writeEmployeeEvent is writeEmployee, recording the event even when the
+// employee reads the same afterwards if always is set, for writes to
+// records the employee only reflects later.
+func (r repositories) writeEmployeeEvent(ctx context.Context, eventType, id string, always bool, write func(tx *sql.Tx, before *model.Employee) error) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	change := model.EmployeeChange{}
+	if eventType != model.EventEmployeeCreated {
+		change.Before = new(model.Employee)
+		if err = scanEmployee(tx.QueryRowContext(ctx, config.GetEmployeeByIdForUpdate(), id), change.Before); err != nil {
+			return err
+		}
+	}
+	if err = write(tx, change.Before); err != nil {
+		return err
+	}
+	if eventType != model.EventEmployeeDeleted {
+		change.After = new(model.Employee)
+		if err = scanEmployee(tx.QueryRowContext(ctx, config.GetEmployeeById(), id), change.After); err != nil {
+			return err
+		}
+	}
+	if always || eventType != model.EventEmployeeUpdated || !reflect.DeepEqual(change.Before, change.After) {
+		if err = insertEvent(ctx, tx, eventType, id, change); err != nil {
+			logrus.Errorf("Error recording outbox event: %v", err)
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	r.markWrite(ctx)
+	return nil
+}
+
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType, aggregateId string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	id, err := newEventId()
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, config.InsertOutboxEvent(),
+		id, eventType, aggregateId, data, time.Now().UTC().Format(outboxTimeLayout))
+	return err
+}
+
+// newEventId returns a random version 4 UUID.
+func newEventId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
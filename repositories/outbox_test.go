@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_outboxRepositories_PublishPending(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	columns := []string{"id", "event_id", "event_type", "aggregate_id", "payload", "occurred_at", "attempts"}
+	// each event is locked, published and its outcome committed on its own
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetPendingOutboxEvents()).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "e-1", model.EventEmployeeCreated, "7", []byte(`{"before":null}`), "2024-05-06 07:00:00.25", 0))
+	mock.ExpectExec(config.MarkOutboxEventPublished()).WithArgs("2024-05-06 07:08:09", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetPendingOutboxEvents()).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "e-2", model.EventEmployeeUpdated, "7", []byte(`{}`), "2024-05-06 07:00:01", 2))
+	mock.ExpectExec(config.RecordOutboxEventFailure()).WithArgs("sink down", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var published []*model.Event
+	r := outboxRepositories{repositories: repositories{DB: db}, publishTimeout: time.Second, now: func() time.Time { return now }}
+	n, err := r.PublishPending(context.Background(), 10, func(ctx context.Context, event *model.Event) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("event %d published without a deadline", event.Sequence)
+		}
+		if event.Sequence == 2 {
+			return errors.New("sink down")
+		}
+		published = append(published, event)
+		return nil
+	})
+
+	// a failed event stops the batch so later events are not published
+	// ahead of it
+	if n != 1 || err == nil {
+		t.Fatalf("PublishPending() = %d, %v", n, err)
+	}
+	want := &model.Event{Sequence: 1, IdEvent: "e-1", Type: model.EventEmployeeCreated, AggregateId: "7",
+		OccurredAt: "2024-05-06T07:00:00.25Z", Payload: []byte(`{"before":null}`)}
+	if len(published) != 1 || !reflect.DeepEqual(published[0], want) {
+		t.Errorf("published %+v, want %+v", published, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_repositories_writeEmployee_noChange(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	row := []driver.Value{"7", "Jane", "Doe", "jane@example.com", "1", "", "0", "USD", "", "", "", "active", "", "", ""}
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).AddRow(row...))
+	mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(config.GetEmployeeById()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).AddRow(row...))
+	mock.ExpectCommit()
+
+	r := repositories{DB: db}
+	err = r.writeEmployee(context.Background(), model.EventEmployeeUpdated, "7", func(tx *sql.Tx, _ *model.Employee) error {
+		_, err := tx.ExecContext(context.Background(), "update")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an update that changes nothing records no event
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_repositories_UpdateEmployee_salaryOnly(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	e := &model.Employee{IdEmployee: "7", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "1",
+		Salary: model.NewMoney(decimal.NewFromInt(65000)), Currency: "USD"}
+	e.Compensation = &model.Compensation{IdEmployee: "7", Salary: e.Salary, Currency: "USD", EffectiveDate: "2024-03-15", Reason: "salary updated"}
+	mock.ExpectQuery(config.CountEmployee()).WithArgs("7", "jane@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("7", "Jane", "Doe", "jane@example.com", "1", "", "60000", "USD", "", "", "", "active", "", "", ""))
+	mock.ExpectExec(config.EditEmployee()).
+		WithArgs("Jane", "Doe", "jane@example.com", "1", "", "65000", "USD", "", "", "", "7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(config.InsertCompensation()).WithArgs("7", "65000", "USD", "2024-03-15", "salary updated").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectQuery(config.GetEmployeeById()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).
+			AddRow("7", "Jane", "Doe", "jane@example.com", "1", "", "65000", "USD", "", "", "", "active", "", "", ""))
+	mock.ExpectExec(config.InsertOutboxEvent()).
+		WithArgs(sqlmock.AnyArg(), model.EventEmployeeUpdated, "7", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// the salary is read from the history, so the after image only shows a
+	// salary-only change when the history entry is written first
+	r := repositories{DB: db}
+	if _, err := r.UpdateEmployee(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_newEventId(t *testing.T) {
+	id, err := newEventId()
+	if err != nil || len(id) != 36 || id[14] != '4' {
+		t.Errorf("newEventId() = %q, %v", id, err)
+	}
+}
+
+func Test_repositories_ScheduleCompensation_future(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	row := []driver.Value{"7", "Jane", "Doe", "jane@example.com", "1", "", "60000", "USD", "", "", "", "active", "", "", ""}
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetEmployeeByIdForUpdate()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).AddRow(row...))
+	mock.ExpectExec(config.InsertCompensation()).WithArgs("7", "65000", "USD", "2099-01-01", "raise").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery(config.GetEmployeeById()).WithArgs("7").
+		WillReturnRows(sqlmock.NewRows(employeeColumnNames).AddRow(row...))
+	mock.ExpectExec(config.InsertOutboxEvent()).
+		WithArgs(sqlmock.AnyArg(), model.EventEmployeeUpdated, "7", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// a change taking effect later leaves the employee as it reads today
+	// but is still an update of it
+	compensation := &model.Compensation{IdEmployee: "7", Salary: model.NewMoney(decimal.NewFromInt(65000)), Currency: "USD", EffectiveDate: "2099-01-01", Reason: "raise"}
+	r := repositories{DB: db}
+	if _, err := r.ScheduleCompensation(context.Background(), compensation, nil); err != nil {
+		t.Fatal(err)
+	}
+	if compensation.IdCompensation != 3 {
+		t.Errorf("IdCompensation = %d, want 3", compensation.IdCompensation)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+func (r repositories) GetPhoto(ctx context.Context, id string) (rs *model.Photo, err error) {
+	data := &model.Photo{}
+	err = scanPhoto(r.reader(ctx).QueryRowContext(ctx, config.GetPhoto(), id), data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving photo: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// SavePhoto inserts the employee's photo or replaces the existing one.
+func (r repositories) SavePhoto(ctx context.Context, photo *model.Photo) (rs string, err error) {
+	_, err = r.DB.ExecContext(ctx, config.SavePhoto(),
+		photo.IdEmployee, photo.ContentType, photo.Width, photo.Height, photo.Size, photo.Checksum)
+	if err != nil {
+		logrus.Errorf("Error saving photo: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Photo was saved", nil
+}
+
+func (r repositories) DeletePhoto(ctx context.Context, id string) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.DeletePhoto(), id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return "", sql.ErrNoRows
+	}
+	r.markWrite(ctx)
+	return "Photo was deleted", nil
+}
+
+func scanPhoto(row scanner, data *model.Photo) error {
+	return row.Scan(
+		&data.IdEmployee,
+		&data.ContentType,
+		&data.Width,
+		&data.Height,
+		&data.Size,
+		&data.Checksum,
+		&data.UpdatedAt,
+	)
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+)
+
+type positionRepositories struct {
+	repositories
+}
+
+func NewPositionRepositories() IPositionRepositories {
+	return &positionRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type IPositionRepositories interface {
+	GetPosition(ctx context.Context) (rs []*model.Position, err error)
+	GetPositionById(ctx context.Context, id string) (rs *model.Position, err error)
+	GetPositionsByIds(ctx context.Context, ids []string) (rs []*model.Position, err error)
+	InsertPosition(ctx context.Context, position *model.Position) (rs string, err error)
+	UpdatePosition(ctx context.Context, position *model.Position) (rs string, err error)
+	DeletePosition(ctx context.Context, id string) (rs string, err error)
+	GetSalaryOverrides(ctx context.Context, employeeId string) (rs []*model.SalaryOverride, err error)
+}
+
+func (r positionRepositories) GetPosition(ctx context.Context) (rs []*model.Position, err error) {
+	return r.queryPositions(ctx, config.GetPositions())
+}
+
+func (r positionRepositories) GetPositionsByIds(ctx context.Context, ids []string) (rs []*model.Position, err error) {
+	return queryBatches(ids, config.GetPositionsByIds(), func(query string, args []any) ([]*model.Position, error) {
+		return r.queryPositions(ctx, query, args...)
+	})
+}
+
+func (r positionRepositories) queryPositions(ctx context.Context, query string, args ...any) ([]*model.Position, error) {
+	res := make([]*model.Position, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.Position)
+		err := rows.Scan(
+			&data.IdPosition,
+			&data.Title,
+			&data.Grade,
+			&data.MinSalary,
+			&data.MaxSalary,
+			&data.Currency,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r positionRepositories) GetPositionById(ctx context.Context, id string) (rs *model.Position, err error) {
+	data := &model.Position{}
+	err = r.reader(ctx).QueryRowContext(ctx, config.GetPositionById(), id).Scan(
+		&data.IdPosition,
+		&data.Title,
+		&data.Grade,
+		&data.MinSalary,
+		&data.MaxSalary,
+		&data.Currency,
+	)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Position %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving position: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r positionRepositories) InsertPosition(ctx context.Context, position *model.Position) (rs string, err error) {
+	exists, err := r.positionExists(ctx, position.IdPosition)
+	if err != nil {
+		logrus.Errorf("Error checking position existence: %v", err)
+		return "", err
+	}
+	if exists {
+		return "Position already exists", model.ErrPositionExists
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.InsertPosition(),
+		position.IdPosition, position.Title, position.Grade, position.MinSalary, position.MaxSalary, position.Currency)
+	if err != nil {
+		logrus.Errorf("Error inserting position: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Successfully inserted a new position", nil
+}
+
+func (r positionRepositories) UpdatePosition(ctx context.Context, position *model.Position) (rs string, err error) {
+	exists, err := r.positionExists(ctx, position.IdPosition)
+	if err != nil {
+		logrus.Errorf("Error checking position existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Position doesn't exists", model.ErrPositionNotFound
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.EditPosition(),
+		position.Title, position.Grade, position.MinSalary, position.MaxSalary, position.Currency, position.IdPosition)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Position was edited", nil
+}
+
+func (r positionRepositories) DeletePosition(ctx context.Context, id string) (rs string, err error) {
+	exists, err := r.positionExists(ctx, id)
+	if err != nil {
+		logrus.Errorf("Error checking position existence: %v", err)
+		return "", err
+	}
+	if !exists {
+		return "Position doesn't exists", model.ErrPositionNotFound
+	}
+
+	var employees int
+	err = r.DB.QueryRowContext(ctx, config.CountEmployeeByPosition(), id).Scan(&employees)
+	if err != nil {
+		logrus.Errorf("Error counting position employees: %v", err)
+		return "", err
+	}
+	if employees > 0 {
+		return "Position is still held by employees", model.ErrPositionInUse
+	}
+
+	_, err = r.DB.ExecContext(ctx, config.DeletePosition(), id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Position was deleted", nil
+}
+
+// insertSalaryOverride records override, if any, within the transaction
+// writing the salary it accepts.
+func insertSalaryOverride(ctx context.Context, tx *sql.Tx, override *model.SalaryOverride) error {
+	if override == nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, config.InsertSalaryOverride(),
+		override.IdEmployee, override.IdPosition, override.Salary, override.Currency,
+		override.MinSalary, override.MaxSalary, override.BandCurrency, override.Reason)
+	if err != nil {
+		logrus.Errorf("Error recording salary override: %v", err)
+	}
+	return err
+}
+
+func (r positionRepositories) GetSalaryOverrides(ctx context.Context, employeeId string) (rs []*model.SalaryOverride, err error) {
+	res := make([]*model.SalaryOverride, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetSalaryOverrides(), employeeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.SalaryOverride)
+		err := rows.Scan(
+			&data.IdEmployee,
+			&data.IdPosition,
+			&data.Salary,
+			&data.Currency,
+			&data.MinSalary,
+			&data.MaxSalary,
+			&data.BandCurrency,
+			&data.Reason,
+			&data.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r positionRepositories) positionExists(ctx context.Context, id string) (bool, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, config.CountPosition(), id).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/util"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultMaxPins bounds the clients pinned to the primary at once when
+// MaxPins is not set.
+const defaultMaxPins = 100000
+
+// ReplicaRouter sends reads to healthy replicas in round-robin order and
+// everything else to the primary. A replica that fails EjectAfter health
+// checks in a row is ejected until it answers a ping again.
+type ReplicaRouter struct {
+	Primary    *sql.DB
+	EjectAfter int
+	// PinWindow keeps a client on the primary for this long after it wrote,
+	// so it reads its own writes despite replication lag.
+	PinWindow time.Duration
+	// MaxPins caps the clients pinned at once. Once it is reached, clients
+	// writing are not pinned until earlier pins expire.
+	MaxPins int
+
+	replicas []*replica
+	next     uint32
+
+	mu   sync.Mutex
+	pins map[string]time.Time
+	stop chan struct{}
+}
+
+type replica struct {
+	db       *sql.DB
+	healthy  atomic.Bool
+	failures int
+}
+
+func NewReplicaRouter(primary *sql.DB, replicas []*sql.DB, ejectAfter int, pinWindow time.Duration) *ReplicaRouter {
+	router := &ReplicaRouter{
+		Primary:    primary,
+		EjectAfter: ejectAfter,
+		PinWindow:  pinWindow,
+		MaxPins:    defaultMaxPins,
+		pins:       map[string]time.Time{},
+	}
+	for _, db := range replicas {
+		rp := &replica{db: db}
+		rp.healthy.Store(true)
+		router.replicas = append(router.replicas, rp)
+	}
+	return router
+}
+
+func newReplicaRouterFromConfig(primary *sql.DB) (*ReplicaRouter, error) {
+	connections, err := config.GetReplicaConnections()
+	if err != nil || len(connections) == 0 {
+		return nil, err
+	}
+	replicas := make([]*sql.DB, 0, len(connections))
+	for _, dsn := range connections {
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			logrus.Errorf("failed to create replica connection %s", err)
+			continue
+		}
+		db.SetConnMaxLifetime(config.GetConnMaxLifetime())
+		db.SetConnMaxIdleTime(config.GetConnMaxIdleTime())
+		db.SetMaxOpenConns(config.GetMaxOpenConns())
+		db.SetMaxIdleConns(config.GetMaxIdleConns())
+		replicas = append(replicas, db)
+	}
+	router := NewReplicaRouter(primary, replicas, config.GetReplicaEjectAfter(), config.GetReadYourWritesWindow())
+	router.MaxPins = config.GetReplicaMaxPins()
+	router.StartHealthCheck(config.GetReplicaHealthInterval())
+	return router, nil
+}
+
+// Reader returns the pool a read for ctx should go to.
+func (r *ReplicaRouter) Reader(ctx context.Context) *sql.DB {
+	if r.pinned(util.ClientFrom(ctx)) {
+		return r.Primary
+	}
+	n := len(r.replicas)
+	for i := 0; i < n; i++ {
+		idx := atomic.AddUint32(&r.next, 1) % uint32(n)
+		if rp := r.replicas[idx]; rp.healthy.Load() {
+			return rp.db
+		}
+	}
+	return r.Primary
+}
+
+// MarkWrite pins the client of ctx to the primary for PinWindow.
+func (r *ReplicaRouter) MarkWrite(ctx context.Context) {
+	client := util.ClientFrom(ctx)
+	if r.PinWindow <= 0 || client == "" {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pins[client]; !ok && len(r.pins) >= r.MaxPins {
+		r.sweepPins(now)
+		if len(r.pins) >= r.MaxPins {
+			logrus.Warnf("not pinning client to the primary, %d clients are pinned already", len(r.pins))
+			return
+		}
+	}
+	r.pins[client] = now.Add(r.PinWindow)
+}
+
+func (r *ReplicaRouter) pinned(client string) bool {
+	if r.PinWindow <= 0 || client == "" {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	until, ok := r.pins[client]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(r.pins, client)
+		return false
+	}
+	return true
+}
+
+// sweepPins drops the pins expired by now. The caller holds r.mu.
+func (r *ReplicaRouter) sweepPins(now time.Time) {
+	for client, until := range r.pins {
+		if now.After(until) {
+			delete(r.pins, client)
+		}
+	}
+}
+
+// CheckHealth pings every replica once, ejecting or restoring them.
+func (r *ReplicaRouter) CheckHealth(ctx context.Context) {
+	for i, rp := range r.replicas {
+		err := rp.db.PingContext(ctx)
+		switch {
+		case err == nil:
+			if !rp.healthy.Load() {
+				logrus.Infof("replica %d is healthy again", i)
+			}
+			rp.failures = 0
+			rp.healthy.Store(true)
+		default:
+			rp.failures++
+			if rp.failures >= r.EjectAfter && rp.healthy.Load() {
+				logrus.Warnf("ejecting replica %d after %d failed health checks: %s", i, rp.failures, err)
+				rp.healthy.Store(false)
+			}
+		}
+	}
+}
+
+// StartHealthCheck runs CheckHealth every interval until Close is called,
+// dropping expired pins of clients that did not read again.
+func (r *ReplicaRouter) StartHealthCheck(interval time.Duration) {
+	r.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				r.CheckHealth(ctx)
+				cancel()
+				r.mu.Lock()
+				r.sweepPins(time.Now())
+				r.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (r *ReplicaRouter) Close() {
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+	for _, rp := range r.replicas {
+		_ = rp.db.Close()
+	}
+}
+
+func (r repositories) reader(ctx context.Context) *sql.DB {
+	if r.Router == nil {
+		return r.DB
+	}
+	return r.Router.Reader(ctx)
+}
+
+func (r repositories) markWrite(ctx context.Context) {
+	if r.Router != nil {
+		r.Router.MarkWrite(ctx)
+	}
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/util"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"testing"
+	"time"
+)
+
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, mock
+}
+
+func TestReplicaRouter_Reader(t *testing.T) {
+	primary, _ := newMockDB(t)
+	first, _ := newMockDB(t)
+	second, _ := newMockDB(t)
+	router := NewReplicaRouter(primary, []*sql.DB{first, second}, 1, time.Minute)
+
+	ctx := util.WithClient(context.Background(), "client-a")
+	got := []*sql.DB{router.Reader(ctx), router.Reader(ctx), router.Reader(ctx)}
+	if got[0] == got[1] || got[0] != got[2] {
+		t.Errorf("Reader() did not round-robin between replicas")
+	}
+	for _, db := range got {
+		if db == primary {
+			t.Errorf("Reader() returned primary with healthy replicas")
+		}
+	}
+
+	router.MarkWrite(ctx)
+	if router.Reader(ctx) != primary {
+		t.Errorf("Reader() after write should pin client to primary")
+	}
+	other := util.WithClient(context.Background(), "client-b")
+	if router.Reader(other) == primary {
+		t.Errorf("Reader() pinned a client that did not write")
+	}
+}
+
+func TestReplicaRouter_CheckHealth(t *testing.T) {
+	primary, _ := newMockDB(t)
+	replicaDB, mock := newMockDB(t)
+	router := NewReplicaRouter(primary, []*sql.DB{replicaDB}, 2, 0)
+	ctx := context.Background()
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	router.CheckHealth(ctx)
+	if router.Reader(ctx) != replicaDB {
+		t.Errorf("replica ejected before reaching the failure threshold")
+	}
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	router.CheckHealth(ctx)
+	if router.Reader(ctx) != primary {
+		t.Errorf("unhealthy replica should fall back to primary")
+	}
+
+	mock.ExpectPing()
+	router.CheckHealth(ctx)
+	if router.Reader(ctx) != replicaDB {
+		t.Errorf("replica should be restored after a successful ping")
+	}
+}
+
+func TestReplicaRouter_MarkWrite_maxPins(t *testing.T) {
+	primary, _ := newMockDB(t)
+	replicaDB, _ := newMockDB(t)
+	router := NewReplicaRouter(primary, []*sql.DB{replicaDB}, 1, time.Minute)
+	router.MaxPins = 1
+
+	first := util.WithClient(context.Background(), "client-a")
+	second := util.WithClient(context.Background(), "client-b")
+	router.MarkWrite(first)
+	router.MarkWrite(second)
+	if router.Reader(first) != primary || router.Reader(second) != replicaDB {
+		t.Errorf("MarkWrite() should not pin beyond MaxPins")
+	}
+
+	// an expired pin makes room for the next client
+	router.mu.Lock()
+	router.pins["client-a"] = time.Now().Add(-time.Second)
+	router.mu.Unlock()
+	router.MarkWrite(second)
+	if router.Reader(second) != primary {
+		t.Errorf("MarkWrite() should pin once an earlier pin expired")
+	}
+	if len(router.pins) != 1 {
+		t.Errorf("expired pins left behind: %v", router.pins)
+	}
+}
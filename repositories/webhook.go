@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/model"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type webhookRepositories struct {
+	repositories
+}
+
+func NewWebhookRepositories() IWebhookRepositories {
+	return &webhookRepositories{
+		repositories: *InitConfiguration(),
+	}
+}
+
+type IWebhookRepositories interface {
+	GetSubscriptions(ctx context.Context) (rs []*model.WebhookSubscription, err error)
+	GetSubscriptionById(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error)
+	InsertSubscription(ctx context.Context, subscription *model.WebhookSubscription) (rs string, err error)
+	UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (rs string, err error)
+	UpdateSecret(ctx context.Context, id int64, secret string) (rs string, err error)
+	DeleteSubscription(ctx context.Context, id int64) (rs string, err error)
+
+	// EnqueueDeliveries adds a pending delivery of event, sent as payload,
+	// for every active subscription selecting it and returns how many were
+	// added.
+	EnqueueDeliveries(ctx context.Context, event *model.Event, payload []byte, at time.Time) (n int64, err error)
+	// ClaimDueDeliveries returns up to limit deliveries due at now and
+	// moves their next attempt to leaseUntil, so no other dispatcher sends
+	// them meanwhile and they are retried if this one dies.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) (rs []*model.WebhookDelivery, err error)
+	// RecordDeliveryAttempt stores the status, attempts and error of
+	// delivery together with attempt.
+	RecordDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookAttempt, attemptedAt, nextAttemptAt time.Time) error
+	GetDeliveries(ctx context.Context, subscriptionId int64, status string, limit int) (rs []*model.WebhookDelivery, err error)
+	GetDeliveryById(ctx context.Context, subscriptionId, id int64) (rs *model.WebhookDelivery, err error)
+	RedeliverDelivery(ctx context.Context, subscriptionId, id int64, at time.Time) (rs string, err error)
+	DeleteFinishedDeliveries(ctx context.Context, before time.Time) (n int64, err error)
+}
+
+func (r webhookRepositories) GetSubscriptions(ctx context.Context) (rs []*model.WebhookSubscription, err error) {
+	res := make([]*model.WebhookSubscription, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetWebhookSubscriptions())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanSubscription(rows)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r webhookRepositories) GetSubscriptionById(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error) {
+	data, err := scanSubscription(r.reader(ctx).QueryRowContext(ctx, config.GetWebhookSubscriptionById(), id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Webhook subscription %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving webhook subscription: %v", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func scanSubscription(row scanner) (*model.WebhookSubscription, error) {
+	data := new(model.WebhookSubscription)
+	var eventTypes string
+	err := row.Scan(
+		&data.IdSubscription,
+		&data.Url,
+		&data.Secret,
+		&eventTypes,
+		&data.Description,
+		&data.Active,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	data.EventTypes = splitEventTypes(eventTypes)
+	return data, nil
+}
+
+func splitEventTypes(v string) []string {
+	res := make([]string, 0)
+	for _, t := range strings.Split(v, ",") {
+		if t != "" {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func (r webhookRepositories) InsertSubscription(ctx context.Context, subscription *model.WebhookSubscription) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.InsertWebhookSubscription(), subscription.Url, subscription.Secret,
+		strings.Join(subscription.EventTypes, ","), subscription.Description, subscription.Active)
+	if err != nil {
+		logrus.Errorf("Error inserting webhook subscription: %v", err)
+		return "", err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		subscription.IdSubscription = id
+	}
+	r.markWrite(ctx)
+	return "Webhook was added", nil
+}
+
+func (r webhookRepositories) UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (rs string, err error) {
+	_, err = r.DB.ExecContext(ctx, config.UpdateWebhookSubscription(), subscription.Url,
+		strings.Join(subscription.EventTypes, ","), subscription.Description, subscription.Active, subscription.IdSubscription)
+	if err != nil {
+		logrus.Errorf("Error updating webhook subscription: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Webhook was updated", nil
+}
+
+func (r webhookRepositories) UpdateSecret(ctx context.Context, id int64, secret string) (rs string, err error) {
+	_, err = r.DB.ExecContext(ctx, config.UpdateWebhookSecret(), secret, id)
+	if err != nil {
+		logrus.Errorf("Error updating webhook secret: %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Webhook secret was rotated", nil
+}
+
+func (r webhookRepositories) DeleteSubscription(ctx context.Context, id int64) (rs string, err error) {
+	res, err := r.DB.ExecContext(ctx, config.DeleteWebhookSubscription(), id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return "", sql.ErrNoRows
+	}
+	r.markWrite(ctx)
+	return "Webhook was deleted", nil
+}
+
+func (r webhookRepositories) EnqueueDeliveries(ctx context.Context, event *model.Event, payload []byte, at time.Time) (n int64, err error) {
+	res, err := r.DB.ExecContext(ctx, config.EnqueueWebhookDeliveries(),
+		event.IdEvent, event.Type, payload, at.UTC().Format(outboxTimeLayout), event.Type)
+	if err != nil {
+		logrus.Errorf("Error enqueueing webhook deliveries: %v", err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r webhookRepositories) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) (rs []*model.WebhookDelivery, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := dueDeliveries(ctx, tx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	lease := leaseUntil.UTC().Format(outboxTimeLayout)
+	for _, delivery := range res {
+		if _, err = tx.ExecContext(ctx, config.LeaseWebhookDelivery(), lease, delivery.IdDelivery); err != nil {
+			logrus.Errorf("Error leasing webhook delivery: %v", err)
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func dueDeliveries(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	res := make([]*model.WebhookDelivery, 0)
+	rows, err := tx.QueryContext(ctx, config.GetDueWebhookDeliveries(), now.UTC().Format(outboxTimeLayout), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := &model.WebhookDelivery{Status: model.DeliveryPending}
+		var payload []byte
+		err := rows.Scan(&data.IdDelivery, &data.IdSubscription, &data.IdEvent, &data.EventType,
+			&payload, &data.Attempts, &data.Url, &data.Secret)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		data.Payload = payload
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r webhookRepositories) RecordDeliveryAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookAttempt, attemptedAt, nextAttemptAt time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	at := attemptedAt.UTC().Format(outboxTimeLayout)
+	_, err = tx.ExecContext(ctx, config.RecordWebhookDelivery(), delivery.Status, delivery.Attempts,
+		nextAttemptAt.UTC().Format(outboxTimeLayout), at, attempt.ResponseStatus, attempt.Error, delivery.IdDelivery)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, config.InsertWebhookAttempt(), delivery.IdDelivery, at,
+			attempt.ResponseStatus, attempt.Error, attempt.DurationMs)
+	}
+	if err != nil {
+		logrus.Errorf("Error recording webhook delivery attempt: %v", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r webhookRepositories) GetDeliveries(ctx context.Context, subscriptionId int64, status string, limit int) (rs []*model.WebhookDelivery, err error) {
+	res := make([]*model.WebhookDelivery, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetWebhookDeliveries(), subscriptionId, status, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.WebhookDelivery)
+		if err := rows.Scan(deliveryFields(data)...); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		readDeliveryTimes(data)
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func (r webhookRepositories) GetDeliveryById(ctx context.Context, subscriptionId, id int64) (rs *model.WebhookDelivery, err error) {
+	data := new(model.WebhookDelivery)
+	var payload []byte
+	err = r.reader(ctx).QueryRowContext(ctx, config.GetWebhookDeliveryById(), subscriptionId, id).
+		Scan(append(deliveryFields(data), &payload)...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logrus.Errorf("Webhook delivery %v not found", id)
+		return nil, err
+	case err != nil:
+		logrus.Errorf("Error retrieving webhook delivery: %v", err)
+		return nil, err
+	}
+	data.Payload = payload
+	readDeliveryTimes(data)
+
+	data.AttemptLog, err = r.getAttempts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r webhookRepositories) getAttempts(ctx context.Context, deliveryId int64) ([]*model.WebhookAttempt, error) {
+	res := make([]*model.WebhookAttempt, 0)
+	rows, err := r.reader(ctx).QueryContext(ctx, config.GetWebhookAttempts(), deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := new(model.WebhookAttempt)
+		if err := rows.Scan(&data.AttemptedAt, &data.ResponseStatus, &data.Error, &data.DurationMs); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		data.AttemptedAt = readTime(data.AttemptedAt)
+		res = append(res, data)
+	}
+	return res, rows.Err()
+}
+
+func deliveryFields(data *model.WebhookDelivery) []interface{} {
+	return []interface{}{
+		&data.IdDelivery,
+		&data.IdSubscription,
+		&data.IdEvent,
+		&data.EventType,
+		&data.Status,
+		&data.Attempts,
+		&data.NextAttemptAt,
+		&data.LastAttemptAt,
+		&data.ResponseStatus,
+		&data.LastError,
+		&data.CreatedAt,
+	}
+}
+
+// readDeliveryTimes reports times in RFC 3339. The next attempt only
+// matters while the delivery is pending.
+func readDeliveryTimes(data *model.WebhookDelivery) {
+	data.NextAttemptAt = readTime(data.NextAttemptAt)
+	if data.Status != model.DeliveryPending {
+		data.NextAttemptAt = ""
+	}
+	data.LastAttemptAt = readTime(data.LastAttemptAt)
+	data.CreatedAt = readTime(data.CreatedAt)
+}
+
+func (r webhookRepositories) RedeliverDelivery(ctx context.Context, subscriptionId, id int64, at time.Time) (rs string, err error) {
+	_, err = r.DB.ExecContext(ctx, config.RedeliverWebhookDelivery(), at.UTC().Format(outboxTimeLayout), subscriptionId, id)
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return "", err
+	}
+	r.markWrite(ctx)
+	return "Delivery was scheduled for redelivery", nil
+}
+
+func (r webhookRepositories) DeleteFinishedDeliveries(ctx context.Context, before time.Time) (n int64, err error) {
+	res, err := r.DB.ExecContext(ctx, config.DeleteFinishedWebhookDeliveries(), before.UTC().Format(outboxTimeLayout))
+	if err != nil {
+		logrus.Errorf("Error on database %v", err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
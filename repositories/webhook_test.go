@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_webhookRepositories_EnqueueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 500000000, time.UTC)
+
+	payload := []byte(`{"sequence":3}`)
+	mock.ExpectExec(config.EnqueueWebhookDeliveries()).
+		WithArgs("e-3", model.EventEmployeeDeleted, payload, "2024-05-06 07:08:09.5", model.EventEmployeeDeleted).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	r := webhookRepositories{repositories: repositories{DB: db}}
+	n, err := r.EnqueueDeliveries(context.Background(), &model.Event{IdEvent: "e-3", Type: model.EventEmployeeDeleted}, payload, now)
+	if n != 2 || err != nil {
+		t.Errorf("EnqueueDeliveries() = %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_webhookRepositories_ClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(config.GetDueWebhookDeliveries()).WithArgs("2024-05-06 07:08:09", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "payload", "attempts", "url", "secret"}).
+			AddRow(4, 1, "e-1", model.EventEmployeeCreated, []byte(`{}`), 0, "https://a.example/", "s1").
+			AddRow(5, 2, "e-1", model.EventEmployeeCreated, []byte(`{}`), 3, "https://b.example/", "s2"))
+	mock.ExpectExec(config.LeaseWebhookDelivery()).WithArgs("2024-05-06 07:09:09", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(config.LeaseWebhookDelivery()).WithArgs("2024-05-06 07:09:09", 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := webhookRepositories{repositories: repositories{DB: db}}
+	got, err := r.ClaimDueDeliveries(context.Background(), now, 10, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries() error = %v", err)
+	}
+	want := &model.WebhookDelivery{IdDelivery: 5, IdSubscription: 2, IdEvent: "e-1", EventType: model.EventEmployeeCreated,
+		Status: model.DeliveryPending, Attempts: 3, Payload: []byte(`{}`), Url: "https://b.example/", Secret: "s2"}
+	if len(got) != 2 || !reflect.DeepEqual(got[1], want) {
+		t.Errorf("ClaimDueDeliveries() = %+v, want second %+v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_webhookRepositories_GetDeliveryById(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		logrus.Fatal("error creating mock")
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(config.GetWebhookDeliveryById()).WithArgs(1, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "status", "attempts",
+			"next_attempt_at", "last_attempt_at", "response_status", "last_error", "created_at", "payload"}).
+			AddRow(4, 1, "e-1", model.EventEmployeeCreated, model.DeliveryDeadLettered, 2, "2024-05-06 07:10:00",
+				"2024-05-06 07:09:00.25", 500, "500 Internal Server Error: ", "2024-05-06 07:08:00", []byte(`{"sequence":1}`)))
+	mock.ExpectQuery(config.GetWebhookAttempts()).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"attempted_at", "response_status", "error", "duration_ms"}).
+			AddRow("2024-05-06 07:08:30", 0, "connection refused", 3).
+			AddRow("2024-05-06 07:09:00.25", 500, "500 Internal Server Error: ", 12))
+
+	r := webhookRepositories{repositories: repositories{DB: db}}
+	got, err := r.GetDeliveryById(context.Background(), 1, 4)
+	if err != nil {
+		t.Fatalf("GetDeliveryById() error = %v", err)
+	}
+	want := &model.WebhookDelivery{IdDelivery: 4, IdSubscription: 1, IdEvent: "e-1", EventType: model.EventEmployeeCreated,
+		Status: model.DeliveryDeadLettered, Attempts: 2, LastAttemptAt: "2024-05-06T07:09:00.25Z", ResponseStatus: 500,
+		LastError: "500 Internal Server Error: ", CreatedAt: "2024-05-06T07:08:00Z", Payload: []byte(`{"sequence":1}`),
+		AttemptLog: []*model.WebhookAttempt{
+			{AttemptedAt: "2024-05-06T07:08:30Z", Error: "connection refused", DurationMs: 3},
+			{AttemptedAt: "2024-05-06T07:09:00.25Z", ResponseStatus: 500, Error: "500 Internal Server Error: ", DurationMs: 12},
+		}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDeliveryById() = %+v, want %+v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
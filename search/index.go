@@ -0,0 +1,176 @@
+// Package search is a small in-process inverted index with typo-tolerant,
+// ranked matching, used when the database offers no full-text index.
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Field is a named piece of text of a document. Weight scales the score of
+// matches in it. Digits fields, such as phone numbers, ignore everything but
+// digits and match any run of them, so "5550123" finds "+1 (555) 0123".
+type Field struct {
+	Name   string
+	Value  string
+	Weight float64
+	Digits bool
+}
+
+type Document struct {
+	ID     string
+	Fields []Field
+}
+
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index maps terms to the documents containing them. It is safe for
+// concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	postings map[string]map[string]float64 // term -> document -> best field weight
+	terms    map[string][]string           // document -> its terms, for removal
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		terms:    make(map[string][]string),
+	}
+}
+
+// Put adds doc, replacing any document with the same ID.
+func (x *Index) Put(doc Document) {
+	weights := make(map[string]float64)
+	for _, f := range doc.Fields {
+		for _, term := range fieldTerms(f) {
+			if f.Weight > weights[term] {
+				weights[term] = f.Weight
+			}
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(doc.ID)
+	terms := make([]string, 0, len(weights))
+	for term, weight := range weights {
+		docs := x.postings[term]
+		if docs == nil {
+			docs = make(map[string]float64)
+			x.postings[term] = docs
+		}
+		docs[doc.ID] = weight
+		terms = append(terms, term)
+	}
+	x.terms[doc.ID] = terms
+}
+
+func (x *Index) Delete(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+}
+
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.terms)
+}
+
+func (x *Index) remove(id string) {
+	for _, term := range x.terms[id] {
+		delete(x.postings[term], id)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	delete(x.terms, id)
+}
+
+// Search returns up to limit documents matching every term of query, best
+// first. Each query term scores its closest index term in a document,
+// weighted by field and by how rare the index term is.
+func (x *Index) Search(query string, limit int) []Hit {
+	queryTerms := Terms(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	n := float64(len(x.terms))
+	var scores map[string]float64
+	for _, q := range queryTerms {
+		best := make(map[string]float64)
+		for term, docs := range x.postings {
+			sim := Similarity(q, term)
+			if sim == 0 {
+				continue
+			}
+			idf := 1 + math.Log(n/float64(len(docs)))
+			for id, weight := range docs {
+				if s := sim * weight * idf; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+		if scores == nil {
+			scores = best
+			continue
+		}
+		for id, score := range scores {
+			if s, ok := best[id]; ok {
+				scores[id] = score + s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// fieldTerms lists the terms f is indexed under. A digits field is indexed
+// under every suffix of its digits, so prefix matching finds any run.
+func fieldTerms(f Field) []string {
+	if !f.Digits {
+		return Terms(f.Value)
+	}
+	digits := onlyDigits(f.Value)
+	var terms []string
+	for i := 0; i+minDigitRun <= len(digits); i++ {
+		terms = append(terms, digits[i:])
+	}
+	return terms
+}
+
+// minDigitRun is the shortest run of digits a digits field is found by.
+const minDigitRun = 3
+
+func onlyDigits(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
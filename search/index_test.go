@@ -0,0 +1,115 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func employee(id, first, last, email, phone string) Document {
+	return Document{ID: id, Fields: []Field{
+		{Name: "firstName", Value: first, Weight: 1.5},
+		{Name: "lastName", Value: last, Weight: 1.5},
+		{Name: "email", Value: email, Weight: 1},
+		{Name: "phone", Value: phone, Weight: 1, Digits: true},
+	}}
+}
+
+func ids(hits []Hit) []string {
+	res := []string{}
+	for _, h := range hits {
+		res = append(res, h.ID)
+	}
+	return res
+}
+
+func TestIndex_Search(t *testing.T) {
+	x := NewIndex()
+	x.Put(employee("1", "John", "Smith", "john.smith@example.com", "+1 (555) 010-1234"))
+	x.Put(employee("2", "Jonathan", "Smithers", "jon@example.com", "555 777 8888"))
+	x.Put(employee("3", "Jane", "Doe", "jsmith@corp.example", "020 7946 0000"))
+	x.Put(employee("4", "Mary", "Johnson", "mary@example.com", "0101234"))
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "exact name ranks above prefix and typo", query: "smith", want: []string{"1", "2", "3"}},
+		{name: "every term must match", query: "john smith", want: []string{"1", "2"}},
+		{name: "typo", query: "jonh", want: []string{"1", "2"}},
+		{name: "transposition", query: "smtih", want: []string{"1"}},
+		{name: "typo across terms", query: "jonathon smithers", want: []string{"2"}},
+		{name: "exact email term ranks above typo in name", query: "jsmith", want: []string{"3", "1"}},
+		{name: "phone digits across punctuation", query: "0101234", want: []string{"1", "4"}},
+		{name: "numbers are not fuzzy", query: "5550", want: []string{"1"}},
+		{name: "no match", query: "zebra", want: []string{}},
+		{name: "no terms", query: "  -- ", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(x.Search(tt.query, 10)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if got := ids(x.Search("smith", 1)); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("Search() with limit = %v", got)
+	}
+}
+
+func TestIndex_PutDelete(t *testing.T) {
+	x := NewIndex()
+	x.Put(employee("1", "John", "Smith", "", ""))
+	x.Put(employee("1", "Johanna", "Smith", "", ""))
+	if got := ids(x.Search("john", 10)); len(got) != 0 {
+		t.Errorf("replaced terms still match: %v", got)
+	}
+	if got := ids(x.Search("johanna", 10)); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("Search() = %v", got)
+	}
+	x.Delete("1")
+	if x.Len() != 0 || len(x.postings) != 0 {
+		t.Errorf("Delete() left %d documents and %d terms", x.Len(), len(x.postings))
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		q, term string
+		want    float64
+	}{
+		{"john", "john", 1},
+		{"jo", "john", 0.8},
+		{"j", "john", 0},
+		{"jonh", "john", 0.6},
+		{"jhn", "john", 0},
+		{"catherine", "katharine", 0.5},
+		{"catherine", "kathryn", 0},
+		{"müller", "muller", 0.6},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.q, tt.term); got != tt.want {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.q, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		query string
+		field Field
+		want  string
+	}{
+		{"jonh", Field{Value: "John <Jr>"}, "<em>John</em> &lt;Jr&gt;"},
+		{"smith", Field{Value: "john.smith@example.com"}, "john.<em>smith</em>@example.com"},
+		{"doe", Field{Value: "John"}, ""},
+		{"5550101", Field{Value: "+1 (555) 010-1234", Digits: true}, "+1 (<em>555) 010-1</em>234"},
+		{"1234 smith", Field{Value: "555 1234", Digits: true}, "555 <em>1234</em>"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.query, tt.field); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.query, tt.field.Value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Terms splits s into lower-cased runs of letters and digits, so
+// "Jane.Doe@example.com" becomes jane, doe, example and com.
+func Terms(s string) []string {
+	var terms []string
+	for _, t := range tokens(s) {
+		terms = append(terms, t.term)
+	}
+	return terms
+}
+
+type token struct {
+	term       string
+	start, end int // byte offsets in the source text
+}
+
+func tokens(s string) []token {
+	var res []token
+	start := -1
+	for i, r := range s {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			res = append(res, token{term: strings.ToLower(s[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, token{term: strings.ToLower(s[start:]), start: start, end: len(s)})
+	}
+	return res
+}
+
+// Similarity scores how well index term matches query term q: 1 for the
+// same term, 0.8 when q is a prefix of it, and 0.6 or 0.5 for one or two
+// typos. Typos are only tolerated in longer words, and never in numbers.
+func Similarity(q, term string) float64 {
+	switch {
+	case q == term:
+		return 1
+	case len(q) >= 2 && strings.HasPrefix(term, q):
+		return 0.8
+	}
+	edits := maxEdits(q)
+	if edits == 0 {
+		return 0
+	}
+	ql, tl := utf8.RuneCountInString(q), utf8.RuneCountInString(term)
+	if tl-ql > edits || ql-tl > edits {
+		return 0
+	}
+	switch d := distance(q, term, edits); {
+	case d > edits:
+		return 0
+	case d == 1:
+		return 0.6
+	default:
+		return 0.5
+	}
+}
+
+func maxEdits(q string) int {
+	if onlyDigits(q) == q {
+		return 0
+	}
+	switch n := utf8.RuneCountInString(q); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b, where
+// swapping two adjacent letters counts as one edit. Distances above limit
+// are reported as limit+1.
+func distance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d := prev[j-1] + cost
+			if v := prev[j] + 1; v < d {
+				d = v
+			}
+			if v := cur[j-1] + 1; v < d {
+				d = v
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				if v := prev2[j-2] + 1; v < d {
+					d = v
+				}
+			}
+			cur[j] = d
+			if d < rowMin {
+				rowMin = d
+			}
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(rb)] > limit {
+		return limit + 1
+	}
+	return prev[len(rb)]
+}
+
+// Highlight returns text HTML-escaped with the words matching query wrapped
+// in <em>, or "" when nothing in text matches. For digits fields the run
+// of digits matching a query number is wrapped instead.
+func Highlight(query string, f Field) string {
+	queryTerms := Terms(query)
+	var spans [][2]int
+	if f.Digits {
+		spans = digitSpans(queryTerms, f.Value)
+	} else {
+		for _, t := range tokens(f.Value) {
+			for _, q := range queryTerms {
+				if Similarity(q, t.term) > 0 {
+					spans = append(spans, [2]int{t.start, t.end})
+					break
+				}
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s[0] < last {
+			s[0] = last
+		}
+		if s[1] <= s[0] {
+			continue
+		}
+		b.WriteString(html.EscapeString(f.Value[last:s[0]]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(f.Value[s[0]:s[1]]))
+		b.WriteString("</em>")
+		last = s[1]
+	}
+	b.WriteString(html.EscapeString(f.Value[last:]))
+	return b.String()
+}
+
+// digitSpans finds, in source order, the byte ranges of value whose digits
+// form one of the numeric query terms.
+func digitSpans(queryTerms []string, value string) [][2]int {
+	var offsets []int // byte offset of each digit of value
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			offsets = append(offsets, i)
+		}
+	}
+	digits := onlyDigits(value)
+	covered := make([]bool, len(digits))
+	for _, q := range queryTerms {
+		if len(q) < minDigitRun || onlyDigits(q) != q {
+			continue
+		}
+		if i := strings.Index(digits, q); i >= 0 {
+			for k := i; k < i+len(q); k++ {
+				covered[k] = true
+			}
+		}
+	}
+	var spans [][2]int
+	for i := 0; i < len(covered); i++ {
+		if !covered[i] {
+			continue
+		}
+		j := i
+		for j+1 < len(covered) && covered[j+1] {
+			j++
+		}
+		spans = append(spans, [2]int{offsets[i], offsets[j] + 1})
+		i = j
+	}
+	return spans
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
+)
+
+type attendanceService struct {
+	repository repositories.IAttendanceRepositories
+	employees  repositories.IEmployeeRepositories
+	rules      config.OvertimeRules
+	weekStart  time.Weekday
+	loc        *time.Location
+	maxShift   time.Duration
+	clockSkew  time.Duration
+	now        func() time.Time
+}
+
+func NewAttendanceService() IAttendanceService {
+	return &attendanceService{
+		repository: repositories.NewAttendanceRepositories(),
+		employees:  repositories.NewEmployeeRepositories(),
+		rules:      config.GetOvertimeRules(),
+		weekStart:  config.GetWeekStart(),
+		loc:        config.GetAttendanceLocation(),
+		maxShift:   config.GetMaxShift(),
+		clockSkew:  config.GetClockSkew(),
+		now:        time.Now,
+	}
+}
+
+type IAttendanceService interface {
+	Clock(ctx context.Context, employeeId, kind string, clock *model.Clock) (rs *model.AttendanceEvent, err error)
+	GetAttendance(ctx context.Context, employeeId, from, to string) (rs []*model.AttendanceEvent, err error)
+	GetTimesheet(ctx context.Context, employeeId, date string) (rs *model.Timesheet, err error)
+	GetHoursReport(ctx context.Context, from, to, period string) (rs []*model.HoursReportRow, err error)
+}
+
+// Clock records a clock-in or clock-out. Events must alternate and move
+// forward in time, no further than now, and only a clock-in may open a
+// session. A clock-out after the longest shift is capped to it.
+func (s attendanceService) Clock(ctx context.Context, employeeId, kind string, clock *model.Clock) (rs *model.AttendanceEvent, err error) {
+	at := s.now()
+	if clock.Time != "" {
+		if at, err = time.Parse(time.RFC3339, clock.Time); err != nil {
+			return nil, err
+		}
+		if at.After(s.now().Add(s.clockSkew)) {
+			return nil, model.ErrClockFuture
+		}
+	}
+
+	rs = &model.AttendanceEvent{
+		IdEmployee: employeeId,
+		Kind:       kind,
+		OccurredAt: at.UTC().Format(time.RFC3339),
+		Note:       strings.TrimSpace(clock.Note),
+	}
+	_, err = s.repository.InsertAttendanceEvent(ctx, rs, func(employee *model.Employee, last *model.AttendanceEvent) error {
+		open := last != nil && last.Kind == model.ClockIn
+		switch {
+		case kind == model.ClockIn && employee.Status == model.StatusTerminated:
+			return model.ErrClockInactive
+		case kind == model.ClockIn && open:
+			return model.ErrAlreadyClockedIn
+		case kind == model.ClockOut && !open:
+			return model.ErrNotClockedIn
+		}
+		if last == nil {
+			return nil
+		}
+		previous, errLast := time.Parse(time.RFC3339, last.OccurredAt)
+		if errLast == nil && !at.After(previous) {
+			return model.ErrClockOrder
+		}
+		if errLast == nil && open && at.Sub(previous) > s.maxShift {
+			logrus.Warnf("capping clock-out of employee %s at %s after clock-in at %s", employeeId, s.maxShift, last.OccurredAt)
+			rs.OccurredAt, rs.Capped = previous.Add(s.maxShift).UTC().Format(time.RFC3339), true
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetAttendance lists the employee's clock events between the from and to
+// dates inclusive, the current week when both are empty.
+func (s attendanceService) GetAttendance(ctx context.Context, employeeId, from, to string) (rs []*model.AttendanceEvent, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	start, end, err := s.period(from, to)
+	if err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetAttendanceEvents(ctx, employeeId, start, end)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetTimesheet aggregates the week containing date, today when empty.
+func (s attendanceService) GetTimesheet(ctx context.Context, employeeId, date string) (rs *model.Timesheet, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	day := s.now().In(s.loc)
+	if date != "" {
+		if day, err = time.ParseInLocation(dateLayout, date, s.loc); err != nil {
+			return nil, fmt.Errorf("%w: %s", model.ErrReportPeriod, err)
+		}
+	}
+	start := s.startOfWeek(day)
+	end := start.AddDate(0, 0, 7)
+
+	events, err := s.repository.GetAttendanceEvents(ctx, employeeId, start.Add(-s.maxShift), end)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	worked, open := s.workedPerDay(events, start, end)
+	days := s.classify(worked)
+
+	rs = &model.Timesheet{
+		IdEmployee: employeeId,
+		WeekStart:  start.Format(dateLayout),
+		WeekEnd:    end.AddDate(0, 0, -1).Format(dateLayout),
+		Open:       open,
+		Days:       make([]*model.TimesheetDay, 0, 7),
+	}
+	var total shift
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format(dateLayout)
+		total = total.add(days[key])
+		rs.Days = append(rs.Days, days[key].day(key))
+	}
+	rs.Hours, rs.RegularHours, rs.OvertimeHours = total.hours()
+	return rs, nil
+}
+
+// GetHoursReport sums every employee's hours per day, week or month
+// between the from and to dates inclusive. Overtime is worked out over whole
+// weeks so a period cutting a week in two still sees the weekly rule.
+func (s attendanceService) GetHoursReport(ctx context.Context, from, to, period string) (rs []*model.HoursReportRow, err error) {
+	start, end, err := s.period(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if period == "" {
+		period = "week"
+	}
+	if period != "day" && period != "week" && period != "month" {
+		return nil, fmt.Errorf("%w: period must be day, week or month", model.ErrReportPeriod)
+	}
+	weeksStart := s.startOfWeek(start)
+	weeksEnd := s.startOfWeek(end.AddDate(0, 0, -1)).AddDate(0, 0, 7)
+
+	events, err := s.repository.GetAllAttendanceEvents(ctx, weeksStart.Add(-s.maxShift), weeksEnd)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	byEmployee := map[string][]*model.AttendanceEvent{}
+	var ids []string
+	for _, event := range events {
+		if _, ok := byEmployee[event.IdEmployee]; !ok {
+			ids = append(ids, event.IdEmployee)
+		}
+		byEmployee[event.IdEmployee] = append(byEmployee[event.IdEmployee], event)
+	}
+	sort.Strings(ids)
+
+	rs = make([]*model.HoursReportRow, 0)
+	for _, id := range ids {
+		worked, _ := s.workedPerDay(byEmployee[id], weeksStart, weeksEnd)
+		days := s.classify(worked)
+
+		periods := map[string]shift{}
+		var keys []string
+		for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+			day, ok := days[d.Format(dateLayout)]
+			if !ok {
+				continue
+			}
+			key := s.periodStart(d, period).Format(dateLayout)
+			if _, seen := periods[key]; !seen {
+				keys = append(keys, key)
+			}
+			periods[key] = periods[key].add(day)
+		}
+		for _, key := range keys {
+			periodStart, _ := time.ParseInLocation(dateLayout, key, s.loc)
+			row := &model.HoursReportRow{
+				IdEmployee:  id,
+				PeriodStart: key,
+				PeriodEnd:   s.periodEnd(periodStart, period).Format(dateLayout),
+			}
+			row.Hours, row.RegularHours, row.OvertimeHours = periods[key].hours()
+			rs = append(rs, row)
+		}
+	}
+	return rs, nil
+}
+
+// period parses an inclusive from - to date range into [start, end),
+// defaulting to the current week.
+func (s attendanceService) period(from, to string) (start, end time.Time, err error) {
+	start = s.startOfWeek(s.now().In(s.loc))
+	end = start.AddDate(0, 0, 7)
+	if from != "" {
+		if start, err = time.ParseInLocation(dateLayout, from, s.loc); err != nil {
+			return start, end, fmt.Errorf("%w: %s", model.ErrReportPeriod, err)
+		}
+		end = start.AddDate(0, 0, 7)
+	}
+	if to != "" {
+		last, errTo := time.ParseInLocation(dateLayout, to, s.loc)
+		if errTo != nil {
+			return start, end, fmt.Errorf("%w: %s", model.ErrReportPeriod, errTo)
+		}
+		end = last.AddDate(0, 0, 1)
+	}
+	if !end.After(start) || end.After(start.AddDate(1, 0, 1)) {
+		return start, end, fmt.Errorf("%w: to must be on or after from and at most a year later", model.ErrReportPeriod)
+	}
+	return start, end, nil
+}
+
+func (s attendanceService) startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.loc)
+	offset := (int(day.Weekday()) - int(s.weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func (s attendanceService) periodStart(day time.Time, period string) time.Time {
+	switch period {
+	case "day":
+		return day
+	case "month":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, s.loc)
+	default:
+		return s.startOfWeek(day)
+	}
+}
+
+func (s attendanceService) periodEnd(start time.Time, period string) time.Time {
+	switch period {
+	case "day":
+		return start
+	case "month":
+		return start.AddDate(0, 1, -1)
+	default:
+		return start.AddDate(0, 0, 6)
+	}
+}
+
+// workedPerDay pairs clock-ins with the following clock-out and splits each
+// session at midnight, counting only the part inside [start, end). A session
+// longer than the longest shift counts as that shift; one still open at the
+// end is not counted and reported as open.
+func (s attendanceService) workedPerDay(events []*model.AttendanceEvent, start, end time.Time) (map[string]time.Duration, bool) {
+	worked := map[string]time.Duration{}
+	var openedAt *time.Time
+	for _, event := range events {
+		at, err := time.Parse(time.RFC3339, event.OccurredAt)
+		if err != nil {
+			continue
+		}
+		at = at.In(s.loc)
+		switch {
+		case event.Kind == model.ClockIn:
+			openedAt = &at
+		case event.Kind == model.ClockOut && openedAt != nil:
+			if at.Sub(*openedAt) > s.maxShift {
+				at = openedAt.Add(s.maxShift)
+			}
+			s.addSession(worked, *openedAt, at, start, end)
+			openedAt = nil
+		}
+	}
+	return worked, openedAt != nil
+}
+
+func (s attendanceService) addSession(worked map[string]time.Duration, from, to, start, end time.Time) {
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	for from.Before(to) {
+		midnight := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, s.loc).AddDate(0, 0, 1)
+		until := to
+		if midnight.Before(until) {
+			until = midnight
+		}
+		worked[from.Format(dateLayout)] += until.Sub(from)
+		from = until
+	}
+}
+
+// classify splits each day's time into regular and overtime: first the part
+// of a day beyond the daily threshold, then regular time beyond the weekly
+// threshold, counted in day order within each week.
+func (s attendanceService) classify(worked map[string]time.Duration) map[string]shift {
+	dates := make([]string, 0, len(worked))
+	for date := range worked {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	days := map[string]shift{}
+	weekly := map[string]time.Duration{}
+	for _, date := range dates {
+		day, _ := time.ParseInLocation(dateLayout, date, s.loc)
+		week := s.startOfWeek(day).Format(dateLayout)
+
+		total := worked[date]
+		regular := total
+		if s.rules.Daily > 0 && regular > s.rules.Daily {
+			regular = s.rules.Daily
+		}
+		if s.rules.Weekly > 0 && weekly[week]+regular > s.rules.Weekly {
+			regular = s.rules.Weekly - weekly[week]
+			if regular < 0 {
+				regular = 0
+			}
+		}
+		weekly[week] += regular
+		days[date] = shift{total: total, regular: regular}
+	}
+	return days
+}
+
+// shift is worked time split into its regular part; the rest is overtime.
+type shift struct {
+	total   time.Duration
+	regular time.Duration
+}
+
+func (s shift) add(other shift) shift {
+	return shift{total: s.total + other.total, regular: s.regular + other.regular}
+}
+
+func (s shift) day(date string) *model.TimesheetDay {
+	day := &model.TimesheetDay{Date: date}
+	day.Hours, day.RegularHours, day.OvertimeHours = s.hours()
+	return day
+}
+
+func (s shift) hours() (total, regular, overtime model.Quantity) {
+	return toHours(s.total), toHours(s.regular), toHours(s.total - s.regular)
+}
+
+func toHours(d time.Duration) model.Quantity {
+	return model.NewQuantity(decimal.NewFromInt(int64(d/time.Second)).DivRound(decimal.NewFromInt(3600), 2))
+}
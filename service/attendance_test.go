@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+type fakeAttendanceRepositories struct {
+	repositories.IAttendanceRepositories
+	employee *model.Employee
+	events   []*model.AttendanceEvent
+}
+
+func (r *fakeAttendanceRepositories) InsertAttendanceEvent(_ context.Context, event *model.AttendanceEvent, check func(*model.Employee, *model.AttendanceEvent) error) (string, error) {
+	var last *model.AttendanceEvent
+	if len(r.events) > 0 {
+		last = r.events[len(r.events)-1]
+	}
+	employee := r.employee
+	if employee == nil {
+		employee = &model.Employee{IdEmployee: event.IdEmployee, Status: model.StatusActive}
+	}
+	if err := check(employee, last); err != nil {
+		return "", err
+	}
+	r.events = append(r.events, event)
+	return "Clocked " + event.Kind, nil
+}
+
+func (r *fakeAttendanceRepositories) GetAttendanceEvents(_ context.Context, _ string, _, _ time.Time) ([]*model.AttendanceEvent, error) {
+	return r.events, nil
+}
+
+func (r *fakeAttendanceRepositories) GetAllAttendanceEvents(_ context.Context, _, _ time.Time) ([]*model.AttendanceEvent, error) {
+	return r.events, nil
+}
+
+func newTestAttendanceService(repository *fakeAttendanceRepositories, now time.Time) attendanceService {
+	return attendanceService{
+		repository: repository,
+		employees:  &leaveEmployeeRepositories{employee: &model.Employee{IdEmployee: "1", Status: model.StatusActive}},
+		rules:      config.OvertimeRules{Daily: 8 * time.Hour, Weekly: 40 * time.Hour},
+		weekStart:  time.Monday,
+		loc:        time.UTC,
+		maxShift:   24 * time.Hour,
+		now:        func() time.Time { return now },
+	}
+}
+
+// shifts builds clock events for sessions given as RFC 3339 start/end pairs.
+func shifts(employeeId string, sessions ...string) []*model.AttendanceEvent {
+	var events []*model.AttendanceEvent
+	for i, at := range sessions {
+		kind := model.ClockIn
+		if i%2 == 1 {
+			kind = model.ClockOut
+		}
+		events = append(events, &model.AttendanceEvent{IdEmployee: employeeId, Kind: kind, OccurredAt: at})
+	}
+	return events
+}
+
+func Test_attendanceService_Clock(t *testing.T) {
+	repository := &fakeAttendanceRepositories{}
+	now := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	s := newTestAttendanceService(repository, now)
+	ctx := context.Background()
+
+	if _, err := s.Clock(ctx, "1", model.ClockOut, &model.Clock{}); !errors.Is(err, model.ErrNotClockedIn) {
+		t.Errorf("clock-out before clock-in error = %v", err)
+	}
+	if _, err := s.Clock(ctx, "1", model.ClockIn, &model.Clock{}); err != nil {
+		t.Fatalf("clock-in error = %v", err)
+	}
+	if _, err := s.Clock(ctx, "1", model.ClockIn, &model.Clock{}); !errors.Is(err, model.ErrAlreadyClockedIn) {
+		t.Errorf("second clock-in error = %v", err)
+	}
+	if _, err := s.Clock(ctx, "1", model.ClockOut, &model.Clock{Time: "2024-03-11T08:00:00Z"}); !errors.Is(err, model.ErrClockOrder) {
+		t.Errorf("clock-out before clock-in time error = %v", err)
+	}
+	if _, err := s.Clock(ctx, "1", model.ClockOut, &model.Clock{Time: "2024-03-11T17:30:00Z"}); !errors.Is(err, model.ErrClockFuture) {
+		t.Errorf("clock-out in the future error = %v", err)
+	}
+	s.now = func() time.Time { return now.Add(9 * time.Hour) }
+	if _, err := s.Clock(ctx, "1", model.ClockOut, &model.Clock{Time: "2024-03-11T17:30:00Z"}); err != nil {
+		t.Errorf("clock-out error = %v", err)
+	}
+}
+
+func Test_attendanceService_Clock_capped(t *testing.T) {
+	repository := &fakeAttendanceRepositories{events: shifts("1", "2024-03-11T09:00:00Z")}
+	s := newTestAttendanceService(repository, time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC))
+
+	// a forgotten clock-out ends the session at the longest shift and is
+	// flagged, rather than dropping the session from timesheets
+	rs, err := s.Clock(context.Background(), "1", model.ClockOut, &model.Clock{})
+	if err != nil {
+		t.Fatalf("Clock() error = %v", err)
+	}
+	if rs.OccurredAt != "2024-03-12T09:00:00Z" || !rs.Capped {
+		t.Errorf("Clock() = %+v, want a capped clock-out at 2024-03-12T09:00:00Z", rs)
+	}
+	timesheet, err := s.GetTimesheet(context.Background(), "1", "2024-03-11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !timesheet.Hours.Equal(decimal.NewFromInt(24)) {
+		t.Errorf("GetTimesheet() hours = %s, want 24", timesheet.Hours)
+	}
+}
+
+func Test_attendanceService_GetTimesheet(t *testing.T) {
+	// Monday to Friday: 10h, 9h, 8h, 9h and a night shift from Friday 20:00
+	// to Saturday 06:00 split across midnight
+	repository := &fakeAttendanceRepositories{events: shifts("1",
+		"2024-03-11T08:00:00Z", "2024-03-11T18:00:00Z",
+		"2024-03-12T08:00:00Z", "2024-03-12T17:00:00Z",
+		"2024-03-13T08:00:00Z", "2024-03-13T16:00:00Z",
+		"2024-03-14T08:00:00Z", "2024-03-14T17:00:00Z",
+		"2024-03-15T20:00:00Z", "2024-03-16T06:00:00Z",
+		"2024-03-17T09:00:00Z",
+	)}
+	s := newTestAttendanceService(repository, time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC))
+
+	rs, err := s.GetTimesheet(context.Background(), "1", "2024-03-13")
+	if err != nil {
+		t.Fatalf("GetTimesheet() error = %v", err)
+	}
+	if rs.WeekStart != "2024-03-11" || rs.WeekEnd != "2024-03-17" || len(rs.Days) != 7 || !rs.Open {
+		t.Errorf("GetTimesheet() week = %s - %s, %d days, open %v", rs.WeekStart, rs.WeekEnd, len(rs.Days), rs.Open)
+	}
+	// daily overtime: 2h Monday, 1h Tuesday, 1h Thursday; regular so far 32h,
+	// Friday 4h brings it to 36h, Saturday's 6h passes 40h by 2h
+	want := map[string][2]string{
+		"total":    {rs.Hours.String(), "46"},
+		"regular":  {rs.RegularHours.String(), "40"},
+		"overtime": {rs.OvertimeHours.String(), "6"},
+		"saturday": {rs.Days[5].OvertimeHours.String(), "2"},
+		"friday":   {rs.Days[4].Hours.String(), "4"},
+	}
+	for name, v := range want {
+		if !decimal.RequireFromString(v[0]).Equal(decimal.RequireFromString(v[1])) {
+			t.Errorf("GetTimesheet() %s = %s, want %s", name, v[0], v[1])
+		}
+	}
+}
+
+func Test_attendanceService_GetHoursReport(t *testing.T) {
+	events := append(shifts("2", "2024-03-01T09:00:00Z", "2024-03-01T17:00:00Z"),
+		shifts("1", "2024-02-29T09:00:00Z", "2024-02-29T19:00:00Z", "2024-03-04T09:00:00Z", "2024-03-04T12:30:00Z")...)
+	s := newTestAttendanceService(&fakeAttendanceRepositories{events: events}, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC))
+
+	rs, err := s.GetHoursReport(context.Background(), "2024-03-01", "2024-03-31", "month")
+	if err != nil {
+		t.Fatalf("GetHoursReport() error = %v", err)
+	}
+	if len(rs) != 2 || rs[0].IdEmployee != "1" || rs[0].PeriodStart != "2024-03-01" || rs[0].PeriodEnd != "2024-03-31" ||
+		!rs[0].Hours.Equal(decimal.RequireFromString("3.5")) || !rs[1].Hours.Equal(decimal.NewFromInt(8)) {
+		t.Errorf("GetHoursReport() = %+v", rs)
+	}
+
+	if _, err := s.GetHoursReport(context.Background(), "2024-03-01", "2024-03-31", "year"); !errors.Is(err, model.ErrReportPeriod) {
+		t.Errorf("GetHoursReport() error = %v, want %v", err, model.ErrReportPeriod)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/repositories"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type compensationService struct {
+	repository repositories.ICompensationRepositories
+	employees  repositories.IEmployeeRepositories
+	positions  repositories.IPositionRepositories
+	now        func() time.Time
+}
+
+func NewCompensationService() ICompensationService {
+	return &compensationService{
+		repository: repositories.NewCompensationRepositories(),
+		employees:  newEmployeeRepositories(),
+		positions:  repositories.NewPositionRepositories(),
+		now:        time.Now,
+	}
+}
+
+type ICompensationService interface {
+	ScheduleCompensation(ctx context.Context, compensation *model.Compensation) (rs string, err error)
+	GetCompensationHistory(ctx context.Context, employeeId string) (rs []*model.Compensation, err error)
+	GetCompensationAsOf(ctx context.Context, employeeId, date string) (rs *model.Compensation, err error)
+}
+
+// ScheduleCompensation adds a salary change taking effect on its effective
+// date, which may be today or later but not in the past. The salary must be
+// within the band of the employee's position unless an override reason is
+// given.
+func (s compensationService) ScheduleCompensation(ctx context.Context, compensation *model.Compensation) (rs string, err error) {
+	employee, err := s.employees.GetEmployeeById(ctx, compensation.IdEmployee)
+	if err != nil {
+		return "", err
+	}
+	// a change without a currency keeps paying in the current one
+	compensation.Currency = strings.ToUpper(strings.TrimSpace(compensation.Currency))
+	if compensation.Currency == "" {
+		compensation.Currency = employee.Currency
+	}
+	if compensation.Currency == "" {
+		compensation.Currency = config.GetDefaultCurrency()
+	}
+	compensation.Salary = model.NewMoney(money.Round(compensation.Salary.Decimal, compensation.Currency))
+	if compensation.EffectiveDate < s.now().Format(dateLayout) {
+		return "", model.ErrEffectiveDatePast
+	}
+	changed := *employee
+	changed.Salary, changed.Currency = compensation.Salary, compensation.Currency
+	changed.SalaryOverrideReason = compensation.SalaryOverrideReason
+	override, err := checkSalaryBand(ctx, s.positions, &changed)
+	if err != nil {
+		return "", err
+	}
+	rs, err = s.employees.ScheduleCompensation(ctx, compensation, override)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	logSalaryOverride(override)
+	return rs, nil
+}
+
+func (s compensationService) GetCompensationHistory(ctx context.Context, employeeId string) (rs []*model.Compensation, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetCompensationHistory(ctx, employeeId)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetCompensationAsOf returns the compensation in effect on date, or today
+// when date is empty.
+func (s compensationService) GetCompensationAsOf(ctx context.Context, employeeId, date string) (rs *model.Compensation, err error) {
+	if date == "" {
+		date = s.now().Format(dateLayout)
+	}
+	rs, err = s.repository.GetCompensationAsOf(ctx, employeeId, date)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
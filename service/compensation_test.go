@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+type fakeLookupRepositories struct {
+	fakeEmployeeRepositories
+}
+
+func (r *fakeLookupRepositories) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	return &model.Employee{IdEmployee: id, Salary: model.NewMoney(decimal.NewFromInt(50000)), Currency: "USD"}, nil
+}
+
+type schedulingRepositories struct {
+	fakeLookupRepositories
+	scheduled []*model.Compensation
+	overrides []*model.SalaryOverride
+}
+
+func (r *schedulingRepositories) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	return &model.Employee{IdEmployee: id, Salary: model.NewMoney(decimal.NewFromInt(60000)), Currency: "USD", PositionId: "P1"}, nil
+}
+
+func (r *schedulingRepositories) ScheduleCompensation(_ context.Context, compensation *model.Compensation, override *model.SalaryOverride) (string, error) {
+	r.scheduled = append(r.scheduled, compensation)
+	if override != nil {
+		r.overrides = append(r.overrides, override)
+	}
+	return "Compensation was scheduled", nil
+}
+
+func Test_compensationService_ScheduleCompensation(t *testing.T) {
+	today := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		effectiveDate  string
+		salary         int64
+		overrideReason string
+		wantErr        error
+		wantOverrides  int
+	}{
+		{name: "future raise", effectiveDate: "2024-04-01", salary: 65000},
+		{name: "effective today", effectiveDate: "2024-03-15", salary: 65000},
+		{name: "backdated raise", effectiveDate: "2024-03-14", salary: 65000, wantErr: model.ErrEffectiveDatePast},
+		{name: "raise above band", effectiveDate: "2024-04-01", salary: 90000, wantErr: model.ErrSalaryOutOfBand},
+		{name: "raise above band with override reason", effectiveDate: "2024-04-01", salary: 90000, overrideReason: "retention offer", wantOverrides: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employees := &schedulingRepositories{}
+			s := compensationService{
+				repository: &fakeCompensationRepositories{},
+				employees:  employees,
+				positions:  &fakePositionRepositories{},
+				now:        func() time.Time { return today },
+			}
+			_, err := s.ScheduleCompensation(context.Background(), &model.Compensation{
+				IdEmployee:           "1",
+				Salary:               model.NewMoney(decimal.NewFromInt(tt.salary)),
+				EffectiveDate:        tt.effectiveDate,
+				SalaryOverrideReason: tt.overrideReason,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ScheduleCompensation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if stored := len(employees.scheduled) == 1; stored != (tt.wantErr == nil) {
+				t.Errorf("ScheduleCompensation() stored = %v", stored)
+			}
+			if len(employees.overrides) != tt.wantOverrides {
+				t.Errorf("ScheduleCompensation() recorded %d overrides, want %d", len(employees.overrides), tt.wantOverrides)
+			}
+		})
+	}
+}
+
+func Test_service_UpdateEmployee_recordsCompensation(t *testing.T) {
+	repository := &updatingRepositories{}
+	s := service{
+		repository: repository,
+		positions:  &fakePositionRepositories{},
+		now:        func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) },
+	}
+
+	if _, err := s.UpdateEmployee(context.Background(), &model.Employee{IdEmployee: "1", Salary: model.NewMoney(decimal.NewFromInt(52000))}); err != nil {
+		t.Fatalf("UpdateEmployee() error = %v", err)
+	}
+	if len(repository.updated) != 1 {
+		t.Fatalf("UpdateEmployee() stored %d employees, want 1", len(repository.updated))
+	}
+	compensation := repository.updated[0].Compensation
+	if compensation == nil || compensation.EffectiveDate != "2024-03-15" || !compensation.Salary.Equal(decimal.NewFromInt(52000)) {
+		t.Errorf("salary change should be written with history effective today, got %+v", compensation)
+	}
+}
+
+type updatingRepositories struct {
+	fakeLookupRepositories
+	updated []*model.Employee
+}
+
+func (r *updatingRepositories) UpdateEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	r.updated = append(r.updated, employee)
+	return "Employee was edited", nil
+}
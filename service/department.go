@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+type departmentService struct {
+	repository repositories.IDepartmentRepositories
+}
+
+func NewDepartmentService() IDepartmentService {
+	return &departmentService{
+		repository: repositories.NewDepartmentRepositories(),
+	}
+}
+
+type IDepartmentService interface {
+	GetDepartments(ctx context.Context) (rs []*model.Department, err error)
+	GetDepartmentById(ctx context.Context, id string) (rs *model.Department, err error)
+	GetDepartmentsByIds(ctx context.Context, ids []string) (rs []*model.Department, err error)
+	GetDepartmentEmployees(ctx context.Context, id string) (rs []*model.Employee, err error)
+	InsertDepartment(ctx context.Context, department *model.Department) (rs string, err error)
+	UpdateDepartment(ctx context.Context, department *model.Department) (rs string, err error)
+	DeleteDepartment(ctx context.Context, id string) (rs string, err error)
+}
+
+func (s departmentService) GetDepartments(ctx context.Context) (rs []*model.Department, err error) {
+	rs, err = s.repository.GetDepartment(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s departmentService) GetDepartmentById(ctx context.Context, id string) (rs *model.Department, err error) {
+	rs, err = s.repository.GetDepartmentById(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetDepartmentEmployees lists the employees of a department, failing with
+// sql.ErrNoRows when the department itself does not exist.
+func (s departmentService) GetDepartmentEmployees(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	if _, err = s.repository.GetDepartmentById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetDepartmentEmployees(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s departmentService) InsertDepartment(ctx context.Context, department *model.Department) (rs string, err error) {
+	rs, err = s.repository.InsertDepartment(ctx, department)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s departmentService) UpdateDepartment(ctx context.Context, department *model.Department) (rs string, err error) {
+	rs, err = s.repository.UpdateDepartment(ctx, department)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s departmentService) DeleteDepartment(ctx context.Context, id string) (rs string, err error) {
+	rs, err = s.repository.DeleteDepartment(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s departmentService) GetDepartmentsByIds(ctx context.Context, ids []string) (rs []*model.Department, err error) {
+	rs, err = s.repository.GetDepartmentsByIds(ctx, ids)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
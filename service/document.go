@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"employee-golang/storage"
+	"encoding/hex"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+type documentService struct {
+	repository   repositories.IDocumentRepositories
+	employees    repositories.IEmployeeRepositories
+	store        storage.Store
+	maxSize      int64
+	contentTypes []string
+}
+
+func NewDocumentService() IDocumentService {
+	return &documentService{
+		repository:   repositories.NewDocumentRepositories(),
+		employees:    repositories.NewEmployeeRepositories(),
+		store:        repositories.NewDocumentStore(),
+		maxSize:      config.GetDocumentMaxSize(),
+		contentTypes: config.GetDocumentContentTypes(),
+	}
+}
+
+type IDocumentService interface {
+	UploadDocument(ctx context.Context, employeeId, fileName string, content io.Reader) (rs *model.Document, err error)
+	GetDocuments(ctx context.Context, employeeId string) (rs []*model.Document, err error)
+	OpenDocument(ctx context.Context, employeeId string, id int64) (rs *model.Document, content io.ReadCloser, err error)
+	DeleteDocument(ctx context.Context, employeeId string, id int64) (rs string, err error)
+	MaxSize() int64
+}
+
+func (s documentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadDocument stores content for the employee after checking its size
+// and sniffing its type. The object is written before the row so a listed
+// document always has content; a failed insert removes the object again.
+func (s documentService) UploadDocument(ctx context.Context, employeeId, fileName string, content io.Reader) (rs *model.Document, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", model.ErrDocumentTooLarge, s.maxSize)
+	}
+	if len(data) == 0 {
+		return nil, model.ErrDocumentEmpty
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", model.ErrDocumentType, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, err
+	}
+	rs = &model.Document{
+		IdEmployee:  employeeId,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  "employees/" + employeeId + "/" + hex.EncodeToString(id),
+	}
+	if err = s.store.Put(ctx, rs.StorageKey, data, contentType); err != nil {
+		logrus.Errorf("Error storing document: %v", err)
+		return nil, err
+	}
+	if _, err = s.repository.InsertDocument(ctx, rs); err != nil {
+		if errDelete := s.store.Delete(ctx, rs.StorageKey); errDelete != nil {
+			logrus.Errorf("Error removing orphaned document %s: %v", rs.StorageKey, errDelete)
+		}
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s documentService) GetDocuments(ctx context.Context, employeeId string) (rs []*model.Document, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetDocuments(ctx, employeeId)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s documentService) OpenDocument(ctx context.Context, employeeId string, id int64) (rs *model.Document, content io.ReadCloser, err error) {
+	rs, err = s.repository.GetDocumentById(ctx, employeeId, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err = s.store.Get(ctx, rs.StorageKey)
+	if err != nil {
+		logrus.Errorf("Error reading document %s: %v", rs.StorageKey, err)
+		return nil, nil, err
+	}
+	return rs, content, nil
+}
+
+// DeleteDocument removes the row first; content left behind by a failed
+// storage delete is only logged, as it is no longer reachable.
+func (s documentService) DeleteDocument(ctx context.Context, employeeId string, id int64) (rs string, err error) {
+	document, err := s.repository.GetDocumentById(ctx, employeeId, id)
+	if err != nil {
+		return "", err
+	}
+	rs, err = s.repository.DeleteDocument(ctx, employeeId, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	if err := s.store.Delete(ctx, document.StorageKey); err != nil {
+		logrus.Errorf("Error removing document %s: %v", document.StorageKey, err)
+	}
+	return rs, nil
+}
+
+func (s documentService) allowed(contentType string) bool {
+	for _, t := range s.contentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanFileName keeps the base name of an uploaded file, as browsers may
+// send a full client path.
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return name
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"employee-golang/storage"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+)
+
+type fakeDocumentRepositories struct {
+	repositories.IDocumentRepositories
+	inserted  []*model.Document
+	insertErr error
+}
+
+func (r *fakeDocumentRepositories) InsertDocument(_ context.Context, document *model.Document) (string, error) {
+	if r.insertErr != nil {
+		return "", r.insertErr
+	}
+	r.inserted = append(r.inserted, document)
+	return "Document was uploaded", nil
+}
+
+func (r *fakeDocumentRepositories) GetDocumentById(_ context.Context, _ string, _ int64) (*model.Document, error) {
+	return r.inserted[0], nil
+}
+
+func Test_documentService_UploadDocument(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		wantType string
+		wantName string
+		wantErr  error
+	}{
+		{name: "pdf", fileName: `C:\scans\contract.pdf`, content: pdf, wantType: "application/pdf", wantName: "contract.pdf"},
+		{name: "declared name does not decide the type", fileName: "photo.png", content: []byte("MZ\x90\x00executable"), wantErr: model.ErrDocumentType},
+		{name: "too large", fileName: "big.txt", content: bytes.Repeat([]byte("a"), 65), wantErr: model.ErrDocumentTooLarge},
+		{name: "empty", fileName: "empty.txt", wantErr: model.ErrDocumentEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeDocumentRepositories{}
+			store := storage.NewFileStore(t.TempDir())
+			s := documentService{
+				repository:   repository,
+				employees:    &leaveEmployeeRepositories{employee: &model.Employee{IdEmployee: "1"}},
+				store:        store,
+				maxSize:      64,
+				contentTypes: []string{"application/pdf", "text/plain"},
+			}
+
+			rs, err := s.UploadDocument(context.Background(), "1", tt.fileName, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UploadDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			sum := sha256.Sum256(tt.content)
+			if rs.ContentType != tt.wantType || rs.FileName != tt.wantName || rs.Size != int64(len(tt.content)) ||
+				rs.Checksum != hex.EncodeToString(sum[:]) {
+				t.Errorf("UploadDocument() = %+v", rs)
+			}
+
+			_, content, err := s.OpenDocument(context.Background(), "1", rs.IdDocument)
+			if err != nil {
+				t.Fatalf("OpenDocument() error = %v", err)
+			}
+			defer content.Close()
+			if b, _ := io.ReadAll(content); !bytes.Equal(b, tt.content) {
+				t.Errorf("OpenDocument() content = %q", b)
+			}
+		})
+	}
+}
+
+func Test_documentService_UploadDocument_removesOrphan(t *testing.T) {
+	repository := &fakeDocumentRepositories{insertErr: errors.New("database down")}
+	store := storage.NewFileStore(t.TempDir())
+	s := documentService{
+		repository:   repository,
+		employees:    &leaveEmployeeRepositories{employee: &model.Employee{IdEmployee: "1"}},
+		store:        store,
+		maxSize:      64,
+		contentTypes: []string{"text/plain"},
+	}
+
+	var stored string
+	s.store = recordingStore{Store: store, keys: &stored}
+	if _, err := s.UploadDocument(context.Background(), "1", "note.txt", bytes.NewReader([]byte("hello"))); err == nil {
+		t.Fatalf("UploadDocument() should fail when the row cannot be written")
+	}
+	if _, err := store.Get(context.Background(), stored); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("stored object %q was left behind, error = %v", stored, err)
+	}
+}
+
+type recordingStore struct {
+	storage.Store
+	keys *string
+}
+
+func (s recordingStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	*s.keys = key
+	return s.Store.Put(ctx, key, data, contentType)
+}
@@ -1,49 +1,118 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"employee-golang/config"
 	"employee-golang/model"
+	"employee-golang/money"
 	"employee-golang/repositories"
+	"employee-golang/storage"
+	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
 )
 
+const dateLayout = "2006-01-02"
+
 type service struct {
-	repository repositories.IEmployeeRepositories
+	repository     repositories.IEmployeeRepositories
+	positions      repositories.IPositionRepositories
+	store          storage.Store
+	thumbnailSizes []int
+	now            func() time.Time
 }
 
 func NewEmployeeService() IEmployeeService {
 	return &service{
-		repository: repositories.NewEmployeeRepositories(),
+		repository:     newEmployeeRepositories(),
+		positions:      repositories.NewPositionRepositories(),
+		store:          repositories.NewDocumentStore(),
+		thumbnailSizes: config.GetPhotoThumbnailSizes(),
+		now:            time.Now,
+	}
+}
+
+// newEmployeeRepositories is the employee repository for services
+// that write employees, so their writes invalidate cached reads and update
+// the search index.
+func newEmployeeRepositories() repositories.IEmployeeRepositories {
+	repository := repositories.NewEmployeeRepositories()
+	if config.IsEmployeeCacheEnabled() {
+		repository = repositories.NewCachedEmployeeRepositories(repository, repositories.SharedEmployeeCache(), config.GetCacheTTL())
 	}
+	return repositories.NewIndexedEmployeeRepositories(repository, repositories.SharedEmployeeIndex())
 }
 
 type IEmployeeService interface {
-	GetEmployees() (rs []*model.Employee, err error)
-	GetEmployeeById(id string) (rs *model.Employee, err error)
-	InsertEmployee(employee *model.Employee) (rs string, err error)
-	UpdateEmployee(employee *model.Employee) (rs string, err error)
-	DeleteEmployee(id string) (rs string, err error)
+	GetEmployees(ctx context.Context) (rs []*model.Employee, err error)
+	GetEmployeeById(ctx context.Context, id string) (rs *model.Employee, err error)
+	InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error)
+	UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error)
+	DeleteEmployee(ctx context.Context, id string) (rs string, err error)
+	GetDirectReports(ctx context.Context, id string) (rs []*model.Employee, err error)
+	GetReportingChain(ctx context.Context, id string) (rs []*model.Employee, err error)
+	GetOrgChart(ctx context.Context, id string) (rs *model.OrgChartNode, err error)
+	GetSalaryOverrides(ctx context.Context, id string) (rs []*model.SalaryOverride, err error)
+	GetEmployeesByStatus(ctx context.Context, filter string) (rs []*model.Employee, err error)
+	GetEmployeesByIds(ctx context.Context, ids []string) (rs []*model.Employee, err error)
+	GetEmployeesByManagers(ctx context.Context, managerIds []string) (rs []*model.Employee, err error)
+	ChangeStatus(ctx context.Context, id string, change *model.StatusChange) (rs string, err error)
+	Rehire(ctx context.Context, id string, rehire *model.Rehire) (rs string, err error)
+	GetStatusHistory(ctx context.Context, id string) (rs []*model.StatusChange, err error)
 }
 
-func (s service) InsertEmployee(employee *model.Employee) (rs string, err error) {
-	rs, err = s.repository.InsertEmployee(employee)
+func (s service) InsertEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	normaliseSalary(employee)
+	if employee.Status, err = s.initialStatus(employee); err != nil {
+		return "", err
+	}
+	employee.TerminationDate, employee.TerminationReason = "", ""
+	if employee.SalaryOverride, err = checkSalaryBand(ctx, s.positions, employee); err != nil {
+		return "", err
+	}
+	effectiveDate := employee.HireDate
+	if _, errDate := time.Parse(dateLayout, effectiveDate); errDate != nil {
+		effectiveDate = s.now().Format(dateLayout)
+	}
+	employee.Compensation = compensationOf(employee, effectiveDate, "initial salary")
+	rs, err = s.repository.InsertEmployee(ctx, employee)
 	if err != nil {
 		logrus.Error("Error is been occurred")
 		return "", err
 	}
+	logSalaryOverride(employee.SalaryOverride)
+	s.recordInitialStatus(ctx, employee, effectiveDate)
 	return rs, nil
 }
 
-func (s service) UpdateEmployee(employee *model.Employee) (rs string, err error) {
-	rs, err = s.repository.UpdateEmployee(employee)
+func (s service) UpdateEmployee(ctx context.Context, employee *model.Employee) (rs string, err error) {
+	normaliseSalary(employee)
+	if employee.SalaryOverride, err = checkSalaryBand(ctx, s.positions, employee); err != nil {
+		return "", err
+	}
+	current, err := s.repository.GetEmployeeById(ctx, employee.IdEmployee)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if current != nil && employee.Status != "" && employee.Status != current.Status {
+		return "", model.ErrStatusNotEditable
+	}
+	employee.Compensation = compensationOf(employee, s.now().Format(dateLayout), "salary updated")
+	rs, err = s.repository.UpdateEmployee(ctx, employee)
 	if err != nil {
 		logrus.Error("Error is been occurred")
 		return "", err
 	}
+	logSalaryOverride(employee.SalaryOverride)
 	return rs, nil
 }
 
-func (s service) GetEmployees() (rs []*model.Employee, err error) {
-	rs, err = s.repository.GetEmployee()
+func (s service) GetEmployees(ctx context.Context) (rs []*model.Employee, err error) {
+	rs, err = s.repository.GetEmployee(ctx)
 	if err != nil {
 		logrus.Error("Error is been occurred")
 		return nil, err
@@ -51,8 +120,8 @@ func (s service) GetEmployees() (rs []*model.Employee, err error) {
 	return rs, nil
 }
 
-func (s service) GetEmployeeById(id string) (rs *model.Employee, err error) {
-	rs, err = s.repository.GetEmployeeById(id)
+func (s service) GetEmployeeById(ctx context.Context, id string) (rs *model.Employee, err error) {
+	rs, err = s.repository.GetEmployeeById(ctx, id)
 	if err != nil {
 		logrus.Error("Error is been occurred")
 		return nil, err
@@ -60,11 +129,190 @@ func (s service) GetEmployeeById(id string) (rs *model.Employee, err error) {
 	return rs, nil
 }
 
-func (s service) DeleteEmployee(id string) (rs string, err error) {
-	rs, err = s.repository.DeleteEmployee(id)
+// DeleteEmployee deletes the employee and then the stored objects of the
+// documents and photo deleted with them. Failing to remove an object is
+// only logged, as nothing references it any more.
+func (s service) DeleteEmployee(ctx context.Context, id string) (rs string, err error) {
+	rs, err = s.repository.DeleteEmployee(ctx, id, func(documents []*model.Document, photo *model.Photo) {
+		for _, document := range documents {
+			if err := s.store.Delete(ctx, document.StorageKey); err != nil {
+				logrus.Errorf("Error removing document %s: %v", document.StorageKey, err)
+			}
+		}
+		if photo != nil {
+			removePhotoObjects(ctx, s.store, photo, s.thumbnailSizes)
+		}
+	})
 	if err != nil {
 		logrus.Error("Error is been occurred")
 		return "", err
 	}
 	return rs, nil
 }
+
+func (s service) GetDirectReports(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	if _, err = s.repository.GetEmployeeById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetDirectReports(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s service) GetReportingChain(ctx context.Context, id string) (rs []*model.Employee, err error) {
+	if _, err = s.repository.GetEmployeeById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetReportingChain(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetOrgChart nests every employee below id under their manager.
+func (s service) GetOrgChart(ctx context.Context, id string) (rs *model.OrgChartNode, err error) {
+	root, err := s.repository.GetEmployeeById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subordinates, err := s.repository.GetSubordinates(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+
+	rs = &model.OrgChartNode{Employee: root, Reports: []*model.OrgChartNode{}}
+	nodes := map[string]*model.OrgChartNode{root.IdEmployee: rs}
+	for _, employee := range subordinates {
+		nodes[employee.IdEmployee] = &model.OrgChartNode{Employee: employee, Reports: []*model.OrgChartNode{}}
+	}
+	// subordinates are ordered by depth, so a manager is always placed before
+	// their reports and the tree keeps that order
+	for _, employee := range subordinates {
+		if manager, ok := nodes[employee.ManagerId]; ok {
+			manager.Reports = append(manager.Reports, nodes[employee.IdEmployee])
+		}
+	}
+	return rs, nil
+}
+
+func (s service) GetSalaryOverrides(ctx context.Context, id string) (rs []*model.SalaryOverride, err error) {
+	if _, err = s.repository.GetEmployeeById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.positions.GetSalaryOverrides(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// checkSalaryBand rejects a salary outside the band of the employee's
+// position unless an override reason is given, in which case it returns the
+// override to store with the employee.
+func checkSalaryBand(ctx context.Context, positions repositories.IPositionRepositories, employee *model.Employee) (*model.SalaryOverride, error) {
+	if employee.PositionId == "" {
+		return nil, nil
+	}
+	position, err := positions.GetPositionById(ctx, employee.PositionId)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, model.ErrPositionNotFound
+	case err != nil:
+		return nil, err
+	}
+	// the band is kept in the position's currency, so compare the salary
+	// converted into it
+	salary, err := config.GetExchangeRates().Convert(employee.Salary.Decimal, employee.Currency, position.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if salary.GreaterThanOrEqual(position.MinSalary.Decimal) && salary.LessThanOrEqual(position.MaxSalary.Decimal) {
+		return nil, nil
+	}
+	reason := strings.TrimSpace(employee.SalaryOverrideReason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: %s %s is not within %s - %s %s for position %s, supply salaryOverrideReason to override",
+			model.ErrSalaryOutOfBand, employee.Salary, employee.Currency,
+			position.MinSalary, position.MaxSalary, position.Currency, position.IdPosition)
+	}
+	return &model.SalaryOverride{
+		IdEmployee:   employee.IdEmployee,
+		IdPosition:   position.IdPosition,
+		Salary:       employee.Salary,
+		Currency:     employee.Currency,
+		MinSalary:    position.MinSalary,
+		MaxSalary:    position.MaxSalary,
+		BandCurrency: position.Currency,
+		Reason:       reason,
+	}, nil
+}
+
+// compensationOf is the history entry for the employee's salary, which the
+// repository stores with the employee so reads of it see the new salary.
+func compensationOf(employee *model.Employee, effectiveDate, reason string) *model.Compensation {
+	return &model.Compensation{
+		IdEmployee:    employee.IdEmployee,
+		Salary:        employee.Salary,
+		Currency:      employee.Currency,
+		EffectiveDate: effectiveDate,
+		Reason:        reason,
+	}
+}
+
+func logSalaryOverride(override *model.SalaryOverride) {
+	if override == nil {
+		return
+	}
+	logrus.Warnf("salary %s %s of employee %s overrides band of position %s: %s",
+		override.Salary, override.Currency, override.IdEmployee, override.IdPosition, override.Reason)
+}
+
+// normaliseSalary upper-cases the currency, defaulting it when missing, and
+// rounds the salary to that currency's minor unit.
+func normaliseSalary(employee *model.Employee) {
+	employee.Currency = strings.ToUpper(strings.TrimSpace(employee.Currency))
+	if employee.Currency == "" {
+		employee.Currency = config.GetDefaultCurrency()
+	}
+	employee.Salary = model.NewMoney(money.Round(employee.Salary.Decimal, employee.Currency))
+}
+
+// GetEmployeesByIds looks up a batch of employees, leaving out unknown
+// ids, for callers that resolve many references at once.
+func (s service) GetEmployeesByIds(ctx context.Context, ids []string) (rs []*model.Employee, err error) {
+	rs, err = s.repository.GetEmployeesByIds(ctx, ids)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetEmployeesByManagers returns the direct reports of a batch of managers.
+func (s service) GetEmployeesByManagers(ctx context.Context, managerIds []string) (rs []*model.Employee, err error) {
+	rs, err = s.repository.GetEmployeesByManagers(ctx, managerIds)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// PageEmployees sorts employees by id in place and returns up to limit of
+// those following the id after, and whether more follow them.
+func PageEmployees(employees []*model.Employee, after string, limit int) (page []*model.Employee, more bool) {
+	sort.Slice(employees, func(i, j int) bool { return employees[i].IdEmployee < employees[j].IdEmployee })
+	start := sort.Search(len(employees), func(i int) bool { return employees[i].IdEmployee > after })
+	page = employees[start:]
+	if len(page) > limit {
+		return page[:limit], true
+	}
+	return page, false
+}
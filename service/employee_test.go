@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+type fakeEmployeeRepositories struct {
+	repositories.IEmployeeRepositories
+	inserted []*model.Employee
+	statuses []*model.StatusChange
+}
+
+func (r *fakeEmployeeRepositories) InsertEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	r.inserted = append(r.inserted, employee)
+	return "Successfully inserted a new employee", nil
+}
+
+func (r *fakeEmployeeRepositories) InsertStatusChange(_ context.Context, change *model.StatusChange) error {
+	r.statuses = append(r.statuses, change)
+	return nil
+}
+
+type fakePositionRepositories struct {
+	repositories.IPositionRepositories
+}
+
+func (r *fakePositionRepositories) GetPositionById(_ context.Context, id string) (*model.Position, error) {
+	return &model.Position{IdPosition: id, Title: "Engineer", Grade: "G5", MinSalary: model.NewMoney(decimal.NewFromInt(50000)), MaxSalary: model.NewMoney(decimal.NewFromInt(80000)), Currency: "USD"}, nil
+}
+
+type fakeCompensationRepositories struct {
+	repositories.ICompensationRepositories
+}
+
+func Test_service_InsertEmployee_salaryBand(t *testing.T) {
+	tests := []struct {
+		name          string
+		employee      *model.Employee
+		wantErr       error
+		wantOverrides int
+	}{
+		{
+			name:     "salary within band",
+			employee: &model.Employee{IdEmployee: "1", PositionId: "P1", Salary: model.NewMoney(decimal.NewFromInt(60000))},
+		},
+		{
+			name:     "salary above band",
+			employee: &model.Employee{IdEmployee: "2", PositionId: "P1", Salary: model.NewMoney(decimal.NewFromInt(90000))},
+			wantErr:  model.ErrSalaryOutOfBand,
+		},
+		{
+			name:          "salary above band with override reason",
+			employee:      &model.Employee{IdEmployee: "3", PositionId: "P1", Salary: model.NewMoney(decimal.NewFromInt(90000)), SalaryOverrideReason: "retention offer"},
+			wantOverrides: 1,
+		},
+		{
+			name:     "no position",
+			employee: &model.Employee{IdEmployee: "4", Salary: model.NewMoney(decimal.NewFromInt(1))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeEmployeeRepositories{}
+			s := service{
+				repository: repository,
+				positions:  &fakePositionRepositories{},
+				now:        time.Now,
+			}
+			_, err := s.InsertEmployee(context.Background(), tt.employee)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("InsertEmployee() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			overrides := 0
+			for _, employee := range repository.inserted {
+				if employee.SalaryOverride != nil {
+					overrides++
+				}
+			}
+			if overrides != tt.wantOverrides {
+				t.Errorf("InsertEmployee() recorded %d overrides, want %d", overrides, tt.wantOverrides)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type leaveService struct {
+	repository repositories.ILeaveRepositories
+	employees  repositories.IEmployeeRepositories
+	now        func() time.Time
+}
+
+func NewLeaveService() ILeaveService {
+	return &leaveService{
+		repository: repositories.NewLeaveRepositories(),
+		employees:  repositories.NewEmployeeRepositories(),
+		now:        time.Now,
+	}
+}
+
+type ILeaveService interface {
+	GetLeaveTypes(ctx context.Context) (rs []*model.LeaveType, err error)
+	GetLeaveTypeById(ctx context.Context, id string) (rs *model.LeaveType, err error)
+	InsertLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error)
+	UpdateLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error)
+	DeleteLeaveType(ctx context.Context, id string) (rs string, err error)
+	RequestLeave(ctx context.Context, request *model.LeaveRequest) (rs []*model.LeaveRequest, err error)
+	GetLeaveRequests(ctx context.Context, employeeId string) (rs []*model.LeaveRequest, err error)
+	GetLeaveBalances(ctx context.Context, employeeId string, year int) (rs []*model.LeaveBalance, err error)
+	DecideLeave(ctx context.Context, id int64, decision *model.LeaveDecision, status string) (rs string, err error)
+	CancelLeave(ctx context.Context, id int64) (rs string, err error)
+}
+
+func (s leaveService) GetLeaveTypes(ctx context.Context) (rs []*model.LeaveType, err error) {
+	rs, err = s.repository.GetLeaveTypes(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s leaveService) GetLeaveTypeById(ctx context.Context, id string) (rs *model.LeaveType, err error) {
+	rs, err = s.repository.GetLeaveTypeById(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s leaveService) InsertLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error) {
+	rs, err = s.repository.InsertLeaveType(ctx, leaveType)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s leaveService) UpdateLeaveType(ctx context.Context, leaveType *model.LeaveType) (rs string, err error) {
+	rs, err = s.repository.UpdateLeaveType(ctx, leaveType)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s leaveService) DeleteLeaveType(ctx context.Context, id string) (rs string, err error) {
+	rs, err = s.repository.DeleteLeaveType(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+// RequestLeave submits a pending request after checking its dates, that it
+// does not overlap the employee's other pending or approved leave and that
+// the balance for its year covers it. Leave running into the next year is
+// split into one request per year, each taken from that year's balance.
+func (s leaveService) RequestLeave(ctx context.Context, request *model.LeaveRequest) (rs []*model.LeaveRequest, err error) {
+	leaveType, err := s.repository.GetLeaveTypeById(ctx, request.IdLeaveType)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, model.ErrLeaveTypeNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	start, errStart := time.Parse(dateLayout, request.StartDate)
+	end, errEnd := time.Parse(dateLayout, request.EndDate)
+	if errStart != nil || errEnd != nil || end.Before(start) || !end.Before(start.AddDate(1, 0, 0)) {
+		return nil, model.ErrLeaveDates
+	}
+	request.Status = model.LeavePending
+	request.Reason = strings.TrimSpace(request.Reason)
+	rs = splitLeaveByYear(request, start, end)
+	if len(rs) == 0 {
+		return nil, model.ErrLeaveDates
+	}
+
+	_, err = s.repository.InsertLeaveRequests(ctx, rs, func(employee *model.Employee, overlapping int, usage []*model.LeaveUsage) error {
+		if employee.Status == model.StatusTerminated {
+			return model.ErrLeaveInactive
+		}
+		if overlapping > 0 {
+			return model.ErrLeaveOverlap
+		}
+		// count the new days as pending, so a later year's carry over
+		// already reflects the part taken from the year before it
+		for _, part := range rs {
+			usage = append(usage, &model.LeaveUsage{Year: leaveYear(part), Status: model.LeavePending, Days: part.Days.Decimal})
+		}
+		for _, part := range rs {
+			balance := s.balanceFrom(usage, employee, leaveType, leaveYear(part))
+			if balance.Available.IsNegative() {
+				return fmt.Errorf("%w: %s days requested in %d, %s available", model.ErrLeaveBalance,
+					part.Days, balance.Year, balance.Available.Add(part.Days.Decimal))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// splitLeaveByYear cuts request into one request per calendar year from
+// start to end, leaving out parts without a working day.
+func splitLeaveByYear(request *model.LeaveRequest, start, end time.Time) []*model.LeaveRequest {
+	parts := make([]*model.LeaveRequest, 0, 2)
+	for from := start; !from.After(end); {
+		to := time.Date(from.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+		if to.After(end) {
+			to = end
+		}
+		part := *request
+		part.StartDate, part.EndDate = from.Format(dateLayout), to.Format(dateLayout)
+		part.Days = model.NewQuantity(workingDays(from, to))
+		if !part.Days.IsZero() {
+			parts = append(parts, &part)
+		}
+		from = to.AddDate(0, 0, 1)
+	}
+	return parts
+}
+
+// leaveYear is the year a request's days are taken from.
+func leaveYear(request *model.LeaveRequest) int {
+	start, _ := time.Parse(dateLayout, request.StartDate)
+	return start.Year()
+}
+
+func (s leaveService) GetLeaveRequests(ctx context.Context, employeeId string) (rs []*model.LeaveRequest, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetLeaveRequests(ctx, employeeId)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// GetLeaveBalances returns the employee's balance for every leave type in
+// year, the current year when zero.
+func (s leaveService) GetLeaveBalances(ctx context.Context, employeeId string, year int) (rs []*model.LeaveBalance, err error) {
+	employee, err := s.employees.GetEmployeeById(ctx, employeeId)
+	if err != nil {
+		return nil, err
+	}
+	if year == 0 {
+		year = s.now().Year()
+	}
+	leaveTypes, err := s.repository.GetLeaveTypes(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	rs = make([]*model.LeaveBalance, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		balance, err := s.balance(ctx, employee, leaveType, year)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, balance)
+	}
+	return rs, nil
+}
+
+// DecideLeave approves or rejects a pending request. The approver must sit
+// above the employee in the reporting line.
+func (s leaveService) DecideLeave(ctx context.Context, id int64, decision *model.LeaveDecision, status string) (rs string, err error) {
+	request, err := s.repository.GetLeaveRequestById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if request.Status != model.LeavePending {
+		return "", model.ErrLeaveNotPending
+	}
+	chain, err := s.employees.GetReportingChain(ctx, request.IdEmployee)
+	if err != nil {
+		return "", err
+	}
+	approver := false
+	for _, manager := range chain {
+		if manager.IdEmployee == decision.ApproverId {
+			approver = true
+			break
+		}
+	}
+	if !approver {
+		return "", model.ErrLeaveNotApprover
+	}
+
+	request.Status = status
+	request.ApproverId = decision.ApproverId
+	request.DecisionNote = strings.TrimSpace(decision.Note)
+	rs, err = s.repository.UpdateLeaveStatus(ctx, request, model.LeavePending)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+// CancelLeave withdraws a request that is pending or has not started yet.
+func (s leaveService) CancelLeave(ctx context.Context, id int64) (rs string, err error) {
+	request, err := s.repository.GetLeaveRequestById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	from := request.Status
+	started := request.StartDate <= s.now().Format(dateLayout)
+	if from != model.LeavePending && (from != model.LeaveApproved || started) {
+		return "", model.ErrLeaveNotPending
+	}
+	request.Status = model.LeaveCancelled
+	rs, err = s.repository.UpdateLeaveStatus(ctx, request, from)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+// balance works out the employee's balance for leaveType in year, carrying
+// unused days forward year by year from the hire year.
+func (s leaveService) balance(ctx context.Context, employee *model.Employee, leaveType *model.LeaveType, year int) (*model.LeaveBalance, error) {
+	usage, err := s.repository.GetLeaveUsage(ctx, employee.IdEmployee, leaveType.IdLeaveType)
+	if err != nil {
+		return nil, err
+	}
+	return s.balanceFrom(usage, employee, leaveType, year), nil
+}
+
+// balanceFrom is balance given the employee's usage of leaveType.
+func (s leaveService) balanceFrom(usage []*model.LeaveUsage, employee *model.Employee, leaveType *model.LeaveType, year int) *model.LeaveBalance {
+	used := map[int]decimal.Decimal{}
+	pending := map[int]decimal.Decimal{}
+	for _, u := range usage {
+		if u.Status == model.LeaveApproved {
+			used[u.Year] = used[u.Year].Add(u.Days)
+		} else {
+			pending[u.Year] = pending[u.Year].Add(u.Days)
+		}
+	}
+
+	hired, errHire := time.Parse(dateLayout, employee.HireDate)
+	first := year
+	if errHire == nil && hired.Year() < year {
+		first = hired.Year()
+		if first < year-maxLeaveCarryYears {
+			first = year - maxLeaveCarryYears
+		}
+	}
+	carried := decimal.Zero
+	var balance *model.LeaveBalance
+	for y := first; y <= year; y++ {
+		entitled := s.entitlement(leaveType, hired, errHire == nil, y)
+		balance = &model.LeaveBalance{
+			IdEmployee:  employee.IdEmployee,
+			IdLeaveType: leaveType.IdLeaveType,
+			Year:        y,
+			Entitled:    model.NewQuantity(entitled),
+			CarriedOver: model.NewQuantity(carried),
+			Used:        model.NewQuantity(used[y]),
+			Pending:     model.NewQuantity(pending[y]),
+			Available:   model.NewQuantity(entitled.Add(carried).Sub(used[y]).Sub(pending[y])),
+		}
+		carried = decimal.Max(decimal.Zero, decimal.Min(balance.Available.Decimal, leaveType.CarryOverDays.Decimal))
+	}
+	return balance
+}
+
+// maxLeaveCarryYears bounds how many past years the carry-over walk visits.
+const maxLeaveCarryYears = 50
+
+// entitlement is the allowance of leaveType earned in year: pro-rated by the
+// months employed in the hire year and, for monthly accrual, only the months
+// started so far in the current year. It is rounded to half days.
+func (s leaveService) entitlement(leaveType *model.LeaveType, hired time.Time, hasHireDate bool, year int) decimal.Decimal {
+	firstMonth, lastMonth := 1, 12
+	if hasHireDate {
+		if hired.Year() > year {
+			return decimal.Zero
+		}
+		if hired.Year() == year {
+			firstMonth = int(hired.Month())
+		}
+	}
+	if leaveType.Accrual == model.LeaveAccrualMonthly {
+		now := s.now()
+		if year > now.Year() {
+			return decimal.Zero
+		}
+		if year == now.Year() {
+			lastMonth = int(now.Month())
+		}
+	}
+	months := lastMonth - firstMonth + 1
+	if months <= 0 {
+		return decimal.Zero
+	}
+	two := decimal.NewFromInt(2)
+	days := leaveType.AnnualDays.Mul(decimal.NewFromInt(int64(months))).Div(decimal.NewFromInt(12))
+	return days.Mul(two).Round(0).Div(two)
+}
+
+// workingDays counts the weekdays from start to end inclusive.
+func workingDays(start, end time.Time) decimal.Decimal {
+	days := int64(0)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return decimal.NewFromInt(days)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+type fakeLeaveRepositories struct {
+	repositories.ILeaveRepositories
+	employee    *model.Employee
+	leaveType   *model.LeaveType
+	usage       []*model.LeaveUsage
+	overlapping int
+	request     *model.LeaveRequest
+	inserted    []*model.LeaveRequest
+}
+
+func (r *fakeLeaveRepositories) GetLeaveTypeById(_ context.Context, _ string) (*model.LeaveType, error) {
+	return r.leaveType, nil
+}
+
+func (r *fakeLeaveRepositories) GetLeaveUsage(_ context.Context, _, _ string) ([]*model.LeaveUsage, error) {
+	return r.usage, nil
+}
+
+func (r *fakeLeaveRepositories) InsertLeaveRequests(_ context.Context, requests []*model.LeaveRequest, check func(*model.Employee, int, []*model.LeaveUsage) error) (string, error) {
+	if err := check(r.employee, r.overlapping, r.usage); err != nil {
+		return "", err
+	}
+	r.inserted = append(r.inserted, requests...)
+	return "Leave request was submitted", nil
+}
+
+func (r *fakeLeaveRepositories) GetLeaveRequestById(_ context.Context, _ int64) (*model.LeaveRequest, error) {
+	request := *r.request
+	return &request, nil
+}
+
+func (r *fakeLeaveRepositories) UpdateLeaveStatus(_ context.Context, request *model.LeaveRequest, _ string) (string, error) {
+	r.request = request
+	return "Leave request was " + request.Status, nil
+}
+
+type leaveEmployeeRepositories struct {
+	fakeEmployeeRepositories
+	employee *model.Employee
+}
+
+func (r *leaveEmployeeRepositories) GetEmployeeById(_ context.Context, _ string) (*model.Employee, error) {
+	return r.employee, nil
+}
+
+func (r *leaveEmployeeRepositories) GetReportingChain(_ context.Context, _ string) ([]*model.Employee, error) {
+	return []*model.Employee{{IdEmployee: "M1"}, {IdEmployee: "M2"}}, nil
+}
+
+func annualLeave() *model.LeaveType {
+	return &model.LeaveType{
+		IdLeaveType:   "AL",
+		AnnualDays:    model.NewQuantity(decimal.NewFromInt(24)),
+		Accrual:       model.LeaveAccrualAnnual,
+		CarryOverDays: model.NewQuantity(decimal.NewFromInt(5)),
+	}
+}
+
+func Test_leaveService_balance(t *testing.T) {
+	now := func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name          string
+		hireDate      string
+		accrual       string
+		usage         []*model.LeaveUsage
+		wantEntitled  string
+		wantCarried   string
+		wantAvailable string
+	}{
+		{
+			name:          "hired this year is pro-rated",
+			hireDate:      "2024-07-01",
+			accrual:       model.LeaveAccrualAnnual,
+			wantEntitled:  "12",
+			wantCarried:   "0",
+			wantAvailable: "12",
+		},
+		{
+			name:          "monthly accrual counts months started",
+			hireDate:      "2020-01-06",
+			accrual:       model.LeaveAccrualMonthly,
+			usage:         []*model.LeaveUsage{{Year: 2024, Status: model.LeavePending, Days: decimal.NewFromInt(2)}},
+			wantEntitled:  "6",
+			wantCarried:   "5",
+			wantAvailable: "9",
+		},
+		{
+			name:     "carry over is capped",
+			hireDate: "2023-01-02",
+			accrual:  model.LeaveAccrualAnnual,
+			usage: []*model.LeaveUsage{
+				{Year: 2023, Status: model.LeaveApproved, Days: decimal.NewFromInt(21)},
+				{Year: 2024, Status: model.LeaveApproved, Days: decimal.NewFromInt(4)},
+			},
+			wantEntitled:  "24",
+			wantCarried:   "3",
+			wantAvailable: "23",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaveType := annualLeave()
+			leaveType.Accrual = tt.accrual
+			s := leaveService{repository: &fakeLeaveRepositories{usage: tt.usage}, now: now}
+
+			balance, err := s.balance(context.Background(), &model.Employee{IdEmployee: "1", HireDate: tt.hireDate}, leaveType, 2024)
+			if err != nil {
+				t.Fatalf("balance() error = %v", err)
+			}
+			if !balance.Entitled.Equal(decimal.RequireFromString(tt.wantEntitled)) ||
+				!balance.CarriedOver.Equal(decimal.RequireFromString(tt.wantCarried)) ||
+				!balance.Available.Equal(decimal.RequireFromString(tt.wantAvailable)) {
+				t.Errorf("balance() = %+v, want entitled %s carried %s available %s",
+					balance, tt.wantEntitled, tt.wantCarried, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func Test_leaveService_RequestLeave(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, end  string
+		overlapping int
+		usage       []*model.LeaveUsage
+		status      string
+		wantDays    []string
+		wantErr     error
+	}{
+		{name: "one working week", start: "2024-05-06", end: "2024-05-12", wantDays: []string{"5"}},
+		{name: "weekend only", start: "2024-05-11", end: "2024-05-12", wantErr: model.ErrLeaveDates},
+		{name: "ends before it starts", start: "2024-05-10", end: "2024-05-06", wantErr: model.ErrLeaveDates},
+		{name: "a year or longer", start: "2024-05-06", end: "2025-05-06", wantErr: model.ErrLeaveDates},
+		{name: "spans two years", start: "2024-12-30", end: "2025-01-02", wantDays: []string{"2", "2"}},
+		{name: "spans into a weekend of the next year", start: "2027-12-30", end: "2028-01-02", wantDays: []string{"2"}},
+		{
+			name:  "next year's part exceeds its balance",
+			start: "2024-12-30", end: "2025-01-02",
+			usage:   []*model.LeaveUsage{{Year: 2024, Status: model.LeaveApproved, Days: decimal.NewFromInt(22)}, {Year: 2025, Status: model.LeavePending, Days: decimal.NewFromInt(28)}},
+			wantErr: model.ErrLeaveBalance,
+		},
+		{name: "overlaps another request", start: "2024-05-06", end: "2024-05-07", overlapping: 1, wantErr: model.ErrLeaveOverlap},
+		{name: "more than the balance", start: "2024-05-01", end: "2024-06-30", wantErr: model.ErrLeaveBalance},
+		{name: "terminated employee", start: "2024-05-06", end: "2024-05-07", status: model.StatusTerminated, wantErr: model.ErrLeaveInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeLeaveRepositories{
+				employee:    &model.Employee{IdEmployee: "1", HireDate: "2020-01-06", Status: tt.status},
+				leaveType:   annualLeave(),
+				usage:       tt.usage,
+				overlapping: tt.overlapping,
+			}
+			s := leaveService{
+				repository: repository,
+				now:        func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) },
+			}
+
+			rs, err := s.RequestLeave(context.Background(), &model.LeaveRequest{IdEmployee: "1", IdLeaveType: "AL", StartDate: tt.start, EndDate: tt.end})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestLeave() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(rs) != len(tt.wantDays) || len(repository.inserted) != len(tt.wantDays) {
+				t.Fatalf("RequestLeave() = %d requests, %d inserted, want %d", len(rs), len(repository.inserted), len(tt.wantDays))
+			}
+			for i, request := range rs {
+				if !request.Days.Equal(decimal.RequireFromString(tt.wantDays[i])) || request.Status != model.LeavePending {
+					t.Errorf("RequestLeave()[%d] = %+v", i, request)
+				}
+			}
+		})
+	}
+}
+
+func Test_leaveService_DecideLeave(t *testing.T) {
+	tests := []struct {
+		name       string
+		approverId string
+		status     string
+		wantErr    error
+	}{
+		{name: "direct manager", approverId: "M1", status: model.LeavePending},
+		{name: "skip-level manager", approverId: "M2", status: model.LeavePending},
+		{name: "outside the reporting line", approverId: "X9", status: model.LeavePending, wantErr: model.ErrLeaveNotApprover},
+		{name: "already decided", approverId: "M1", status: model.LeaveRejected, wantErr: model.ErrLeaveNotPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeLeaveRepositories{request: &model.LeaveRequest{IdLeaveRequest: 7, IdEmployee: "1", Status: tt.status}}
+			s := leaveService{repository: repository, employees: &leaveEmployeeRepositories{}}
+
+			_, err := s.DecideLeave(context.Background(), 7, &model.LeaveDecision{ApproverId: tt.approverId}, model.LeaveApproved)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecideLeave() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (repository.request.Status != model.LeaveApproved || repository.request.ApproverId != tt.approverId) {
+				t.Errorf("DecideLeave() stored %+v", repository.request)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// statusTransitions lists the statuses each status may move to. Leaving
+// terminated is a rehire, which goes through Rehire instead.
+var statusTransitions = map[string][]string{
+	model.StatusOnboarding: {model.StatusActive, model.StatusTerminated},
+	model.StatusActive:     {model.StatusOnLeave, model.StatusTerminated},
+	model.StatusOnLeave:    {model.StatusActive, model.StatusTerminated},
+	model.StatusTerminated: {},
+}
+
+func canTransition(from, to string) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatuses splits a comma-separated status filter, rejecting unknown
+// statuses so a typo does not silently match nothing.
+func parseStatuses(filter string) ([]string, error) {
+	var statuses []string
+	for _, status := range strings.Split(filter, ",") {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if status == "" {
+			continue
+		}
+		if _, ok := statusTransitions[status]; !ok {
+			return nil, fmt.Errorf("%w: %s", model.ErrUnknownStatus, status)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// initialStatus is the status a new employee starts in: onboarding until
+// their hire date, active from then on, unless one of those was requested.
+func (s service) initialStatus(employee *model.Employee) (string, error) {
+	switch employee.Status {
+	case model.StatusOnboarding, model.StatusActive:
+		return employee.Status, nil
+	case "":
+		hireDate, err := time.Parse(dateLayout, employee.HireDate)
+		if err == nil && hireDate.Format(dateLayout) > s.now().Format(dateLayout) {
+			return model.StatusOnboarding, nil
+		}
+		return model.StatusActive, nil
+	default:
+		return "", fmt.Errorf("%w: new employees start as %s or %s", model.ErrStatusTransition, model.StatusOnboarding, model.StatusActive)
+	}
+}
+
+func (s service) GetEmployeesByStatus(ctx context.Context, filter string) (rs []*model.Employee, err error) {
+	statuses, err := parseStatuses(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return s.GetEmployees(ctx)
+	}
+	rs, err = s.repository.GetEmployeesByStatus(ctx, statuses)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ChangeStatus moves an employee along an allowed lifecycle transition.
+// The change applies at once, so its effective date may be today or earlier
+// but not later. Terminating requires a reason and records the effective
+// date as the termination date.
+func (s service) ChangeStatus(ctx context.Context, id string, change *model.StatusChange) (rs string, err error) {
+	employee, err := s.repository.GetEmployeeById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	change.Status = strings.ToLower(strings.TrimSpace(change.Status))
+	if !canTransition(employee.Status, change.Status) {
+		return "", fmt.Errorf("%w: %s to %s", model.ErrStatusTransition, employee.Status, change.Status)
+	}
+	today := s.now().Format(dateLayout)
+	if change.EffectiveDate == "" {
+		change.EffectiveDate = today
+	}
+	if change.EffectiveDate > today {
+		return "", model.ErrStatusFutureDate
+	}
+	change.Reason = strings.TrimSpace(change.Reason)
+	if change.Status == model.StatusTerminated {
+		if change.Reason == "" {
+			return "", model.ErrTerminationReason
+		}
+		employee.TerminationDate = change.EffectiveDate
+		employee.TerminationReason = change.Reason
+	}
+	change.IdEmployee = id
+	change.FromStatus = employee.Status
+	employee.Status = change.Status
+
+	rs, err = s.repository.ChangeEmployeeStatus(ctx, employee, change)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+// Rehire brings a terminated employee back under their original id with a
+// new hire date, clearing the termination. Compensation and status history
+// are kept.
+func (s service) Rehire(ctx context.Context, id string, rehire *model.Rehire) (rs string, err error) {
+	employee, err := s.repository.GetEmployeeById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if employee.Status != model.StatusTerminated {
+		return "", model.ErrNotTerminated
+	}
+	status := rehire.Status
+	if status == "" {
+		status = model.StatusOnboarding
+	}
+	hireDate := rehire.HireDate
+	if hireDate == "" {
+		hireDate = s.now().Format(dateLayout)
+	}
+	reason := strings.TrimSpace(rehire.Reason)
+	if reason == "" {
+		reason = "rehired"
+	}
+
+	change := &model.StatusChange{
+		IdEmployee:    id,
+		FromStatus:    employee.Status,
+		Status:        status,
+		EffectiveDate: hireDate,
+		Reason:        reason,
+	}
+	employee.Status = status
+	employee.HireDate = hireDate
+	employee.TerminationDate = ""
+	employee.TerminationReason = ""
+
+	rs, err = s.repository.ChangeEmployeeStatus(ctx, employee, change)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return "Employee was rehired", nil
+}
+
+func (s service) GetStatusHistory(ctx context.Context, id string) (rs []*model.StatusChange, err error) {
+	if _, err = s.repository.GetEmployeeById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetStatusHistory(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+// recordInitialStatus starts the status history of a new employee.
+func (s service) recordInitialStatus(ctx context.Context, employee *model.Employee, effectiveDate string) {
+	err := s.repository.InsertStatusChange(ctx, &model.StatusChange{
+		IdEmployee:    employee.IdEmployee,
+		Status:        employee.Status,
+		EffectiveDate: effectiveDate,
+		Reason:        "hired",
+	})
+	if err != nil {
+		logrus.Errorf("Error recording status history: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+type lifecycleRepositories struct {
+	fakeEmployeeRepositories
+	employee *model.Employee
+	changes  []*model.StatusChange
+}
+
+func (r *lifecycleRepositories) GetEmployeeById(_ context.Context, _ string) (*model.Employee, error) {
+	employee := *r.employee
+	return &employee, nil
+}
+
+func (r *lifecycleRepositories) ChangeEmployeeStatus(_ context.Context, employee *model.Employee, change *model.StatusChange) (string, error) {
+	r.employee = employee
+	r.changes = append(r.changes, change)
+	return "Employee status was changed", nil
+}
+
+func Test_service_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		change  model.StatusChange
+		wantErr error
+	}{
+		{name: "onboarding to active", from: model.StatusOnboarding, change: model.StatusChange{Status: model.StatusActive}},
+		{name: "active to on leave", from: model.StatusActive, change: model.StatusChange{Status: model.StatusOnLeave}},
+		{name: "back from leave", from: model.StatusOnLeave, change: model.StatusChange{Status: model.StatusActive}},
+		{name: "onboarding straight to leave", from: model.StatusOnboarding, change: model.StatusChange{Status: model.StatusOnLeave}, wantErr: model.ErrStatusTransition},
+		{name: "terminated cannot come back through status", from: model.StatusTerminated, change: model.StatusChange{Status: model.StatusActive}, wantErr: model.ErrStatusTransition},
+		{name: "termination without reason", from: model.StatusActive, change: model.StatusChange{Status: model.StatusTerminated}, wantErr: model.ErrTerminationReason},
+		{name: "termination", from: model.StatusActive, change: model.StatusChange{Status: model.StatusTerminated, EffectiveDate: "2024-03-14", Reason: "resigned"}},
+		{name: "termination in the future", from: model.StatusActive, change: model.StatusChange{Status: model.StatusTerminated, EffectiveDate: "2024-03-31", Reason: "resigned"}, wantErr: model.ErrStatusFutureDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &lifecycleRepositories{employee: &model.Employee{IdEmployee: "1", Status: tt.from}}
+			s := service{repository: repository, now: func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) }}
+
+			change := tt.change
+			_, err := s.ChangeStatus(context.Background(), "1", &change)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if repository.employee.Status != tt.change.Status || change.FromStatus != tt.from {
+				t.Errorf("ChangeStatus() moved %s to %s, want %s to %s", change.FromStatus, repository.employee.Status, tt.from, tt.change.Status)
+			}
+			if tt.change.Status == model.StatusTerminated && repository.employee.TerminationDate != "2024-03-14" {
+				t.Errorf("ChangeStatus() termination date = %q", repository.employee.TerminationDate)
+			}
+		})
+	}
+}
+
+func Test_service_Rehire(t *testing.T) {
+	repository := &lifecycleRepositories{employee: &model.Employee{
+		IdEmployee:        "1",
+		HireDate:          "2020-01-06",
+		Status:            model.StatusTerminated,
+		TerminationDate:   "2023-06-30",
+		TerminationReason: "resigned",
+	}}
+	s := service{repository: repository, now: func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) }}
+
+	if _, err := s.Rehire(context.Background(), "1", &model.Rehire{}); err != nil {
+		t.Fatalf("Rehire() error = %v", err)
+	}
+	employee := repository.employee
+	if employee.IdEmployee != "1" || employee.Status != model.StatusOnboarding || employee.HireDate != "2024-03-15" ||
+		employee.TerminationDate != "" || employee.TerminationReason != "" {
+		t.Errorf("Rehire() employee = %+v", employee)
+	}
+
+	if _, err := s.Rehire(context.Background(), "1", &model.Rehire{}); !errors.Is(err, model.ErrNotTerminated) {
+		t.Errorf("Rehire() of a current employee error = %v, want %v", err, model.ErrNotTerminated)
+	}
+}
+
+func Test_service_InsertEmployee_initialStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		employee *model.Employee
+		want     string
+		wantErr  error
+	}{
+		{name: "already started", employee: &model.Employee{IdEmployee: "1", HireDate: "2024-03-01"}, want: model.StatusActive},
+		{name: "starts later", employee: &model.Employee{IdEmployee: "2", HireDate: "2024-04-01"}, want: model.StatusOnboarding},
+		{name: "created terminated", employee: &model.Employee{IdEmployee: "3", Status: model.StatusTerminated}, wantErr: model.ErrStatusTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeEmployeeRepositories{}
+			s := service{
+				repository: repository,
+				positions:  &fakePositionRepositories{},
+				now:        func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) },
+			}
+			_, err := s.InsertEmployee(context.Background(), tt.employee)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InsertEmployee() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (tt.employee.Status != tt.want || len(repository.statuses) != 1) {
+				t.Errorf("InsertEmployee() status = %s with %d history entries, want %s", tt.employee.Status, len(repository.statuses), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"employee-golang/config"
+	"employee-golang/imaging"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"employee-golang/storage"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const photoJPEGQuality = 85
+
+type photoService struct {
+	employees      repositories.IEmployeeRepositories
+	store          storage.Store
+	maxSize        int64
+	minDimension   int
+	maxDimension   int
+	thumbnailSizes []int
+}
+
+// NewPhotoService keeps photos in the document store under their own
+// photos/ prefix.
+func NewPhotoService() IPhotoService {
+	minDimension, maxDimension := config.GetPhotoDimensions()
+	return &photoService{
+		employees:      newEmployeeRepositories(),
+		store:          repositories.NewDocumentStore(),
+		maxSize:        config.GetPhotoMaxSize(),
+		minDimension:   minDimension,
+		maxDimension:   maxDimension,
+		thumbnailSizes: config.GetPhotoThumbnailSizes(),
+	}
+}
+
+type IPhotoService interface {
+	UploadPhoto(ctx context.Context, employeeId string, content io.Reader) (rs *model.Photo, err error)
+	GetPhoto(ctx context.Context, employeeId string) (rs *model.Photo, err error)
+	OpenPhoto(ctx context.Context, photo *model.Photo, size int) (content io.ReadCloser, err error)
+	DeletePhoto(ctx context.Context, employeeId string) (rs string, err error)
+	MaxSize() int64
+}
+
+func (s photoService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadPhoto validates content as a JPEG or PNG within the configured size
+// and dimensions, stores it with its thumbnails and makes it the
+// employee's photo. The objects of a replaced photo are removed afterwards.
+func (s photoService) UploadPhoto(ctx context.Context, employeeId string, content io.Reader) (rs *model.Photo, err error) {
+	if _, err = s.employees.GetEmployeeById(ctx, employeeId); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", model.ErrPhotoTooLarge, s.maxSize)
+	}
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, model.ErrPhotoType
+	}
+	// the header is checked before decoding so oversized images are
+	// rejected without allocating their pixels
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrPhotoType, err)
+	}
+	if !s.dimensionsAllowed(cfg.Width, cfg.Height) {
+		return nil, fmt.Errorf("%w: %dx%d, each side must be between %d and %d pixels",
+			model.ErrPhotoDimensions, cfg.Width, cfg.Height, s.minDimension, s.maxDimension)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrPhotoType, err)
+	}
+
+	sum := sha256.Sum256(data)
+	rs = &model.Photo{
+		IdEmployee:  employeeId,
+		ContentType: contentType,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+	}
+	previous, err := s.employees.GetPhoto(ctx, employeeId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// re-uploading the current photo writes the same keys, which must not be
+	// removed if saving fails
+	replaced := previous == nil || previous.Checksum != rs.Checksum
+	objects := map[string][]byte{photoKey(rs, 0): data}
+	for _, size := range s.thumbnailSizes {
+		thumbnail, err := encodeImage(imaging.Fit(img, size), contentType)
+		if err != nil {
+			return nil, err
+		}
+		objects[photoKey(rs, size)] = thumbnail
+	}
+	for key, object := range objects {
+		if err = s.store.Put(ctx, key, object, contentType); err != nil {
+			logrus.Errorf("Error storing photo: %v", err)
+			if replaced {
+				s.removeObjects(ctx, rs)
+			}
+			return nil, err
+		}
+	}
+	if _, err = s.employees.SavePhoto(ctx, rs); err != nil {
+		if replaced {
+			s.removeObjects(ctx, rs)
+		}
+		return nil, err
+	}
+	if previous != nil && replaced {
+		s.removeObjects(ctx, previous)
+	}
+	return s.withUrls(rs), nil
+}
+
+func (s photoService) GetPhoto(ctx context.Context, employeeId string) (rs *model.Photo, err error) {
+	rs, err = s.employees.GetPhoto(ctx, employeeId)
+	if err != nil {
+		return nil, err
+	}
+	return s.withUrls(rs), nil
+}
+
+// OpenPhoto reads the original for size 0, or the thumbnail of a
+// configured size.
+func (s photoService) OpenPhoto(ctx context.Context, photo *model.Photo, size int) (content io.ReadCloser, err error) {
+	if size != 0 && !s.hasThumbnail(size) {
+		return nil, fmt.Errorf("%w: %d", model.ErrPhotoSize, size)
+	}
+	content, err = s.store.Get(ctx, photoKey(photo, size))
+	if err != nil {
+		logrus.Errorf("Error reading photo of employee %s: %v", photo.IdEmployee, err)
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s photoService) DeletePhoto(ctx context.Context, employeeId string) (rs string, err error) {
+	photo, err := s.employees.GetPhoto(ctx, employeeId)
+	if err != nil {
+		return "", err
+	}
+	rs, err = s.employees.DeletePhoto(ctx, employeeId)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	s.removeObjects(ctx, photo)
+	return rs, nil
+}
+
+func (s photoService) dimensionsAllowed(width, height int) bool {
+	return width >= s.minDimension && height >= s.minDimension &&
+		width <= s.maxDimension && height <= s.maxDimension
+}
+
+func (s photoService) hasThumbnail(size int) bool {
+	for _, v := range s.thumbnailSizes {
+		if v == size {
+			return true
+		}
+	}
+	return false
+}
+
+func (s photoService) withUrls(photo *model.Photo) *model.Photo {
+	photo.Url = model.PhotoURL(photo.IdEmployee, photo.Checksum, 0)
+	photo.Thumbnails = make(map[int]string, len(s.thumbnailSizes))
+	for _, size := range s.thumbnailSizes {
+		photo.Thumbnails[size] = model.PhotoURL(photo.IdEmployee, photo.Checksum, size)
+	}
+	return photo
+}
+
+// removeObjects deletes the stored original and thumbnails of photo. A
+// failure is only logged, as the objects are no longer referenced.
+func (s photoService) removeObjects(ctx context.Context, photo *model.Photo) {
+	removePhotoObjects(ctx, s.store, photo, s.thumbnailSizes)
+}
+
+func removePhotoObjects(ctx context.Context, store storage.Store, photo *model.Photo, thumbnailSizes []int) {
+	for _, size := range append([]int{0}, thumbnailSizes...) {
+		key := photoKey(photo, size)
+		if err := store.Delete(ctx, key); err != nil {
+			logrus.Errorf("Error removing photo %s: %v", key, err)
+		}
+	}
+}
+
+// photoKey addresses content by checksum, so a replacement is stored under
+// new keys instead of changing what a cached URL points at.
+func photoKey(photo *model.Photo, size int) string {
+	variant := "original"
+	if size > 0 {
+		variant = strconv.Itoa(size)
+	}
+	return "photos/" + photo.IdEmployee + "/" + photo.Checksum + "/" + variant
+}
+
+func encodeImage(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: photoJPEGQuality})
+	}
+	return buf.Bytes(), err
+}
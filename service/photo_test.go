@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"employee-golang/model"
+	"employee-golang/storage"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+type photoEmployeeRepositories struct {
+	leaveEmployeeRepositories
+	photo *model.Photo
+}
+
+func (r *photoEmployeeRepositories) GetPhoto(_ context.Context, _ string) (*model.Photo, error) {
+	if r.photo == nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.photo, nil
+}
+
+func (r *photoEmployeeRepositories) SavePhoto(_ context.Context, photo *model.Photo) (string, error) {
+	r.photo = photo
+	return "Photo was saved", nil
+}
+
+func (r *photoEmployeeRepositories) DeleteEmployee(_ context.Context, _ string, removed func([]*model.Document, *model.Photo)) (string, error) {
+	removed([]*model.Document{{StorageKey: "employees/1/contract"}}, r.photo)
+	r.photo = nil
+	return "Employee was deleted", nil
+}
+
+func encodedImage(t *testing.T, w, h int, format string) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestPhotoService(t *testing.T) (photoService, *photoEmployeeRepositories) {
+	employees := &photoEmployeeRepositories{
+		leaveEmployeeRepositories: leaveEmployeeRepositories{employee: &model.Employee{IdEmployee: "1"}},
+	}
+	return photoService{
+		employees:      employees,
+		store:          storage.NewFileStore(t.TempDir()),
+		maxSize:        1 << 20,
+		minDimension:   32,
+		maxDimension:   1024,
+		thumbnailSizes: []int{16, 64},
+	}, employees
+}
+
+func Test_photoService_UploadPhoto(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		wantType string
+		wantErr  error
+	}{
+		{name: "png", content: encodedImage(t, 200, 100, "png"), wantType: "image/png"},
+		{name: "jpeg", content: encodedImage(t, 100, 200, "jpeg"), wantType: "image/jpeg"},
+		{name: "too small", content: encodedImage(t, 200, 20, "png"), wantErr: model.ErrPhotoDimensions},
+		{name: "too big", content: encodedImage(t, 1100, 40, "jpeg"), wantErr: model.ErrPhotoDimensions},
+		{name: "not an image", content: []byte("%PDF-1.4\n"), wantErr: model.ErrPhotoType},
+		{name: "corrupt png", content: append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...), wantErr: model.ErrPhotoType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestPhotoService(t)
+			rs, err := s.UploadPhoto(context.Background(), "1", bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UploadPhoto() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rs.ContentType != tt.wantType || rs.Url == "" || len(rs.Thumbnails) != 2 {
+				t.Errorf("UploadPhoto() = %+v", rs)
+			}
+
+			// thumbnails keep the format and aspect ratio and fit their box
+			for _, size := range s.thumbnailSizes {
+				content, err := s.OpenPhoto(context.Background(), rs, size)
+				if err != nil {
+					t.Fatalf("OpenPhoto(%d) error = %v", size, err)
+				}
+				cfg, format, err := image.DecodeConfig(content)
+				content.Close()
+				if err != nil || "image/"+format != tt.wantType {
+					t.Fatalf("thumbnail %d format = %s, error = %v", size, format, err)
+				}
+				long, short := cfg.Width, cfg.Height
+				if long < short {
+					long, short = short, long
+				}
+				if long != size || short != size/2 {
+					t.Errorf("thumbnail %d is %dx%d", size, cfg.Width, cfg.Height)
+				}
+			}
+		})
+	}
+}
+
+func Test_service_DeleteEmployee_removesObjects(t *testing.T) {
+	photos, employees := newTestPhotoService(t)
+	photo, err := photos.UploadPhoto(context.Background(), "1", bytes.NewReader(encodedImage(t, 64, 64, "png")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = photos.store.Put(context.Background(), "employees/1/contract", []byte("%PDF-1.4"), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := service{repository: employees, store: photos.store, thumbnailSizes: photos.thumbnailSizes}
+	if _, err = s.DeleteEmployee(context.Background(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := photos.store.Get(context.Background(), "employees/1/contract"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("document of a deleted employee should be removed, error = %v", err)
+	}
+	for _, size := range []int{0, 16, 64} {
+		if _, err := photos.store.Get(context.Background(), photoKey(photo, size)); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("photo of size %d of a deleted employee should be removed, error = %v", size, err)
+		}
+	}
+}
+
+func Test_photoService_UploadPhoto_replaces(t *testing.T) {
+	s, employees := newTestPhotoService(t)
+	first, err := s.UploadPhoto(context.Background(), "1", bytes.NewReader(encodedImage(t, 64, 64, "png")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.UploadPhoto(context.Background(), "1", bytes.NewReader(encodedImage(t, 128, 64, "png")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if employees.photo != second || first.Url == second.Url {
+		t.Fatalf("photo was not replaced: %+v", employees.photo)
+	}
+	if _, err := s.OpenPhoto(context.Background(), first, 0); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("replaced photo should be removed, error = %v", err)
+	}
+
+	// uploading the same photo again keeps its content
+	if _, err := s.UploadPhoto(context.Background(), "1", bytes.NewReader(encodedImage(t, 128, 64, "png"))); err != nil {
+		t.Fatal(err)
+	}
+	content, err := s.OpenPhoto(context.Background(), second, 64)
+	if err != nil {
+		t.Fatalf("re-uploaded photo should be kept, error = %v", err)
+	}
+	io.Copy(io.Discard, content)
+	content.Close()
+
+	if _, err := s.OpenPhoto(context.Background(), second, 100); !errors.Is(err, model.ErrPhotoSize) {
+		t.Errorf("OpenPhoto() with an unknown size error = %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+type positionService struct {
+	repository repositories.IPositionRepositories
+}
+
+func NewPositionService() IPositionService {
+	return &positionService{
+		repository: repositories.NewPositionRepositories(),
+	}
+}
+
+type IPositionService interface {
+	GetPositions(ctx context.Context) (rs []*model.Position, err error)
+	GetPositionById(ctx context.Context, id string) (rs *model.Position, err error)
+	GetPositionsByIds(ctx context.Context, ids []string) (rs []*model.Position, err error)
+	InsertPosition(ctx context.Context, position *model.Position) (rs string, err error)
+	UpdatePosition(ctx context.Context, position *model.Position) (rs string, err error)
+	DeletePosition(ctx context.Context, id string) (rs string, err error)
+}
+
+func (s positionService) GetPositions(ctx context.Context) (rs []*model.Position, err error) {
+	rs, err = s.repository.GetPosition(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s positionService) GetPositionById(ctx context.Context, id string) (rs *model.Position, err error) {
+	rs, err = s.repository.GetPositionById(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s positionService) InsertPosition(ctx context.Context, position *model.Position) (rs string, err error) {
+	rs, err = s.repository.InsertPosition(ctx, position)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s positionService) UpdatePosition(ctx context.Context, position *model.Position) (rs string, err error) {
+	rs, err = s.repository.UpdatePosition(ctx, position)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s positionService) DeletePosition(ctx context.Context, id string) (rs string, err error) {
+	rs, err = s.repository.DeletePosition(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+func (s positionService) GetPositionsByIds(ctx context.Context, ids []string) (rs []*model.Position, err error) {
+	rs, err = s.repository.GetPositionsByIds(ctx, ids)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/events"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	maxRelayBackoff = time.Minute
+	relayPurgeEvery = time.Hour
+)
+
+// EventRelay publishes outbox events to a sink in order. It polls every
+// interval, drains full batches without waiting, and backs off
+// exponentially while the sink fails.
+type EventRelay struct {
+	outbox    repositories.IOutboxRepositories
+	sink      events.Sink
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+	now       func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewEventRelay() (*EventRelay, error) {
+	sink, err := newEventSink()
+	if err != nil {
+		return nil, err
+	}
+	return &EventRelay{
+		outbox:    repositories.NewOutboxRepositories(),
+		sink:      sink,
+		interval:  config.GetEventRelayInterval(),
+		batchSize: config.GetEventRelayBatchSize(),
+		retention: config.GetEventRetention(),
+		now:       time.Now,
+	}, nil
+}
+
+// newEventSink builds the sinks selected by configuration.
+func newEventSink() (events.Sink, error) {
+	timeout := config.GetEventSinkTimeout()
+	var sinks events.FanOut
+	for _, name := range config.GetEventSinks() {
+		switch name {
+		case "stdout":
+			sinks = append(sinks, events.NewStdoutSink())
+		case "file":
+			sink, err := events.NewFileSink(config.GetEventFilePath())
+			if err != nil {
+				sinks.Close()
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "webhook":
+			sinks = append(sinks, events.NewWebhookSink(config.GetEventWebhookURL(), timeout))
+		case "nats":
+			sink := events.NewNATSSink(config.GetNATSURL(), config.GetNATSSubject())
+			sink.Timeout = timeout
+			sinks = append(sinks, sink)
+		case "kafka":
+			sinks = append(sinks, events.NewKafkaSink(config.GetKafkaRestURL(), config.GetKafkaTopic(), timeout))
+		case "webhooks":
+			sinks = append(sinks, newSubscriptionSink())
+		default:
+			sinks.Close()
+			return nil, fmt.Errorf("unknown event sink %q", name)
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+// RelayOnce publishes one batch of pending events and returns how many
+// were published.
+func (r *EventRelay) RelayOnce(ctx context.Context) (int, error) {
+	return r.outbox.PublishPending(ctx, r.batchSize, func(ctx context.Context, event *model.Event) error {
+		return r.sink.Publish(ctx, event)
+	})
+}
+
+// Start runs the relay in the background until Close is called.
+func (r *EventRelay) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+func (r *EventRelay) run() {
+	defer close(r.done)
+	wait, backoff := time.Duration(0), r.interval
+	var purged time.Time
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.interval+config.GetEventSinkTimeout()*time.Duration(r.batchSize))
+		n, err := r.RelayOnce(ctx)
+		if now := r.now(); err == nil && now.Sub(purged) >= relayPurgeEvery {
+			if _, errPurge := r.outbox.DeletePublishedEvents(ctx, now.Add(-r.retention)); errPurge != nil {
+				logrus.Warnf("purging published events: %s", errPurge)
+			}
+			purged = now
+		}
+		cancel()
+
+		switch {
+		case err != nil:
+			logrus.Warnf("event relay published %d events, retrying in %s: %s", n, backoff, err)
+			wait = backoff
+			if backoff *= 2; backoff > maxRelayBackoff {
+				backoff = maxRelayBackoff
+			}
+		case n == r.batchSize:
+			wait, backoff = 0, r.interval
+		default:
+			wait, backoff = r.interval, r.interval
+		}
+	}
+}
+
+// Close stops the relay, waiting for a batch in flight, and closes the
+// sink.
+func (r *EventRelay) Close() error {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+	return r.sink.Close()
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/money"
+	"employee-golang/repositories"
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+type reportService struct {
+	employees repositories.IEmployeeRepositories
+	rates     func() money.Rates
+}
+
+func NewReportService() IReportService {
+	return &reportService{
+		employees: repositories.NewEmployeeRepositories(),
+		rates:     config.GetExchangeRates,
+	}
+}
+
+type IReportService interface {
+	GetSalaryReport(ctx context.Context, currency string) (rs *model.SalaryReport, err error)
+}
+
+// GetSalaryReport converts every employee's current salary into currency,
+// the configured base currency when empty, using the local exchange-rate
+// table. Employees paid in a currency without a rate are listed as
+// unconverted instead of failing the report.
+func (s reportService) GetSalaryReport(ctx context.Context, currency string) (rs *model.SalaryReport, err error) {
+	rates := s.rates()
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = rates.Base
+	}
+	// reject an unknown target up front rather than leaving every row unconverted
+	if _, err = rates.Convert(decimal.Zero, rates.Base, currency); err != nil {
+		return nil, err
+	}
+
+	employees, err := s.employees.GetEmployee(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	rs = &model.SalaryReport{
+		Currency:     currency,
+		BaseCurrency: rates.Base,
+		Total:        model.NewMoney(decimal.Zero),
+		Employees:    make([]*model.SalaryReportRow, 0, len(employees)),
+		Unconverted:  []string{},
+	}
+	for _, employee := range employees {
+		from := employee.Currency
+		if from == "" {
+			from = config.GetDefaultCurrency()
+		}
+		converted, err := rates.Convert(employee.Salary.Decimal, from, currency)
+		if errors.Is(err, money.ErrUnknownCurrency) {
+			rs.Unconverted = append(rs.Unconverted, employee.IdEmployee)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rs.Employees = append(rs.Employees, &model.SalaryReportRow{
+			IdEmployee:      employee.IdEmployee,
+			Salary:          employee.Salary,
+			Currency:        from,
+			ConvertedSalary: model.NewMoney(converted),
+		})
+		rs.Total = model.NewMoney(rs.Total.Add(converted))
+	}
+	return rs, nil
+}
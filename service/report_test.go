@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/money"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+type fakeEmployeeListRepositories struct {
+	fakeEmployeeRepositories
+	employees []*model.Employee
+}
+
+func (r *fakeEmployeeListRepositories) GetEmployee(_ context.Context) ([]*model.Employee, error) {
+	return r.employees, nil
+}
+
+func Test_reportService_GetSalaryReport(t *testing.T) {
+	s := reportService{
+		employees: &fakeEmployeeListRepositories{employees: []*model.Employee{
+			{IdEmployee: "1", Salary: model.NewMoney(decimal.RequireFromString("1000.10")), Currency: "USD"},
+			{IdEmployee: "2", Salary: model.NewMoney(decimal.RequireFromString("920")), Currency: "EUR"},
+			{IdEmployee: "3", Salary: model.NewMoney(decimal.RequireFromString("500")), Currency: "GBP"},
+		}},
+		rates: func() money.Rates {
+			return money.Rates{Base: "USD", Rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.92")}}
+		},
+	}
+
+	rs, err := s.GetSalaryReport(context.Background(), "usd")
+	if err != nil {
+		t.Fatalf("GetSalaryReport() error = %v", err)
+	}
+	if rs.Currency != "USD" || !rs.Total.Equal(decimal.RequireFromString("2000.10")) {
+		t.Errorf("GetSalaryReport() = %s %s, want 2000.10 USD", rs.Total, rs.Currency)
+	}
+	if len(rs.Employees) != 2 || len(rs.Unconverted) != 1 || rs.Unconverted[0] != "3" {
+		t.Errorf("GetSalaryReport() rows = %d, unconverted = %v", len(rs.Employees), rs.Unconverted)
+	}
+
+	if _, err := s.GetSalaryReport(context.Background(), "XYZ"); !errors.Is(err, money.ErrUnknownCurrency) {
+		t.Errorf("GetSalaryReport() error = %v, want %v", err, money.ErrUnknownCurrency)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"employee-golang/search"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const defaultSearchLimit = 20
+
+type searchService struct {
+	employees  repositories.IEmployeeRepositories
+	index      *repositories.EmployeeIndex
+	fullText   bool
+	refresh    time.Duration
+	maxResults int
+}
+
+// NewSearchService picks the search backend once, at startup.
+func NewSearchService() ISearchService {
+	s := &searchService{
+		employees:  repositories.NewEmployeeRepositories(),
+		index:      repositories.SharedEmployeeIndex(),
+		refresh:    config.GetSearchRefreshInterval(),
+		maxResults: config.GetSearchMaxResults(),
+	}
+	s.fullText = useFullText(context.Background(), s.employees, config.GetSearchBackend())
+	return s
+}
+
+type ISearchService interface {
+	SearchEmployees(ctx context.Context, query string, limit int) (rs []*model.SearchResult, err error)
+}
+
+func useFullText(ctx context.Context, employees repositories.IEmployeeRepositories, backend string) bool {
+	switch backend {
+	case "fulltext":
+		return true
+	case "memory":
+		return false
+	}
+	ok, err := employees.HasEmployeeFullText(ctx)
+	if err != nil {
+		logrus.Warnf("cannot check for the employee FULLTEXT index, searching in memory: %s", err)
+		return false
+	}
+	if !ok {
+		logrus.Info("employee FULLTEXT index not found, searching in memory")
+	}
+	return ok
+}
+
+// SearchEmployees returns up to limit employees matching query, best first,
+// with the matching fields highlighted.
+func (s searchService) SearchEmployees(ctx context.Context, query string, limit int) (rs []*model.SearchResult, err error) {
+	query = strings.TrimSpace(query)
+	if len(search.Terms(query)) == 0 {
+		return nil, model.ErrSearchQuery
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > s.maxResults {
+		limit = s.maxResults
+	}
+
+	if s.fullText {
+		rs, err = s.employees.SearchEmployees(ctx, query, limit)
+	} else {
+		err = s.index.Refresh(s.refresh, func() ([]*model.Employee, error) {
+			return s.employees.GetEmployee(ctx)
+		})
+		if err == nil {
+			rs = s.index.Search(query, limit)
+		}
+	}
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+
+	for _, r := range rs {
+		for _, f := range repositories.EmployeeSearchFields(r.Employee) {
+			if h := search.Highlight(query, f); h != "" {
+				if r.Highlights == nil {
+					r.Highlights = make(map[string]string)
+				}
+				r.Highlights[f.Name] = h
+			}
+		}
+	}
+	return rs, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type searchEmployeeRepositories struct {
+	fakeEmployeeRepositories
+	employees map[string]*model.Employee
+	loads     int
+}
+
+func (r *searchEmployeeRepositories) GetEmployee(_ context.Context) ([]*model.Employee, error) {
+	r.loads++
+	res := make([]*model.Employee, 0, len(r.employees))
+	for _, e := range r.employees {
+		copied := *e
+		res = append(res, &copied)
+	}
+	return res, nil
+}
+
+func (r *searchEmployeeRepositories) GetEmployeeById(_ context.Context, id string) (*model.Employee, error) {
+	copied := *r.employees[id]
+	return &copied, nil
+}
+
+func (r *searchEmployeeRepositories) UpdateEmployee(_ context.Context, employee *model.Employee) (string, error) {
+	r.employees[employee.IdEmployee] = employee
+	return "Employee was updated", nil
+}
+
+func (r *searchEmployeeRepositories) DeleteEmployee(_ context.Context, id string, _ func([]*model.Document, *model.Photo)) (string, error) {
+	delete(r.employees, id)
+	return "Employee was deleted", nil
+}
+
+func searchIds(rs []*model.SearchResult) []string {
+	ids := []string{}
+	for _, r := range rs {
+		ids = append(ids, r.Employee.IdEmployee)
+	}
+	return ids
+}
+
+func Test_searchService_SearchEmployees(t *testing.T) {
+	employees := &searchEmployeeRepositories{employees: map[string]*model.Employee{
+		"1": {IdEmployee: "1", FirstName: "John", LastName: "Smith", Email: "john.smith@example.com", Phone: "+1 555 0100"},
+		"2": {IdEmployee: "2", FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", Phone: "+1 555 0199"},
+	}}
+	s := searchService{
+		employees:  employees,
+		index:      repositories.NewEmployeeIndex(),
+		refresh:    time.Hour,
+		maxResults: 10,
+	}
+
+	rs, err := s.SearchEmployees(context.Background(), " jonh ", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHighlights := map[string]string{"firstName": "<em>John</em>", "email": "<em>john</em>.smith@example.com"}
+	if len(rs) != 1 || rs[0].Employee.IdEmployee != "1" || !reflect.DeepEqual(rs[0].Highlights, wantHighlights) {
+		t.Fatalf("SearchEmployees() = %+v", rs)
+	}
+
+	rs, _ = s.SearchEmployees(context.Background(), "5550199", 0)
+	if !reflect.DeepEqual(searchIds(rs), []string{"2"}) || rs[0].Highlights["phone"] != "+1 <em>555 0199</em>" {
+		t.Errorf("SearchEmployees() by phone = %+v", rs)
+	}
+
+	if _, err := s.SearchEmployees(context.Background(), " @ ", 0); !errors.Is(err, model.ErrSearchQuery) {
+		t.Errorf("SearchEmployees() without terms error = %v", err)
+	}
+
+	// writes through the indexed repositories show up without a reload
+	writer := repositories.NewIndexedEmployeeRepositories(employees, s.index)
+	writer.UpdateEmployee(context.Background(), &model.Employee{IdEmployee: "2", FirstName: "Jane", LastName: "Johnson", Email: "jane@example.com"})
+	writer.DeleteEmployee(context.Background(), "1", nil)
+	rs, _ = s.SearchEmployees(context.Background(), "john", 0)
+	if !reflect.DeepEqual(searchIds(rs), []string{"2"}) {
+		t.Errorf("SearchEmployees() after writes = %v", searchIds(rs))
+	}
+	if employees.loads != 1 {
+		t.Errorf("index was loaded %d times, want 1", employees.loads)
+	}
+}
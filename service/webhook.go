@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"employee-golang/config"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"encoding/hex"
+	"github.com/sirupsen/logrus"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+type webhookService struct {
+	repository repositories.IWebhookRepositories
+	allowHTTP  bool
+	now        func() time.Time
+}
+
+func NewWebhookService() IWebhookService {
+	return &webhookService{
+		repository: repositories.NewWebhookRepositories(),
+		allowHTTP:  config.IsWebhookHTTPAllowed(),
+		now:        time.Now,
+	}
+}
+
+type IWebhookService interface {
+	GetWebhooks(ctx context.Context) (rs []*model.WebhookSubscription, err error)
+	GetWebhookById(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error)
+	// InsertWebhook registers a subscription, generating its secret when
+	// none is given. The result is the only one carrying the secret.
+	InsertWebhook(ctx context.Context, subscription *model.WebhookSubscription) (rs *model.WebhookSubscription, err error)
+	UpdateWebhook(ctx context.Context, subscription *model.WebhookSubscription) (rs *model.WebhookSubscription, err error)
+	// RotateSecret replaces the secret with a generated one and returns
+	// the subscription carrying it.
+	RotateSecret(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error)
+	DeleteWebhook(ctx context.Context, id int64) (rs string, err error)
+	GetDeliveries(ctx context.Context, id int64, status string, limit int) (rs []*model.WebhookDelivery, err error)
+	GetDeliveryById(ctx context.Context, id, deliveryId int64) (rs *model.WebhookDelivery, err error)
+	// Redeliver sends a delivery again from its first attempt, whatever
+	// its status.
+	Redeliver(ctx context.Context, id, deliveryId int64) (rs *model.WebhookDelivery, err error)
+}
+
+func (s webhookService) GetWebhooks(ctx context.Context) (rs []*model.WebhookSubscription, err error) {
+	rs, err = s.repository.GetSubscriptions(ctx)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	for _, subscription := range rs {
+		subscription.Secret = ""
+	}
+	return rs, nil
+}
+
+func (s webhookService) GetWebhookById(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error) {
+	rs, err = s.repository.GetSubscriptionById(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	rs.Secret = ""
+	return rs, nil
+}
+
+func (s webhookService) InsertWebhook(ctx context.Context, subscription *model.WebhookSubscription) (rs *model.WebhookSubscription, err error) {
+	if err = s.validate(subscription); err != nil {
+		return nil, err
+	}
+	if subscription.Secret == "" {
+		if subscription.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = s.repository.InsertSubscription(ctx, subscription); err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	secret := subscription.Secret
+	rs, err = s.repository.GetSubscriptionById(ctx, subscription.IdSubscription)
+	if err != nil {
+		return nil, err
+	}
+	rs.Secret = secret
+	return rs, nil
+}
+
+// UpdateWebhook replaces the url, event types, description and active
+// flag. A secret in the request rotates the secret to it.
+func (s webhookService) UpdateWebhook(ctx context.Context, subscription *model.WebhookSubscription) (rs *model.WebhookSubscription, err error) {
+	if err = s.validate(subscription); err != nil {
+		return nil, err
+	}
+	if _, err = s.repository.GetSubscriptionById(ctx, subscription.IdSubscription); err != nil {
+		return nil, err
+	}
+	if _, err = s.repository.UpdateSubscription(ctx, subscription); err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	if subscription.Secret != "" {
+		if _, err = s.repository.UpdateSecret(ctx, subscription.IdSubscription, subscription.Secret); err != nil {
+			logrus.Error("Error is been occurred")
+			return nil, err
+		}
+	}
+	return s.GetWebhookById(ctx, subscription.IdSubscription)
+}
+
+func (s webhookService) RotateSecret(ctx context.Context, id int64) (rs *model.WebhookSubscription, err error) {
+	if rs, err = s.repository.GetSubscriptionById(ctx, id); err != nil {
+		return nil, err
+	}
+	if rs.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if _, err = s.repository.UpdateSecret(ctx, id, rs.Secret); err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s webhookService) DeleteWebhook(ctx context.Context, id int64) (rs string, err error) {
+	rs, err = s.repository.DeleteSubscription(ctx, id)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return "", err
+	}
+	return rs, nil
+}
+
+// GetDeliveries lists the newest deliveries of a subscription, optionally
+// only those with status.
+func (s webhookService) GetDeliveries(ctx context.Context, id int64, status string, limit int) (rs []*model.WebhookDelivery, err error) {
+	switch status {
+	case "", model.DeliveryPending, model.DeliverySucceeded, model.DeliveryDeadLettered:
+	default:
+		return nil, model.ErrDeliveryStatus
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	} else if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	if _, err = s.repository.GetSubscriptionById(ctx, id); err != nil {
+		return nil, err
+	}
+	rs, err = s.repository.GetDeliveries(ctx, id, status, limit)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s webhookService) GetDeliveryById(ctx context.Context, id, deliveryId int64) (rs *model.WebhookDelivery, err error) {
+	rs, err = s.repository.GetDeliveryById(ctx, id, deliveryId)
+	if err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (s webhookService) Redeliver(ctx context.Context, id, deliveryId int64) (rs *model.WebhookDelivery, err error) {
+	subscription, err := s.repository.GetSubscriptionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, model.ErrWebhookInactive
+	}
+	if _, err = s.repository.GetDeliveryById(ctx, id, deliveryId); err != nil {
+		return nil, err
+	}
+	if _, err = s.repository.RedeliverDelivery(ctx, id, deliveryId, s.now()); err != nil {
+		logrus.Error("Error is been occurred")
+		return nil, err
+	}
+	return s.repository.GetDeliveryById(ctx, id, deliveryId)
+}
+
+// validate checks the url and event types, dropping repeated types.
+func (s webhookService) validate(subscription *model.WebhookSubscription) error {
+	u, err := url.Parse(subscription.Url)
+	if err != nil || u.Host == "" || u.User != nil ||
+		!(u.Scheme == "https" || u.Scheme == "http" && s.allowHTTP) {
+		return model.ErrWebhookUrl
+	}
+	eventTypes := make([]string, 0, len(subscription.EventTypes))
+	seen := make(map[string]bool)
+	for _, t := range subscription.EventTypes {
+		if !isEventType(t) {
+			return model.ErrWebhookEventType
+		}
+		if !seen[t] {
+			seen[t] = true
+			eventTypes = append(eventTypes, t)
+		}
+	}
+	subscription.EventTypes = eventTypes
+	return nil
+}
+
+func isEventType(t string) bool {
+	for _, known := range model.EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
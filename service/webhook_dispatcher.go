@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"employee-golang/config"
+	"employee-golang/events"
+	"employee-golang/model"
+	"employee-golang/repositories"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const webhookPurgeEvery = time.Hour
+
+// WebhookDispatcher sends pending webhook deliveries. A failed delivery is
+// retried with exponential backoff and dead-lettered after maxAttempts
+// failures; every attempt is kept in the delivery log. Deliveries of one
+// subscription may arrive out of order, so receivers order by the event
+// sequence.
+type WebhookDispatcher struct {
+	webhooks    repositories.IWebhookRepositories
+	client      *resty.Client
+	interval    time.Duration
+	batchSize   int
+	concurrency int
+	lease       time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	retention   time.Duration
+	now         func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewWebhookDispatcher() *WebhookDispatcher {
+	backoff, maxBackoff := config.GetWebhookBackoff()
+	timeout := config.GetWebhookTimeout()
+	batchSize, concurrency := config.GetWebhookBatchSize(), config.GetWebhookConcurrency()
+	// the lease covers sending a whole batch concurrency at a time
+	rounds := (batchSize + concurrency - 1) / concurrency
+	return &WebhookDispatcher{
+		webhooks:    repositories.NewWebhookRepositories(),
+		client:      newWebhookClient(timeout, config.IsWebhookPrivateNetworkAllowed()),
+		interval:    config.GetWebhookInterval(),
+		batchSize:   batchSize,
+		concurrency: concurrency,
+		lease:       timeout*time.Duration(rounds) + time.Minute,
+		maxAttempts: config.GetWebhookMaxAttempts(),
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+		retention:   config.GetWebhookRetention(),
+		now:         time.Now,
+	}
+}
+
+// newWebhookClient builds a client that does not follow redirects and,
+// unless allowPrivate, refuses to connect to non-public addresses. The
+// check runs on the resolved address, so DNS cannot point around it.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *resty.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return model.ErrWebhookAddress
+			}
+			return nil
+		}
+	}
+	return resty.New().
+		SetTransport(&http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: timeout, MaxIdleConnsPerHost: 2}).
+		SetTimeout(timeout).
+		SetRedirectPolicy(resty.NoRedirectPolicy()).
+		SetHeader("User-Agent", "employee-golang-webhooks")
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast())
+}
+
+// DispatchOnce sends one batch of due deliveries and returns how many
+// were attempted. Failed sends are recorded on the deliveries; only
+// failures to claim or record them are returned.
+func (d *WebhookDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := d.now()
+	deliveries, err := d.webhooks.ClaimDueDeliveries(ctx, now, d.batchSize, now.Add(d.lease))
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+	)
+	sem := make(chan struct{}, d.concurrency)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := d.deliver(ctx, delivery); err != nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries), first
+}
+
+// deliver makes one attempt at delivery and records its outcome.
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) error {
+	start := d.now()
+	attempt := &model.WebhookAttempt{}
+	attempt.ResponseStatus, attempt.Error = d.send(ctx, delivery, start)
+	attempt.DurationMs = d.now().Sub(start).Milliseconds()
+
+	delivery.Attempts++
+	next := start
+	switch {
+	case attempt.Error == "":
+		delivery.Status = model.DeliverySucceeded
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = model.DeliveryDeadLettered
+		logrus.Warnf("webhook delivery %d to subscription %d dead-lettered after %d attempts: %s",
+			delivery.IdDelivery, delivery.IdSubscription, delivery.Attempts, attempt.Error)
+	default:
+		delivery.Status = model.DeliveryPending
+		next = start.Add(d.retryAfter(delivery.Attempts))
+	}
+	return d.webhooks.RecordDeliveryAttempt(ctx, delivery, attempt, start, next)
+}
+
+// send POSTs the payload, signed with the subscription secret. It returns
+// the response status and, unless the status is 2xx, the error.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *model.WebhookDelivery, at time.Time) (int, string) {
+	timestamp := at.Unix()
+	resp, err := d.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader(events.HeaderWebhookId, strconv.FormatInt(delivery.IdDelivery, 10)).
+		SetHeader(events.HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10)).
+		SetHeader(events.HeaderWebhookSignature, events.SignPayload(delivery.Secret, timestamp, delivery.Payload)).
+		SetHeader("X-Event-Id", delivery.IdEvent).
+		SetHeader("X-Event-Type", delivery.EventType).
+		SetBody([]byte(delivery.Payload)).
+		Post(delivery.Url)
+	if err != nil {
+		return 0, err.Error()
+	}
+	if !resp.IsSuccess() {
+		body := resp.Body()
+		if len(body) > 200 {
+			body = body[:200]
+		}
+		return resp.StatusCode(), fmt.Sprintf("%s: %s", resp.Status(), body)
+	}
+	return resp.StatusCode(), ""
+}
+
+// retryAfter is the wait after the given number of failed attempts.
+func (d *WebhookDispatcher) retryAfter(attempts int) time.Duration {
+	wait := d.backoff
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+// Start runs the dispatcher in the background until Close is called.
+func (d *WebhookDispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go d.run()
+}
+
+func (d *WebhookDispatcher) run() {
+	defer close(d.done)
+	wait := time.Duration(0)
+	var purged time.Time
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), d.lease)
+		n, err := d.DispatchOnce(ctx)
+		if now := d.now(); now.Sub(purged) >= webhookPurgeEvery {
+			if _, errPurge := d.webhooks.DeleteFinishedDeliveries(ctx, now.Add(-d.retention)); errPurge != nil {
+				logrus.Warnf("purging webhook deliveries: %s", errPurge)
+			}
+			purged = now
+		}
+		cancel()
+
+		switch {
+		case err != nil:
+			logrus.Warnf("webhook dispatcher: %s", err)
+			wait = d.interval
+		case n == d.batchSize:
+			wait = 0
+		default:
+			wait = d.interval
+		}
+	}
+}
+
+// Close stops the dispatcher, waiting for a batch in flight.
+func (d *WebhookDispatcher) Close() error {
+	if d.stop != nil {
+		close(d.stop)
+		<-d.done
+		d.stop = nil
+	}
+	return nil
+}
+
+// subscriptionSink is the webhooks event sink: it enqueues a delivery of
+// every event for each subscription selecting it, leaving the sending to
+// the dispatcher.
+type subscriptionSink struct {
+	webhooks repositories.IWebhookRepositories
+	now      func() time.Time
+}
+
+func newSubscriptionSink() *subscriptionSink {
+	return &subscriptionSink{
+		webhooks: repositories.NewWebhookRepositories(),
+		now:      time.Now,
+	}
+}
+
+func (s *subscriptionSink) Publish(ctx context.Context, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.webhooks.EnqueueDeliveries(ctx, event, payload, s.now())
+	return err
+}
+
+func (s *subscriptionSink) Close() error {
+	return nil
+}
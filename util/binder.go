@@ -0,0 +1,200 @@
+package util
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Binder decodes XML and MessagePack bodies as the JSON document they
+// render to in responses, so the JSON field names and rules apply to
+// every format. Other bodies go to echo's DefaultBinder, which answers
+// unsupported types with echo.ErrUnsupportedMediaType.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	var decode func(io.Reader, reflect.Type) ([]byte, error)
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMEApplicationXML), strings.HasPrefix(contentType, echo.MIMETextXML):
+		decode = xmlToJSON
+	case strings.HasPrefix(contentType, MIMEApplicationMsgpack), strings.HasPrefix(contentType, "application/x-msgpack"),
+		strings.HasPrefix(contentType, "application/vnd.msgpack"):
+		decode = msgpackToJSON
+	default:
+		return b.DefaultBinder.Bind(i, c)
+	}
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+	if c.Request().ContentLength == 0 {
+		return nil
+	}
+	body, err := decode(c.Request().Body, reflect.TypeOf(i))
+	if err != nil {
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	if err := json.Unmarshal(body, i); err != nil {
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+func msgpackToJSON(r io.Reader, _ reflect.Type) ([]byte, error) {
+	var v interface{}
+	if err := msgpack.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// element is a parsed XML element.
+type element struct {
+	name     string
+	key      string
+	text     string
+	children []*element
+}
+
+var textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// xmlToJSON reads an XML document shaped like the XML responses and
+// rewrites it as JSON, using the target type to tell numbers and booleans
+// from strings.
+func xmlToJSON(r io.Reader, t reflect.Type) ([]byte, error) {
+	root, err := parseXML(xml.NewDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeXMLValue(&buf, root, t)
+	return buf.Bytes(), nil
+}
+
+func parseXML(dec *xml.Decoder) (*element, error) {
+	var stack []*element
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, errors.New("XML body has no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &element{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "key" {
+					e.key = attr.Value
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			}
+			stack = append(stack, e)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		case xml.EndElement:
+			e := stack[len(stack)-1]
+			if stack = stack[:len(stack)-1]; len(stack) == 0 {
+				return e, nil
+			}
+		}
+	}
+}
+
+func writeXMLValue(buf *bytes.Buffer, e *element, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshaler) || reflect.PointerTo(t).Implements(textUnmarshaler) {
+		writeXMLText(buf, strings.TrimSpace(e.text))
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		buf.WriteByte('{')
+		for i, child := range e.children {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeXMLText(buf, child.name)
+			buf.WriteByte(':')
+			writeXMLValue(buf, child, structFieldType(t, child.name))
+		}
+		buf.WriteByte('}')
+	case reflect.Map:
+		buf.WriteByte('{')
+		for i, child := range e.children {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key := child.name
+			if child.key != "" {
+				key = child.key
+			}
+			writeXMLText(buf, key)
+			buf.WriteByte(':')
+			writeXMLValue(buf, child, t.Elem())
+		}
+		buf.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		buf.WriteByte('[')
+		for i, child := range e.children {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeXMLValue(buf, child, t.Elem())
+		}
+		buf.WriteByte(']')
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		text := strings.TrimSpace(e.text)
+		if json.Valid([]byte(text)) {
+			buf.WriteString(text)
+		} else {
+			// let json.Unmarshal report the type mismatch
+			writeXMLText(buf, text)
+		}
+	default:
+		writeXMLText(buf, e.text)
+	}
+}
+
+func writeXMLText(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// structFieldType is the type of the field JSON decodes name into, or
+// string for unknown fields, which JSON ignores.
+func structFieldType(t reflect.Type, name string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if jsonName == "-" || !f.IsExported() {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		if strings.EqualFold(jsonName, name) {
+			return f.Type
+		}
+	}
+	return reflect.TypeOf("")
+}
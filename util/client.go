@@ -0,0 +1,115 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net"
+	"strings"
+)
+
+type clientKey struct{}
+
+const clientIdKey = "clientId"
+
+// WithClient stores the identifier of the calling client on ctx.
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFrom returns the client identifier stored by WithClient, or "".
+func ClientFrom(ctx context.Context) string {
+	v, _ := ctx.Value(clientKey{}).(string)
+	return v
+}
+
+// ClientId identifies the caller of a request: by the API key or bearer
+// token subject the client identification middleware verified, otherwise by
+// its IP address. The
+// address comes from the server's IPExtractor, which only trusts forwarding
+// headers set by configured proxies, and from the connection itself when
+// the server has none.
+func ClientId(c echo.Context) string {
+	if v, ok := c.Get(clientIdKey).(string); ok {
+		return v
+	}
+	if c.Echo().IPExtractor == nil {
+		return "ip:" + echo.ExtractIPDirect()(c.Request())
+	}
+	return "ip:" + c.RealIP()
+}
+
+// SetClientId records the verified identity of the caller.
+func SetClientId(c echo.Context, id string) {
+	c.Set(clientIdKey, id)
+}
+
+// APIKeys is the set of API keys callers may identify with. Keys are kept
+// hashed so they are never held in memory or logs in clear text.
+type APIKeys map[string]bool
+
+func NewAPIKeys(keys []string) APIKeys {
+	res := APIKeys{}
+	for _, key := range keys {
+		if key != "" {
+			res[keyClientId(key)] = true
+		}
+	}
+	return res
+}
+
+// ClientId is the client id of key, or "" when key is not in the set.
+func (k APIKeys) ClientId(key string) string {
+	if key == "" {
+		return ""
+	}
+	if id := keyClientId(key); k[id] {
+		return id
+	}
+	return ""
+}
+
+func keyClientId(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:8])
+}
+
+// JWTSecret is the HMAC secret bearer tokens callers may identify with are
+// signed with.
+type JWTSecret []byte
+
+// ClientId is the client id of the subject of the bearer token in
+// authorization, or "" when there is no token or it is not valid: signed
+// with another secret or algorithm, expired, not valid yet or without a
+// subject. An empty secret accepts no token.
+func (s JWTSecret) ClientId(authorization string) string {
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if len(s) == 0 || token == authorization || token == "" {
+		return ""
+	}
+	claims := &jwt.StandardClaims{}
+	parser := jwt.Parser{ValidMethods: []string{"HS256", "HS384", "HS512"}}
+	_, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(s), nil
+	})
+	if err != nil || claims.Subject == "" {
+		return ""
+	}
+	return "sub:" + claims.Subject
+}
+
+// IPExtractor reads the client address from X-Forwarded-For when the
+// request comes through one of the trusted proxies, and from the connection
+// otherwise.
+func IPExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
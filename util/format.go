@@ -0,0 +1,275 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"strconv"
+)
+
+// Responses in every format are renderings of the JSON document, so they
+// keep its field names, omitted fields and value formatting.
+
+// field is a member of a JSON object, kept in document order.
+type field struct {
+	key   string
+	value any
+}
+
+// parseJSON decodes a JSON document into []field objects, []any arrays,
+// json.Number numbers, strings, bools and nil.
+func parseJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return parseValue(dec)
+}
+
+func parseValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		object := []field{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, field{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for dec.More() {
+			value, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = dec.Token()
+		return array, err
+	}
+	return tok, nil
+}
+
+// writeXML writes the document under a <response> element. Object members
+// become elements named after their keys, or <entry key="..."> when the key
+// is not an XML name, and array items become <item> elements.
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := encodeXML(enc, xml.StartElement{Name: xml.Name{Local: "response"}}, doc); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+func encodeXML(enc *xml.Encoder, start xml.StartElement, value any) error {
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case []field:
+		for _, f := range v {
+			if err := encodeXML(enc, xmlElement(f.key), f.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := encodeXML(enc, xml.StartElement{Name: xml.Name{Local: "item"}}, item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(scalarText(v))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func xmlElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		letter := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if !letter && (i == 0 || !(r == '-' || r == '.' || r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMsgpack writes objects as maps in document order, integral numbers
+// as integers and other numbers as floats.
+func writeMsgpack(w io.Writer, doc any) error {
+	return encodeMsgpack(msgpack.NewEncoder(w), doc)
+}
+
+func encodeMsgpack(enc *msgpack.Encoder, value any) error {
+	switch v := value.(type) {
+	case []field:
+		if err := enc.EncodeMapLen(len(v)); err != nil {
+			return err
+		}
+		for _, f := range v {
+			if err := enc.EncodeString(f.key); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(enc, f.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if err := enc.EncodeArrayLen(len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := encodeMsgpack(enc, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return enc.EncodeInt(n)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		return enc.EncodeFloat64(f)
+	}
+	return enc.Encode(value)
+}
+
+var errNotList = errors.New("only lists can be written as CSV")
+
+// writeCSV writes the items of the document's "data" array as rows, with a
+// column for each key found in the items. Nested values are written as
+// JSON.
+func writeCSV(w io.Writer, doc any) error {
+	var items []any
+	if object, ok := doc.([]field); ok {
+		for _, f := range object {
+			if f.key == "data" {
+				items, ok = f.value.([]any)
+				if !ok {
+					return errNotList
+				}
+			}
+		}
+	}
+	if items == nil {
+		return errNotList
+	}
+
+	var columns []string
+	index := map[string]int{}
+	for _, item := range items {
+		object, ok := item.([]field)
+		if !ok {
+			object = []field{{key: "value", value: item}}
+		}
+		for _, f := range object {
+			if _, ok := index[f.key]; !ok {
+				index[f.key] = len(columns)
+				columns = append(columns, f.key)
+			}
+		}
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for _, item := range items {
+		object, ok := item.([]field)
+		if !ok {
+			object = []field{{key: "value", value: item}}
+		}
+		row := make([]string, len(columns))
+		for _, f := range object {
+			row[index[f.key]] = csvText(f.value)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvText(value any) string {
+	switch value.(type) {
+	case []field, []any:
+		var buf bytes.Buffer
+		writeJSONValue(&buf, value)
+		return buf.String()
+	}
+	return scalarText(value)
+}
+
+func writeJSONValue(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case []field:
+		buf.WriteByte('{')
+		for i, f := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			writeJSONValue(buf, f.value)
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, item)
+		}
+		buf.WriteByte(']')
+	default:
+		b, _ := json.Marshal(v)
+		buf.Write(b)
+	}
+}
+
+func scalarText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
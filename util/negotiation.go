@@ -0,0 +1,155 @@
+package util
+
+import (
+	"io"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	MIMEApplicationMsgpack = "application/msgpack"
+	MIMETextCSV            = "text/csv"
+)
+
+// format is a response format; write is nil for JSON, which is written
+// directly rather than from the parsed document.
+type format struct {
+	contentType string
+	mediaTypes  []string
+	write       func(io.Writer, any) error
+	// list is set for formats that can only write lists.
+	list bool
+}
+
+// formats are offered in this order when the request accepts several
+// equally.
+var formats = []format{
+	{contentType: "application/json; charset=UTF-8", mediaTypes: []string{"application/json", MediaTypeV2}},
+	{contentType: "application/xml; charset=UTF-8", mediaTypes: []string{"application/xml", "text/xml"}, write: writeXML},
+	{contentType: MIMEApplicationMsgpack, mediaTypes: []string{MIMEApplicationMsgpack, "application/x-msgpack", "application/vnd.msgpack"}, write: writeMsgpack},
+	{contentType: "text/csv; charset=UTF-8", mediaTypes: []string{MIMETextCSV}, write: writeCSV, list: true},
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// acceptedFormats returns the formats the Accept header allows, most
+// preferred first. Each format takes the quality of the most specific range
+// matching it; ties go to the range listed first, then to the order of
+// formats. An empty header accepts JSON only.
+func acceptedFormats(accept string) []format {
+	if strings.TrimSpace(accept) == "" {
+		return formats[:1]
+	}
+	ranges := parseAccept(accept)
+
+	type candidate struct {
+		format format
+		q      float64
+		rank   int
+	}
+	var candidates []candidate
+	for _, f := range formats {
+		best, q, rank := -1, 0.0, 0
+		for i, r := range ranges {
+			if s := specificity(r.mediaType, f.mediaTypes); s > best {
+				best, q, rank = s, r.q, i
+			}
+		}
+		if best >= 0 && q > 0 {
+			candidates = append(candidates, candidate{format: f, q: q, rank: rank})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].rank < candidates[j].rank
+	})
+	res := make([]format, len(candidates))
+	for i, c := range candidates {
+		res[i] = c.format
+	}
+	return res
+}
+
+// parseAccept reads the media ranges of an Accept header in the order
+// listed, skipping those that do not parse.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// AcceptsV2 reports whether the Accept header asks for MediaTypeV2: JSON
+// is the preferred format and the v2 media type is listed with a quality
+// above zero and no lower than application/json's.
+func AcceptsV2(accept string) bool {
+	if accepted := acceptedFormats(accept); len(accepted) == 0 || accepted[0].write != nil {
+		return false
+	}
+	v2, json := 0.0, 0.0
+	for _, r := range parseAccept(accept) {
+		switch r.mediaType {
+		case MediaTypeV2:
+			v2 = math.Max(v2, r.q)
+		case "application/json":
+			json = math.Max(json, r.q)
+		}
+	}
+	return v2 > 0 && v2 >= json
+}
+
+// specificity is how closely a media range matches one of the media
+// types: 2 exactly, 1 by type/*, 0 by */*, and -1 not at all.
+func specificity(mediaRange string, mediaTypes []string) int {
+	best := -1
+	for _, mediaType := range mediaTypes {
+		switch {
+		case mediaRange == mediaType:
+			return 2
+		case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+			best = 1
+		case mediaRange == "*/*" && best < 0:
+			best = 0
+		}
+	}
+	return best
+}
+
+// Acceptable reports whether the Accept header allows a format responses
+// can be written in; CSV counts only for lists.
+func Acceptable(accept string, list bool) bool {
+	for _, f := range acceptedFormats(accept) {
+		if list || !f.list {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseType is the media type of the format the Accept header prefers,
+// empty when it allows none.
+func ResponseType(accept string) string {
+	if accepted := acceptedFormats(accept); len(accepted) > 0 {
+		return accepted[0].mediaTypes[0]
+	}
+	return ""
+}
@@ -1,15 +1,86 @@
 package util
 
 import (
+	"bytes"
+	"employee-golang/model"
+	"encoding/json"
 	"github.com/labstack/echo/v4"
+	"net/http"
 	"os"
+	"strings"
 )
 
+// RespJSONData writes the response body, converting the v1 envelope to the
+// v2 one for requests answered with v2. The body is written in the format
+// the Accept header prefers: JSON, XML, MessagePack, or CSV for lists.
+// When no accepted format fits, errors are still written as JSON and
+// anything else is answered with 406.
 func RespJSONData(c echo.Context, sc int, i interface{}, err error) error {
 	c.Set("completionStatus", sc)
 	c.Set("instanceId", os.Hostname)
 	if err != nil {
 		c.Set("error", err.Error())
 	}
+	addVary(c.Response().Header(), echo.HeaderAccept)
+	if rs, ok := i.(model.GenericResponse[any]); ok && APIVersion(c) == 2 {
+		i = envelope(c, sc, rs)
+	}
+
+	var doc any
+	for _, f := range acceptedFormats(c.Request().Header.Get(echo.HeaderAccept)) {
+		if f.write == nil {
+			return writeJSON(c, sc, i)
+		}
+		if doc == nil {
+			b, errMarshal := json.Marshal(i)
+			if errMarshal != nil {
+				return errMarshal
+			}
+			if doc, errMarshal = parseJSON(b); errMarshal != nil {
+				return errMarshal
+			}
+		}
+		var buf bytes.Buffer
+		if f.write(&buf, doc) == nil {
+			return c.Blob(sc, f.contentType, buf.Bytes())
+		}
+	}
+	if sc >= 400 {
+		return writeJSON(c, sc, i)
+	}
+	return NotAcceptable(c)
+}
+
+// writeJSON writes i as JSON, typed as MediaTypeV2 for requests answered
+// with v2.
+func writeJSON(c echo.Context, sc int, i interface{}) error {
+	if APIVersion(c) == 2 {
+		c.Response().Header().Set(echo.HeaderContentType, MediaTypeV2+"; charset=UTF-8")
+	}
 	return c.JSON(sc, i)
 }
+
+// NotAcceptable answers that no format the request accepts can be written.
+func NotAcceptable(c echo.Context) error {
+	c.Set("completionStatus", http.StatusNotAcceptable)
+	var rs interface{} = model.GenericResponse[any]{
+		Code:   http.StatusNotAcceptable,
+		Status: "NOT_ACCEPTABLE",
+		Data:   "Supported types are application/json, application/xml, application/msgpack and, for lists, text/csv",
+	}
+	if APIVersion(c) == 2 {
+		rs = envelope(c, http.StatusNotAcceptable, rs.(model.GenericResponse[any]))
+	}
+	return writeJSON(c, http.StatusNotAcceptable, rs)
+}
+
+func addVary(header http.Header, name string) {
+	for _, v := range header.Values(echo.HeaderVary) {
+		for _, existing := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), name) {
+				return
+			}
+		}
+	}
+	header.Add(echo.HeaderVary, name)
+}
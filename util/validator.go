@@ -0,0 +1,50 @@
+package util
+
+import (
+	"employee-golang/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"reflect"
+)
+
+// NewValidator returns a validator that compares decimal amounts exactly
+// with decimal_gte and decimal_gtefield, for money, days and hours fields.
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		switch d := field.Interface().(type) {
+		case model.Money:
+			return d.Decimal
+		case model.Quantity:
+			return d.Decimal
+		}
+		return nil
+	}, model.Money{}, model.Quantity{})
+	_ = v.RegisterValidation("decimal_gte", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		min, err := decimal.NewFromString(fl.Param())
+		if err != nil {
+			return false
+		}
+		return value.Cmp(min) >= 0
+	})
+	_ = v.RegisterValidation("decimal_gtefield", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		field, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+		if !found {
+			return false
+		}
+		other, ok := field.Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		return value.Cmp(other) >= 0
+	})
+	return v
+}
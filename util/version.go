@@ -0,0 +1,59 @@
+package util
+
+import (
+	"employee-golang/model"
+	"github.com/labstack/echo/v4"
+)
+
+// MediaTypeV2 selects v2 of the API in an Accept header on v1 paths.
+const MediaTypeV2 = "application/vnd.employee.v2+json"
+
+const (
+	apiVersionKey = "apiVersion"
+	requestIdKey  = "requestId"
+	paginationKey = "pagination"
+)
+
+// APIVersion is the API version the request was answered with, 1 unless
+// the versioning middleware selected another.
+func APIVersion(c echo.Context) int {
+	if v, ok := c.Get(apiVersionKey).(int); ok {
+		return v
+	}
+	return 1
+}
+
+func SetAPIVersion(c echo.Context, version int) {
+	c.Set(apiVersionKey, version)
+}
+
+func RequestId(c echo.Context) string {
+	v, _ := c.Get(requestIdKey).(string)
+	return v
+}
+
+func SetRequestId(c echo.Context, id string) {
+	c.Set(requestIdKey, id)
+}
+
+// SetPagination describes the page a list handler answers with, for the
+// meta of the v2 envelope.
+func SetPagination(c echo.Context, pagination *model.Pagination) {
+	c.Set(paginationKey, pagination)
+}
+
+// envelope converts a v1 response body to the v2 envelope.
+func envelope(c echo.Context, sc int, rs model.GenericResponse[any]) model.Envelope {
+	res := model.Envelope{Meta: model.EnvelopeMeta{RequestId: RequestId(c)}}
+	if sc >= 400 {
+		message, ok := rs.Data.(string)
+		if !ok {
+			message = rs.Status
+		}
+		res.Error = &model.EnvelopeError{Code: rs.Status, Message: message}
+		return res
+	}
+	res.Data = rs.Data
+	res.Meta.Pagination, _ = c.Get(paginationKey).(*model.Pagination)
+	return res
+}